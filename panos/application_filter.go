@@ -0,0 +1,436 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Data source (listing).
+func dataSourceApplicationFilters() *schema.Resource {
+	s := listingSchema()
+	s["vsys"] = vsysSchema("vsys1")
+	s["device_group"] = deviceGroupSchema()
+
+	return &schema.Resource{
+		Read: dataSourceApplicationFiltersRead,
+
+		Schema: s,
+	}
+}
+
+func dataSourceApplicationFiltersRead(d *schema.ResourceData, meta interface{}) error {
+	vsys := d.Get("vsys").(string)
+	dg := d.Get("device_group").(string)
+
+	path, id := applicationFilterXpath(meta, vsys, dg)
+
+	c := rawClient(meta)
+	listing, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	d.SetId(id)
+	saveListing(d, listing)
+	return nil
+}
+
+// Data source.
+func dataSourceApplicationFilter() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceApplicationFilterRead,
+
+		Schema: applicationFilterSchema(false),
+	}
+}
+
+func dataSourceApplicationFilterRead(d *schema.ResourceData, meta interface{}) error {
+	vsys := d.Get("vsys").(string)
+	dg := d.Get("device_group").(string)
+	name := d.Get("name").(string)
+
+	_, loc := applicationFilterXpath(meta, vsys, dg)
+	o, err := getApplicationFilter(meta, loc, name)
+	if err != nil {
+		return err
+	}
+	if o == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.SetId(buildApplicationFilterId(loc, name))
+	saveApplicationFilter(d, *o)
+
+	return nil
+}
+
+// Resource.
+func resourceApplicationFilter() *schema.Resource {
+	return &schema.Resource{
+		Create: createApplicationFilter,
+		Read:   readApplicationFilter,
+		Update: updateApplicationFilter,
+		Delete: deleteApplicationFilter,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: applicationFilterSchema(true),
+	}
+}
+
+func createApplicationFilter(d *schema.ResourceData, meta interface{}) error {
+	vsys := d.Get("vsys").(string)
+	dg := d.Get("device_group").(string)
+	o := loadApplicationFilter(d)
+
+	path, loc := applicationFilterXpath(meta, vsys, dg)
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Application filter %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildApplicationFilterId(loc, o.Name))
+	return readApplicationFilter(d, meta)
+}
+
+func readApplicationFilter(d *schema.ResourceData, meta interface{}) error {
+	loc, name := parseApplicationFilterId(d.Id())
+
+	o, err := getApplicationFilter(meta, loc, name)
+	if err != nil {
+		return err
+	}
+	if o == nil {
+		d.SetId("")
+		return nil
+	}
+
+	switch meta.(type) {
+	case *pango.Firewall:
+		d.Set("vsys", loc)
+	case *pango.Panorama:
+		d.Set("device_group", loc)
+	}
+	saveApplicationFilter(d, *o)
+
+	return nil
+}
+
+func updateApplicationFilter(d *schema.ResourceData, meta interface{}) error {
+	loc, name := parseApplicationFilterId(d.Id())
+	o := loadApplicationFilter(d)
+
+	path := applicationFilterLocXpath(meta, loc)
+
+	c := rawClient(meta)
+	if _, err := c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readApplicationFilter(d, meta)
+}
+
+func deleteApplicationFilter(d *schema.ResourceData, meta interface{}) error {
+	loc, name := parseApplicationFilterId(d.Id())
+
+	path := applicationFilterLocXpath(meta, loc)
+
+	c := rawClient(meta)
+	if _, err := c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema handling.
+func applicationFilterSchema(isResource bool) map[string]*schema.Schema {
+	ans := map[string]*schema.Schema{
+		"vsys":         vsysSchema("vsys1"),
+		"device_group": deviceGroupSchema(),
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name",
+		},
+		"categories": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Application categories",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"subcategories": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Application subcategories",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"technologies": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Application technologies",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"risks": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Application risk levels (1 - 5)",
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validateStringIn("1", "2", "3", "4", "5"),
+			},
+		},
+		"evasive": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match evasive applications",
+		},
+		"excessive_bandwidth_use": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications with excessive bandwidth use",
+		},
+		"prone_to_misuse": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications prone to misuse",
+		},
+		"is_saas": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match SaaS applications",
+		},
+		"transfers_files": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications capable of file transfer",
+		},
+		"tunnels_other_apps": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications that tunnel other applications",
+		},
+		"used_by_malware": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications used by malware",
+		},
+		"has_known_vulnerabilities": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications with known vulnerabilities",
+		},
+		"pervasive": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match widely used applications",
+		},
+		"new_app_id": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Match applications from the most recent content release",
+		},
+		"tags": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Application tags",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"excludes": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Applications to exclude from the filter",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+
+	if !isResource {
+		computed(ans, "", []string{"vsys", "device_group", "name"})
+	}
+
+	return ans
+}
+
+func loadApplicationFilter(d *schema.ResourceData) applicationFilterEntry {
+	o := applicationFilterEntry{
+		Name:                    d.Get("name").(string),
+		Categories:              util.StrToMem(setAsList(d.Get("categories").(*schema.Set))),
+		Subcategories:           util.StrToMem(setAsList(d.Get("subcategories").(*schema.Set))),
+		Technologies:            util.StrToMem(setAsList(d.Get("technologies").(*schema.Set))),
+		Risks:                   util.StrToMem(setAsList(d.Get("risks").(*schema.Set))),
+		Evasive:                 applicationFilterYes(d.Get("evasive").(bool)),
+		ExcessiveBandwidthUse:   applicationFilterYes(d.Get("excessive_bandwidth_use").(bool)),
+		ProneToMisuse:           applicationFilterYes(d.Get("prone_to_misuse").(bool)),
+		IsSaas:                  applicationFilterYes(d.Get("is_saas").(bool)),
+		TransfersFiles:          applicationFilterYes(d.Get("transfers_files").(bool)),
+		TunnelsOtherApps:        applicationFilterYes(d.Get("tunnels_other_apps").(bool)),
+		UsedByMalware:           applicationFilterYes(d.Get("used_by_malware").(bool)),
+		HasKnownVulnerabilities: applicationFilterYes(d.Get("has_known_vulnerabilities").(bool)),
+		Pervasive:               applicationFilterYes(d.Get("pervasive").(bool)),
+		NewAppId:                applicationFilterYes(d.Get("new_app_id").(bool)),
+		Excludes:                util.StrToMem(setAsList(d.Get("excludes").(*schema.Set))),
+	}
+
+	if tags := setAsList(d.Get("tags").(*schema.Set)); len(tags) != 0 {
+		o.Tagging = &applicationFilterTagging{Tags: util.StrToMem(tags)}
+	}
+
+	return o
+}
+
+func saveApplicationFilter(d *schema.ResourceData, o applicationFilterEntry) {
+	var tags []string
+	if o.Tagging != nil {
+		tags = util.MemToStr(o.Tagging.Tags)
+	}
+
+	d.Set("name", o.Name)
+	d.Set("evasive", util.AsBool(o.Evasive))
+	d.Set("excessive_bandwidth_use", util.AsBool(o.ExcessiveBandwidthUse))
+	d.Set("prone_to_misuse", util.AsBool(o.ProneToMisuse))
+	d.Set("is_saas", util.AsBool(o.IsSaas))
+	d.Set("transfers_files", util.AsBool(o.TransfersFiles))
+	d.Set("tunnels_other_apps", util.AsBool(o.TunnelsOtherApps))
+	d.Set("used_by_malware", util.AsBool(o.UsedByMalware))
+	d.Set("has_known_vulnerabilities", util.AsBool(o.HasKnownVulnerabilities))
+	d.Set("pervasive", util.AsBool(o.Pervasive))
+	d.Set("new_app_id", util.AsBool(o.NewAppId))
+
+	sets := map[string][]string{
+		"categories":    util.MemToStr(o.Categories),
+		"subcategories": util.MemToStr(o.Subcategories),
+		"technologies":  util.MemToStr(o.Technologies),
+		"risks":         util.MemToStr(o.Risks),
+		"tags":          tags,
+		"excludes":      util.MemToStr(o.Excludes),
+	}
+	for key, list := range sets {
+		if err := d.Set(key, listAsSet(list)); err != nil {
+			log.Printf("[WARN] Error setting %q for %q: %s", key, d.Id(), err)
+		}
+	}
+}
+
+// applicationFilterYes returns "yes" if v is true, otherwise it returns an
+// empty string so the characteristic is omitted from the filter.
+func applicationFilterYes(v bool) string {
+	if v {
+		return util.YesNo(v)
+	}
+
+	return ""
+}
+
+// getApplicationFilter returns the filter, or nil if it does not exist.
+func getApplicationFilter(meta interface{}, loc, name string) (*applicationFilterEntry, error) {
+	var ans applicationFilterAns
+
+	path := applicationFilterLocXpath(meta, loc)
+
+	c := rawClient(meta)
+	if _, err := c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return ans.Entry, nil
+}
+
+// Id functions.
+func buildApplicationFilterId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parseApplicationFilterId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
+
+// applicationFilterXpath returns the xpath to the application filters and
+// the vsys or device group the xpath is for.
+func applicationFilterXpath(meta interface{}, vsys, dg string) ([]string, string) {
+	loc := vsys
+	if _, ok := meta.(*pango.Panorama); ok {
+		loc = dg
+	}
+
+	return applicationFilterLocXpath(meta, loc), loc
+}
+
+func applicationFilterLocXpath(meta interface{}, loc string) []string {
+	var ans []string
+	if _, ok := meta.(*pango.Panorama); ok {
+		ans = util.DeviceGroupXpathPrefix(loc)
+	} else {
+		ans = util.VsysXpathPrefix(loc)
+	}
+
+	return append(ans, "application-filter")
+}
+
+// Config structs.
+type applicationFilterEntry struct {
+	XMLName                 xml.Name                  `xml:"entry"`
+	Name                    string                    `xml:"name,attr"`
+	Categories              *util.MemberType          `xml:"category"`
+	Subcategories           *util.MemberType          `xml:"subcategory"`
+	Technologies            *util.MemberType          `xml:"technology"`
+	Risks                   *util.MemberType          `xml:"risk"`
+	Evasive                 string                    `xml:"evasive,omitempty"`
+	ExcessiveBandwidthUse   string                    `xml:"excessive-bandwidth-use,omitempty"`
+	ProneToMisuse           string                    `xml:"prone-to-misuse,omitempty"`
+	IsSaas                  string                    `xml:"is-saas,omitempty"`
+	TransfersFiles          string                    `xml:"transfers-files,omitempty"`
+	TunnelsOtherApps        string                    `xml:"tunnels-other-apps,omitempty"`
+	UsedByMalware           string                    `xml:"used-by-malware,omitempty"`
+	HasKnownVulnerabilities string                    `xml:"has-known-vulnerabilities,omitempty"`
+	Pervasive               string                    `xml:"pervasive,omitempty"`
+	NewAppId                string                    `xml:"new-appid,omitempty"`
+	Tagging                 *applicationFilterTagging `xml:"tagging"`
+	Excludes                *util.MemberType          `xml:"exclude"`
+}
+
+type applicationFilterTagging struct {
+	Tags *util.MemberType `xml:"tag"`
+}
+
+type applicationFilterAns struct {
+	Entry *applicationFilterEntry `xml:"result>entry"`
+}
@@ -0,0 +1,137 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Data source listing tests.
+func TestAccPanosDsApplicationFilterList(t *testing.T) {
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsApplicationFilterConfig(name),
+				Check:  checkDataSourceListing("panos_application_filters"),
+			},
+		},
+	})
+}
+
+// Data source tests.
+func TestAccPanosDsApplicationFilter_basic(t *testing.T) {
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsApplicationFilterConfig(name),
+				Check: checkDataSource("panos_application_filter", []string{
+					"name", "evasive", "transfers_files",
+					"categories.#", "risks.#",
+				}),
+			},
+		},
+	})
+}
+
+func testAccDsApplicationFilterConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_application_filters" "test" {}
+
+data "panos_application_filter" "test" {
+    name = panos_application_filter.x.name
+}
+
+resource "panos_application_filter" "x" {
+    name = %q
+    categories = ["networking"]
+    risks = ["4", "5"]
+    evasive = true
+    transfers_files = true
+}
+`, name)
+}
+
+// Resource tests.
+func TestAccPanosApplicationFilter_basic(t *testing.T) {
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosApplicationFilterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccApplicationFilterConfig(name, "networking", "5", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_application_filter.test", "name", name),
+					resource.TestCheckResourceAttr("panos_application_filter.test", "categories.#", "1"),
+					resource.TestCheckResourceAttr("panos_application_filter.test", "risks.#", "1"),
+					resource.TestCheckResourceAttr("panos_application_filter.test", "tunnels_other_apps", "true"),
+				),
+			},
+			{
+				Config: testAccApplicationFilterConfig(name, "collaboration", "3", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_application_filter.test", "name", name),
+					resource.TestCheckResourceAttr("panos_application_filter.test", "categories.#", "1"),
+					resource.TestCheckResourceAttr("panos_application_filter.test", "risks.#", "1"),
+					resource.TestCheckResourceAttr("panos_application_filter.test", "tunnels_other_apps", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosApplicationFilterDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_application_filter" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			loc, name := parseApplicationFilterId(rs.Primary.ID)
+			o, err := getApplicationFilter(testAccProvider.Meta(), loc, name)
+			if err != nil {
+				return err
+			}
+			if o != nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccApplicationFilterConfig(name, category, risk string, tunnels bool) string {
+	var loc string
+	if _, ok := testAccProvider.Meta().(*pango.Panorama); ok {
+		loc = `device_group = "shared"`
+	} else {
+		loc = `vsys = "vsys1"`
+	}
+
+	return fmt.Sprintf(`
+resource "panos_application_filter" "test" {
+    %s
+    name = %q
+    categories = [%q]
+    risks = [%q]
+    tunnels_other_apps = %t
+}
+`, loc, name, category, risk, tunnels)
+}
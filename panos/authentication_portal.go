@@ -0,0 +1,430 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Valid values for the authentication portal mode.
+const (
+	authenticationPortalTransparent = "transparent"
+	authenticationPortalRedirect    = "redirect"
+)
+
+// Resource (authentication portal).
+func resourceAuthenticationPortal() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdateAuthenticationPortal,
+		Read:   readAuthenticationPortal,
+		Update: createUpdateAuthenticationPortal,
+		Delete: deleteAuthenticationPortal,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("vsys1"),
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Enable the authentication portal",
+			},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      authenticationPortalTransparent,
+				Description:  "The portal mode",
+				ValidateFunc: validation.StringInSlice([]string{authenticationPortalTransparent, authenticationPortalRedirect}, false),
+			},
+			"redirect_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(redirect mode) The intranet hostname that resolves to the portal interface",
+			},
+			"idle_timer": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      15,
+				Description:  "Idle timeout, in minutes",
+				ValidateFunc: validation.IntBetween(1, 1440),
+			},
+			"timer": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				Description:  "Session timeout, in minutes",
+				ValidateFunc: validation.IntBetween(1, 1440),
+			},
+			"gp_udp_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The UDP port for GlobalProtect authentication prompts",
+				ValidateFunc: validation.IntBetween(0, 65535),
+			},
+			"ssl_tls_service_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The SSL/TLS service profile for redirect mode",
+			},
+			"authentication_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The default authentication profile",
+			},
+			"certificate_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The certificate profile to authenticate users with",
+			},
+		},
+	}
+}
+
+func createUpdateAuthenticationPortal(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadAuthenticationPortal(d)
+
+	path, err := authenticationPortalXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildAuthenticationPortalId(tmpl, ts, vsys))
+	return readAuthenticationPortal(d, meta)
+}
+
+func readAuthenticationPortal(d *schema.ResourceData, meta interface{}) error {
+	var ans authenticationPortalAns
+
+	tmpl, ts, vsys := parseAuthenticationPortalId(d.Id())
+
+	path, err := authenticationPortalXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Config == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveAuthenticationPortal(d, *ans.Config)
+
+	return nil
+}
+
+func deleteAuthenticationPortal(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys := parseAuthenticationPortalId(d.Id())
+
+	path, err := authenticationPortalXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(path, nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Resource (authentication enforcement).
+func resourceAuthenticationEnforcement() *schema.Resource {
+	return &schema.Resource{
+		Create: createAuthenticationEnforcement,
+		Read:   readAuthenticationEnforcement,
+		Update: updateAuthenticationEnforcement,
+		Delete: deleteAuthenticationEnforcement,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("vsys1"),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name",
+			},
+			"authentication_method": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "web-form",
+				Description:  "The authentication method",
+				ValidateFunc: validation.StringInSlice([]string{"web-form", "browser-challenge", "no-captive-portal"}, false),
+			},
+			"authentication_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The authentication profile",
+			},
+			"message": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The message shown to users",
+			},
+		},
+	}
+}
+
+func createAuthenticationEnforcement(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadAuthenticationEnforcement(d)
+
+	path, err := authenticationEnforcementXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Authentication enforcement %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildAuthenticationEnforcementId(tmpl, ts, vsys, o.Name))
+	return readAuthenticationEnforcement(d, meta)
+}
+
+func readAuthenticationEnforcement(d *schema.ResourceData, meta interface{}) error {
+	var ans authenticationEnforcementAns
+
+	tmpl, ts, vsys, name := parseAuthenticationEnforcementId(d.Id())
+
+	path, err := authenticationEnforcementXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveAuthenticationEnforcement(d, *ans.Entry)
+
+	return nil
+}
+
+func updateAuthenticationEnforcement(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseAuthenticationEnforcementId(d.Id())
+	o := loadAuthenticationEnforcement(d)
+
+	path, err := authenticationEnforcementXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readAuthenticationEnforcement(d, meta)
+}
+
+func deleteAuthenticationEnforcement(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseAuthenticationEnforcementId(d.Id())
+
+	path, err := authenticationEnforcementXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadAuthenticationPortal(d *schema.ResourceData) authenticationPortalConfig {
+	o := authenticationPortalConfig{
+		Enabled:               util.YesNo(d.Get("enabled").(bool)),
+		RedirectHost:          d.Get("redirect_host").(string),
+		IdleTimer:             d.Get("idle_timer").(int),
+		Timer:                 d.Get("timer").(int),
+		GpUdpPort:             d.Get("gp_udp_port").(int),
+		SslTlsServiceProfile:  d.Get("ssl_tls_service_profile").(string),
+		AuthenticationProfile: d.Get("authentication_profile").(string),
+		CertificateProfile:    d.Get("certificate_profile").(string),
+	}
+
+	s := ""
+	switch d.Get("mode").(string) {
+	case authenticationPortalTransparent:
+		o.Mode.Transparent = &s
+	case authenticationPortalRedirect:
+		o.Mode.Redirect = &s
+	}
+
+	return o
+}
+
+func saveAuthenticationPortal(d *schema.ResourceData, o authenticationPortalConfig) {
+	mode := authenticationPortalTransparent
+	if o.Mode.Redirect != nil {
+		mode = authenticationPortalRedirect
+	}
+
+	d.Set("enabled", util.AsBool(o.Enabled))
+	d.Set("mode", mode)
+	d.Set("redirect_host", o.RedirectHost)
+	d.Set("idle_timer", o.IdleTimer)
+	d.Set("timer", o.Timer)
+	d.Set("gp_udp_port", o.GpUdpPort)
+	d.Set("ssl_tls_service_profile", o.SslTlsServiceProfile)
+	d.Set("authentication_profile", o.AuthenticationProfile)
+	d.Set("certificate_profile", o.CertificateProfile)
+}
+
+func loadAuthenticationEnforcement(d *schema.ResourceData) authenticationEnforcementEntry {
+	return authenticationEnforcementEntry{
+		Name:                  d.Get("name").(string),
+		AuthenticationMethod:  d.Get("authentication_method").(string),
+		AuthenticationProfile: d.Get("authentication_profile").(string),
+		Message:               d.Get("message").(string),
+	}
+}
+
+func saveAuthenticationEnforcement(d *schema.ResourceData, o authenticationEnforcementEntry) {
+	d.Set("name", o.Name)
+	d.Set("authentication_method", o.AuthenticationMethod)
+	d.Set("authentication_profile", o.AuthenticationProfile)
+	d.Set("message", o.Message)
+}
+
+// Id functions.
+func buildAuthenticationPortalId(a, b, c string) string {
+	return strings.Join([]string{a, b, c}, IdSeparator)
+}
+
+func parseAuthenticationPortalId(v string) (string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2]
+}
+
+func buildAuthenticationEnforcementId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseAuthenticationEnforcementId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func authenticationPortalXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "captive-portal"), nil
+}
+
+func authenticationEnforcementXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "authentication-object"), nil
+}
+
+// Config structs.
+type authenticationPortalConfig struct {
+	XMLName               xml.Name                 `xml:"captive-portal"`
+	Enabled               string                   `xml:"enable-captive-portal"`
+	Mode                  authenticationPortalMode `xml:"mode"`
+	RedirectHost          string                   `xml:"redirect-host,omitempty"`
+	IdleTimer             int                      `xml:"idle-timer,omitempty"`
+	Timer                 int                      `xml:"timer,omitempty"`
+	GpUdpPort             int                      `xml:"gp-udp-port,omitempty"`
+	SslTlsServiceProfile  string                   `xml:"ssl-tls-service-profile,omitempty"`
+	AuthenticationProfile string                   `xml:"authentication-profile,omitempty"`
+	CertificateProfile    string                   `xml:"certificate-profile,omitempty"`
+}
+
+type authenticationPortalMode struct {
+	Transparent *string `xml:"transparent"`
+	Redirect    *string `xml:"redirect"`
+}
+
+type authenticationPortalAns struct {
+	Config *authenticationPortalConfig `xml:"result>captive-portal"`
+}
+
+type authenticationEnforcementEntry struct {
+	XMLName               xml.Name `xml:"entry"`
+	Name                  string   `xml:"name,attr"`
+	AuthenticationMethod  string   `xml:"authentication-method,omitempty"`
+	AuthenticationProfile string   `xml:"authentication-profile,omitempty"`
+	Message               string   `xml:"message,omitempty"`
+}
+
+type authenticationEnforcementAns struct {
+	Entry *authenticationEnforcementEntry `xml:"result>entry"`
+}
@@ -0,0 +1,89 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosAuthenticationPortal(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosAuthenticationEnforcementDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAuthenticationPortalConfig(name, 30, "web-form"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_authentication_portal.test", "idle_timer", "30"),
+					resource.TestCheckResourceAttr("panos_authentication_portal.test", "mode", "transparent"),
+					resource.TestCheckResourceAttr("panos_authentication_enforcement.test", "authentication_method", "web-form"),
+				),
+			},
+			{
+				Config: testAccAuthenticationPortalConfig(name, 45, "browser-challenge"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_authentication_portal.test", "idle_timer", "45"),
+					resource.TestCheckResourceAttr("panos_authentication_enforcement.test", "authentication_method", "browser-challenge"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosAuthenticationEnforcementDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_authentication_enforcement" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, vsys, name := parseAuthenticationEnforcementId(rs.Primary.ID)
+			path, err := authenticationEnforcementXpath(testAccProvider.Meta(), tmpl, ts, vsys)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccAuthenticationPortalConfig(name string, idle int, method string) string {
+	return fmt.Sprintf(`
+resource "panos_authentication_profile" "test" {
+    name = %q
+    type {
+        local_database = true
+    }
+}
+
+resource "panos_authentication_portal" "test" {
+    idle_timer = %d
+    authentication_profile = panos_authentication_profile.test.name
+}
+
+resource "panos_authentication_enforcement" "test" {
+    name = %q
+    authentication_method = %q
+    authentication_profile = panos_authentication_profile.test.name
+    message = "Please log in"
+}
+`, name, idle, name, method)
+}
@@ -0,0 +1,198 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceAuthenticationSequence() *schema.Resource {
+	return &schema.Resource{
+		Create: createAuthenticationSequence,
+		Read:   readAuthenticationSequence,
+		Update: updateAuthenticationSequence,
+		Delete: deleteAuthenticationSequence,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("shared"),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name",
+			},
+			"authentication_profiles": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Ordered list of authentication profiles",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"use_domain_to_determine_profile": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Use the domain of the username to determine the authentication profile",
+			},
+		},
+	}
+}
+
+func createAuthenticationSequence(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadAuthenticationSequence(d)
+
+	path, err := authenticationSequenceXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Authentication sequence %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildAuthenticationSequenceId(tmpl, ts, vsys, o.Name))
+	return readAuthenticationSequence(d, meta)
+}
+
+func readAuthenticationSequence(d *schema.ResourceData, meta interface{}) error {
+	var ans authenticationSequenceAns
+
+	tmpl, ts, vsys, name := parseAuthenticationSequenceId(d.Id())
+
+	path, err := authenticationSequenceXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveAuthenticationSequence(d, *ans.Entry)
+
+	return nil
+}
+
+func updateAuthenticationSequence(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseAuthenticationSequenceId(d.Id())
+	o := loadAuthenticationSequence(d)
+
+	path, err := authenticationSequenceXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readAuthenticationSequence(d, meta)
+}
+
+func deleteAuthenticationSequence(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseAuthenticationSequenceId(d.Id())
+
+	path, err := authenticationSequenceXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadAuthenticationSequence(d *schema.ResourceData) authenticationSequenceEntry {
+	return authenticationSequenceEntry{
+		Name:                        d.Get("name").(string),
+		AuthenticationProfiles:      util.StrToMem(asStringList(d.Get("authentication_profiles").([]interface{}))),
+		UseDomainToDetermineProfile: util.YesNo(d.Get("use_domain_to_determine_profile").(bool)),
+	}
+}
+
+func saveAuthenticationSequence(d *schema.ResourceData, o authenticationSequenceEntry) {
+	d.Set("name", o.Name)
+	if err := d.Set("authentication_profiles", util.MemToStr(o.AuthenticationProfiles)); err != nil {
+		log.Printf("[WARN] Error setting 'authentication_profiles' for %q: %s", d.Id(), err)
+	}
+	d.Set("use_domain_to_determine_profile", o.UseDomainToDetermineProfile == "" || util.AsBool(o.UseDomainToDetermineProfile))
+}
+
+// Id functions.
+func buildAuthenticationSequenceId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseAuthenticationSequenceId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func authenticationSequenceXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "authentication-sequence"), nil
+}
+
+// Config structs.
+type authenticationSequenceEntry struct {
+	XMLName                     xml.Name         `xml:"entry"`
+	Name                        string           `xml:"name,attr"`
+	AuthenticationProfiles      *util.MemberType `xml:"authentication-profiles"`
+	UseDomainToDetermineProfile string           `xml:"use-domain-find-profile"`
+}
+
+type authenticationSequenceAns struct {
+	Entry *authenticationSequenceEntry `xml:"result>entry"`
+}
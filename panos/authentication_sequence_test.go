@@ -0,0 +1,95 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosAuthenticationSequence(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosAuthenticationSequenceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAuthenticationSequenceConfig(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_authentication_sequence.test", "authentication_profiles.#", "2"),
+					resource.TestCheckResourceAttr("panos_authentication_sequence.test", "authentication_profiles.0", name+"a"),
+					resource.TestCheckResourceAttr("panos_authentication_sequence.test", "use_domain_to_determine_profile", "true"),
+				),
+			},
+			{
+				Config: testAccAuthenticationSequenceConfig(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_authentication_sequence.test", "authentication_profiles.0", name+"b"),
+					resource.TestCheckResourceAttr("panos_authentication_sequence.test", "use_domain_to_determine_profile", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosAuthenticationSequenceDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_authentication_sequence" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, vsys, name := parseAuthenticationSequenceId(rs.Primary.ID)
+			path, err := authenticationSequenceXpath(testAccProvider.Meta(), tmpl, ts, vsys)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccAuthenticationSequenceConfig(name string, first bool) string {
+	order := "panos_authentication_profile.a.name, panos_authentication_profile.b.name"
+	if !first {
+		order = "panos_authentication_profile.b.name, panos_authentication_profile.a.name"
+	}
+
+	return fmt.Sprintf(`
+resource "panos_authentication_profile" "a" {
+    name = "%sa"
+    type {
+        local_database = true
+    }
+}
+
+resource "panos_authentication_profile" "b" {
+    name = "%sb"
+    type {
+        local_database = true
+    }
+}
+
+resource "panos_authentication_sequence" "test" {
+    name = %q
+    authentication_profiles = [%s]
+    use_domain_to_determine_profile = %t
+}
+`, name, name, name, order, first)
+}
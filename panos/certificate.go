@@ -0,0 +1,681 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	cert "github.com/fpluchorg/pango/dev/certificate"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Data source (listing).
+func dataSourceCertificates() *schema.Resource {
+	s := listingSchema()
+	s["vsys"] = vsysSchema("shared")
+	s["template"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The template.",
+	}
+	s["expires_within_days"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Description:  "Only return certificates that expire within this many days (already expired certificates included).",
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+	s["certificates"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Certificate expiry information",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"common_name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"issuer": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"ca": {
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+				"not_valid_after": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"expiry_epoch": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"days_remaining": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"status": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read: dataSourceCertificatesRead,
+
+		Schema: s,
+	}
+}
+
+func dataSourceCertificatesRead(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var list []cert.Entry
+
+	tmpl := d.Get("template").(string)
+	vsys := d.Get("vsys").(string)
+	within := d.Get("expires_within_days").(int)
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		list, err = con.Device.Certificate.GetAll(vsys)
+	case *pango.Panorama:
+		list, err = con.Device.Certificate.GetAll(false, tmpl, vsys)
+	}
+
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	now := time.Now()
+	names := make([]string, 0, len(list))
+	info := make([]interface{}, 0, len(list))
+	for _, o := range list {
+		// CSRs and other entries without an expiry only get filtered out
+		// when filtering on expiry.
+		days, err := certificateDaysRemaining(o.ExpiryEpoch, now)
+		if within > 0 {
+			if err != nil {
+				log.Printf("[WARN] Skipping certificate %q: %s", o.Name, err)
+				continue
+			} else if days > within {
+				continue
+			}
+		}
+		names = append(names, o.Name)
+		info = append(info, map[string]interface{}{
+			"name":            o.Name,
+			"common_name":     o.CommonName,
+			"issuer":          o.Issuer,
+			"ca":              o.Ca,
+			"not_valid_after": o.NotValidAfter,
+			"expiry_epoch":    o.ExpiryEpoch,
+			"days_remaining":  days,
+			"status":          o.Status,
+		})
+	}
+
+	d.SetId(buildCertificateImportId(tmpl, vsys, ""))
+	saveListing(d, names)
+	if err = d.Set("certificates", info); err != nil {
+		log.Printf("[WARN] Error setting 'certificates' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// Resource.
+func resourceCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: createCertificate,
+		Read:   readCertificate,
+		Update: updateCertificate,
+		Delete: deleteCertificate,
+
+		CustomizeDiff: certificateDiff,
+
+		Schema: certificateSchema(),
+	}
+}
+
+func createCertificate(d *schema.ResourceData, meta interface{}) error {
+	var err error
+
+	vsys := d.Get("vsys").(string)
+	o := loadCertificateGenerate(d)
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		err = generateCertificate(&con.Client, vsys, o)
+	case *pango.Panorama:
+		err = generateCertificate(&con.Client, "", o)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(buildCertificateId(vsys, o.Name))
+	if o.Algorithm.Ecdsa != nil {
+		d.Set("key_size", o.Algorithm.Ecdsa.Bits)
+	} else {
+		d.Set("key_size", o.Algorithm.Rsa.Bits)
+	}
+
+	if signed := d.Get("signed_certificate").(string); signed != "" {
+		if err = importSignedCertificate(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return readCertificate(d, meta)
+}
+
+func readCertificate(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var o cert.Entry
+
+	vsys, name := parseCertificateId(d.Id())
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		o, err = con.Device.Certificate.Get(vsys, name)
+	case *pango.Panorama:
+		o, err = con.Device.Certificate.Get(false, "", "", name)
+	}
+
+	if err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vsys", vsys)
+	d.Set("name", o.Name)
+	saveCertificate(d, o)
+
+	return nil
+}
+
+func updateCertificate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChange("signed_certificate") && d.Get("signed_certificate").(string) != "" {
+		if err := importSignedCertificate(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return readCertificate(d, meta)
+}
+
+func deleteCertificate(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var o cert.Entry
+
+	vsys, name := parseCertificateId(d.Id())
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		o, err = con.Device.Certificate.Get(vsys, name)
+	case *pango.Panorama:
+		o, err = con.Device.Certificate.Get(false, "", "", name)
+	}
+
+	// A renewal with create_before_destroy has already replaced this
+	// certificate, so leave the replacement alone.
+	if err == nil && o.PublicKey == d.Get("public_key").(string) {
+		switch con := meta.(type) {
+		case *pango.Firewall:
+			err = con.Device.Certificate.Delete(vsys, name)
+		case *pango.Panorama:
+			err = con.Device.Certificate.Delete(false, "", "", name)
+		}
+	}
+
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// importSignedCertificate imports an externally signed certificate, pairing
+// it with the private key that was generated alongside the CSR.
+func importSignedCertificate(d *schema.ResourceData, meta interface{}) error {
+	var err error
+
+	vsys, name := parseCertificateId(d.Id())
+	data := cert.Pem{
+		Name:                name,
+		Certificate:         d.Get("signed_certificate").(string),
+		CertificateFilename: "cert.pem",
+	}
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		err = con.Device.Certificate.ImportPem(vsys, 0, data)
+	case *pango.Panorama:
+		err = con.Device.Certificate.ImportPem("", "", 0, data)
+	}
+
+	return err
+}
+
+// Schema functions.
+func certificateSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"vsys": vsysSchema("shared"),
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The certificate name.",
+			ForceNew:    true,
+		},
+		"common_name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The common name.",
+			ForceNew:    true,
+		},
+		"signed_by": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The signing CA certificate; leave empty for self-signed, or 'external' to generate a CSR.",
+			ForceNew:    true,
+		},
+		"ca": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Generate a certificate authority.",
+			ForceNew:    true,
+		},
+		"algorithm": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The key algorithm.",
+			Default:      "RSA",
+			ForceNew:     true,
+			ValidateFunc: validateStringIn("RSA", "ECDSA"),
+		},
+		"key_size": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Computed:    true,
+			Description: "The number of bits in the key; defaults to 2048 for RSA and 256 for ECDSA.",
+			ForceNew:    true,
+		},
+		"digest": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The digest algorithm.",
+			Default:     "sha256",
+			ForceNew:    true,
+			ValidateFunc: validateStringIn(
+				"md5", "sha1", "sha256", "sha384", "sha512",
+			),
+		},
+		"days_till_expiry": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Number of days the certificate is valid for.",
+			Default:      365,
+			ForceNew:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"country_code": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject country code.",
+			ForceNew:    true,
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject state.",
+			ForceNew:    true,
+		},
+		"locality": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject locality.",
+			ForceNew:    true,
+		},
+		"organization": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject organization.",
+			ForceNew:    true,
+		},
+		"organization_unit": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject organization unit.",
+			ForceNew:    true,
+		},
+		"email": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Subject email address.",
+			ForceNew:    true,
+		},
+		"hostnames": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Subject alternative name DNS entries.",
+			ForceNew:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"ip_addresses": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Subject alternative name IP address entries.",
+			ForceNew:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"alt_emails": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Subject alternative name email entries.",
+			ForceNew:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"signed_certificate": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "PEM certificate signed by an external CA from the CSR, to be imported onto the generated key.",
+		},
+		"renew_before_days": renewBeforeDaysSchema(),
+
+		// Attributes.
+		"not_valid_after": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"not_valid_before": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"expiry_epoch": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"subject": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"subject_hash": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"issuer": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"issuer_hash": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"csr": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"public_key": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"private_key_on_hsm": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"revoke_date_epoch": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func loadCertificateGenerate(d *schema.ResourceData) certGenerate {
+	o := certGenerate{
+		Name:             d.Get("name").(string),
+		CommonName:       d.Get("common_name").(string),
+		SignedBy:         d.Get("signed_by").(string),
+		Digest:           d.Get("digest").(string),
+		DaysTillExpiry:   d.Get("days_till_expiry").(int),
+		CountryCode:      d.Get("country_code").(string),
+		State:            d.Get("state").(string),
+		Locality:         d.Get("locality").(string),
+		Organization:     d.Get("organization").(string),
+		OrganizationUnit: d.Get("organization_unit").(string),
+		Email:            d.Get("email").(string),
+		Hostnames:        util.StrToMem(asStringList(d.Get("hostnames").([]interface{}))),
+		IpAddresses:      util.StrToMem(asStringList(d.Get("ip_addresses").([]interface{}))),
+		AltEmails:        util.StrToMem(asStringList(d.Get("alt_emails").([]interface{}))),
+	}
+
+	if d.Get("ca").(bool) {
+		o.Ca = util.YesNo(true)
+	}
+
+	algorithm := d.Get("algorithm").(string)
+	bits := d.Get("key_size").(int)
+	if bits == 0 {
+		bits = certificateKeySizes[algorithm][0]
+	}
+
+	switch algorithm {
+	case "ECDSA":
+		o.Algorithm.Ecdsa = &certGenerateEcdsa{Bits: bits}
+	default:
+		o.Algorithm.Rsa = &certGenerateRsa{Bits: bits}
+	}
+
+	return o
+}
+
+// certificateKeySizes are the valid key sizes for each algorithm, with the
+// default first.
+var certificateKeySizes = map[string][]int{
+	"RSA":   {2048, 512, 1024, 3072, 4096},
+	"ECDSA": {256, 384},
+}
+
+// certificateDiff validates the key size for the algorithm and forces
+// renewal of expiring certificates.
+func certificateDiff(d *schema.ResourceDiff, meta interface{}) error {
+	algorithm := d.Get("algorithm").(string)
+	if v, ok := d.GetOk("key_size"); ok && d.NewValueKnown("key_size") {
+		valid := false
+		for _, x := range certificateKeySizes[algorithm] {
+			if x == v.(int) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("key_size %d is not valid for %s, must be one of %v", v.(int), algorithm, certificateKeySizes[algorithm])
+		}
+	}
+
+	return certificateRenewalDiff(d, meta)
+}
+
+func saveCertificate(d *schema.ResourceData, o cert.Entry) {
+	d.Set("common_name", o.CommonName)
+	d.Set("ca", o.Ca)
+	if o.Algorithm != "" {
+		if strings.HasPrefix(strings.ToUpper(o.Algorithm), "EC") {
+			d.Set("algorithm", "ECDSA")
+		} else {
+			d.Set("algorithm", "RSA")
+		}
+	}
+	if o.Subject != "" {
+		subject := parseCertificateSubject(o.Subject)
+		d.Set("country_code", subject["C"])
+		d.Set("state", subject["ST"])
+		d.Set("locality", subject["L"])
+		d.Set("organization", subject["O"])
+		d.Set("organization_unit", subject["OU"])
+		d.Set("email", subject["emailAddress"])
+	}
+	d.Set("not_valid_after", o.NotValidAfter)
+	d.Set("not_valid_before", o.NotValidBefore)
+	d.Set("expiry_epoch", o.ExpiryEpoch)
+	d.Set("subject", o.Subject)
+	d.Set("subject_hash", o.SubjectHash)
+	d.Set("issuer", o.Issuer)
+	d.Set("issuer_hash", o.IssuerHash)
+	d.Set("csr", o.Csr)
+	d.Set("public_key", o.PublicKey)
+	d.Set("private_key_on_hsm", o.PrivateKeyOnHsm)
+	d.Set("status", o.Status)
+	d.Set("revoke_date_epoch", o.RevokeDateEpoch)
+}
+
+func renewBeforeDaysSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Description:  "Replace the certificate when it expires within this many days.",
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+}
+
+// certificateRenewalDiff forces replacement of a certificate resource once
+// its expiry is within renew_before_days.
+func certificateRenewalDiff(d *schema.ResourceDiff, meta interface{}) error {
+	limit := d.Get("renew_before_days").(int)
+	epoch := d.Get("expiry_epoch").(string)
+
+	if d.Id() == "" || limit == 0 || epoch == "" {
+		return nil
+	}
+
+	days, err := certificateDaysRemaining(epoch, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if days > limit {
+		return nil
+	}
+
+	log.Printf("[INFO] Certificate %q expires in %d day(s), forcing renewal", d.Id(), days)
+	if err = d.SetNewComputed("expiry_epoch"); err != nil {
+		return err
+	}
+	return d.ForceNew("expiry_epoch")
+}
+
+// certificateDaysRemaining returns the number of whole days between now and
+// the given expiry epoch; expired certificates return a negative number.
+func certificateDaysRemaining(epoch string, now time.Time) (int, error) {
+	v, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid expiry epoch %q: %s", epoch, err)
+	}
+
+	left := time.Unix(v, 0).Sub(now)
+	days := int(left / (24 * time.Hour))
+	if left < 0 {
+		days--
+	}
+
+	return days, nil
+}
+
+// parseCertificateSubject parses a subject such as "/C=US/O=Example/CN=foo"
+// into its components.
+func parseCertificateSubject(v string) map[string]string {
+	ans := make(map[string]string)
+
+	for _, part := range strings.Split(v, "/") {
+		if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+			ans[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	return ans
+}
+
+// Id functions.
+func buildCertificateId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parseCertificateId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
+
+// Operational commands.
+type certGenerateReq struct {
+	XMLName xml.Name     `xml:"request"`
+	Cmd     certGenerate `xml:"certificate>generate"`
+}
+
+type certGenerate struct {
+	Name             string                `xml:"certificate-name"`
+	CommonName       string                `xml:"name"`
+	Algorithm        certGenerateAlgorithm `xml:"algorithm"`
+	Digest           string                `xml:"digest,omitempty"`
+	DaysTillExpiry   int                   `xml:"days-till-expiry,omitempty"`
+	Ca               string                `xml:"ca,omitempty"`
+	SignedBy         string                `xml:"signed-by,omitempty"`
+	CountryCode      string                `xml:"country-code,omitempty"`
+	State            string                `xml:"state,omitempty"`
+	Locality         string                `xml:"locality,omitempty"`
+	Organization     string                `xml:"organization,omitempty"`
+	OrganizationUnit string                `xml:"organization-unit,omitempty"`
+	Email            string                `xml:"email,omitempty"`
+	Hostnames        *util.MemberType      `xml:"hostname"`
+	IpAddresses      *util.MemberType      `xml:"ip"`
+	AltEmails        *util.MemberType      `xml:"alt-email"`
+}
+
+type certGenerateAlgorithm struct {
+	Rsa   *certGenerateRsa   `xml:"RSA"`
+	Ecdsa *certGenerateEcdsa `xml:"ECDSA"`
+}
+
+type certGenerateRsa struct {
+	Bits int `xml:"rsa-nbits"`
+}
+
+type certGenerateEcdsa struct {
+	Bits int `xml:"ecdsa-nbits"`
+}
+
+func generateCertificate(c *pango.Client, vsys string, o certGenerate) error {
+	if vsys == "shared" {
+		vsys = ""
+	}
+
+	c.LogOp("(op) generating certificate: %s", o.Name)
+	_, err := c.Op(certGenerateReq{Cmd: o}, vsys, nil, nil)
+	return err
+}
@@ -3,6 +3,7 @@ package panos
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/fpluchorg/pango"
 	cert "github.com/fpluchorg/pango/dev/certificate"
@@ -293,6 +294,10 @@ func certificateImportSchema() map[string]*schema.Schema {
 			Type:     schema.TypeString,
 			Computed: true,
 		},
+		"days_until_expiry": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
 		"subject": {
 			Type:     schema.TypeString,
 			Computed: true,
@@ -365,6 +370,11 @@ func saveCertificateImport(d *schema.ResourceData, o cert.Entry) {
 	d.Set("not_valid_after", o.NotValidAfter)
 	d.Set("not_valid_before", o.NotValidBefore)
 	d.Set("expiry_epoch", o.ExpiryEpoch)
+	if days, err := certificateDaysRemaining(o.ExpiryEpoch, time.Now()); err == nil {
+		d.Set("days_until_expiry", days)
+	} else {
+		d.Set("days_until_expiry", 0)
+	}
 	d.Set("subject", o.Subject)
 	d.Set("subject_hash", o.SubjectHash)
 	d.Set("issuer", o.Issuer)
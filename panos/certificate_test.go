@@ -0,0 +1,178 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+	cert "github.com/fpluchorg/pango/dev/certificate"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Data source (listing) test.
+func TestAccPanosDsCertificates(t *testing.T) {
+	name := fmt.Sprintf("tfroot%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsCertificatesConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					checkDataSourceListing("panos_certificates"),
+					resource.TestCheckResourceAttr("data.panos_certificates.test", "total", "1"),
+					resource.TestCheckResourceAttr("data.panos_certificates.test", "listing.0", name),
+				),
+			},
+		},
+	})
+}
+
+func testAccDsCertificatesConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_certificates" "test" {
+    expires_within_days = 2
+    depends_on = [panos_certificate.x]
+}
+
+resource "panos_certificate" "x" {
+    name = %q
+    common_name = "tf acctest expiring"
+    days_till_expiry = 1
+}
+`, name)
+}
+
+func TestParseCertificateSubject(t *testing.T) {
+	ans := parseCertificateSubject("/C=US/ST=CA/L=Santa Clara/O=Example/OU=IT/CN=foo.example.com/emailAddress=a@example.com")
+
+	expected := map[string]string{
+		"C":            "US",
+		"ST":           "CA",
+		"L":            "Santa Clara",
+		"O":            "Example",
+		"OU":           "IT",
+		"CN":           "foo.example.com",
+		"emailAddress": "a@example.com",
+	}
+	for k, v := range expected {
+		if ans[k] != v {
+			t.Errorf("%s is %q, not %q", k, ans[k], v)
+		}
+	}
+}
+
+// Resource test.
+func TestAccPanosCertificate(t *testing.T) {
+	var o cert.Entry
+	root := fmt.Sprintf("tfroot%s", acctest.RandString(6))
+	leaf := fmt.Sprintf("tfleaf%s", acctest.RandString(6))
+	csr := fmt.Sprintf("tfcsr%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosCertificateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertificateConfig(root, leaf, csr),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosCertificateExists("panos_certificate.root", &o),
+					testAccCheckPanosCertificateExists("panos_certificate.leaf", &o),
+					resource.TestCheckResourceAttrSet("panos_certificate.leaf", "public_key"),
+					resource.TestCheckResourceAttr("panos_certificate.leaf", "key_size", "256"),
+					resource.TestCheckResourceAttr("panos_certificate.csr", "organization", "acctest"),
+					resource.TestCheckResourceAttrSet("panos_certificate.csr", "csr"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPanosCertificateExists(n string, o *cert.Entry) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("Object label ID is not set")
+		}
+
+		var err error
+		var v cert.Entry
+
+		vsys, name := parseCertificateId(rs.Primary.ID)
+		switch con := testAccProvider.Meta().(type) {
+		case *pango.Firewall:
+			v, err = con.Device.Certificate.Get(vsys, name)
+		case *pango.Panorama:
+			v, err = con.Device.Certificate.Get(false, "", "", name)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Error in get: %s", err)
+		}
+
+		*o = v
+
+		return nil
+	}
+}
+
+func testAccPanosCertificateDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_certificate" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			var err error
+
+			vsys, name := parseCertificateId(rs.Primary.ID)
+			switch con := testAccProvider.Meta().(type) {
+			case *pango.Firewall:
+				_, err = con.Device.Certificate.Get(vsys, name)
+			case *pango.Panorama:
+				_, err = con.Device.Certificate.Get(false, "", "", name)
+			}
+			if err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccCertificateConfig(root, leaf, csr string) string {
+	return fmt.Sprintf(`
+resource "panos_certificate" "root" {
+    name = %q
+    common_name = "tf acctest root"
+    ca = true
+}
+
+resource "panos_certificate" "leaf" {
+    name = %q
+    common_name = "leaf.example.com"
+    signed_by = panos_certificate.root.name
+    algorithm = "ECDSA"
+    hostnames = ["leaf.example.com"]
+    ip_addresses = ["10.1.1.1"]
+}
+
+resource "panos_certificate" "csr" {
+    name = %q
+    common_name = "csr.example.com"
+    signed_by = "external"
+    organization = "acctest"
+}
+`, root, leaf, csr)
+}
@@ -0,0 +1,408 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Data source.
+func dataSourceConfigSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSourceConfigSnapshot,
+
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Default:       "running",
+				Description:   "The config to retrieve",
+				ValidateFunc:  validation.StringInSlice([]string{"running", "candidate"}, false),
+				ConflictsWith: []string{"name"},
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The name of a saved config snapshot to retrieve instead",
+				ConflictsWith: []string{"source"},
+			},
+			"config": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The config XML",
+			},
+		},
+	}
+}
+
+func readDataSourceConfigSnapshot(d *schema.ResourceData, meta interface{}) error {
+	var id string
+
+	c := rawClient(meta)
+	source := d.Get("source").(string)
+	name := d.Get("name").(string)
+
+	if name != "" {
+		id = name
+	} else {
+		id = source
+	}
+
+	data, err := getConfigXml(c, source, name)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(id)
+	d.Set("source", source)
+	d.Set("name", name)
+	d.Set("config", data)
+
+	return nil
+}
+
+// Resource.
+func resourceConfigSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: createConfigSnapshot,
+		Read:   readConfigSnapshot,
+		Delete: deleteConfigSnapshot,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The filename to save the config to on the device",
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "candidate",
+				Description:  "The config to save",
+				ValidateFunc: validation.StringInSlice([]string{"candidate", "running"}, false),
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that cause the snapshot to be saved again when changed",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			// Local save variables.
+			"save_to_file_system": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Also save the config XML to the local file system",
+			},
+			"file_system_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The local directory to save the config XML to",
+			},
+			"filename": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The local file the config XML was saved to",
+			},
+		},
+	}
+}
+
+func createConfigSnapshot(d *schema.ResourceData, meta interface{}) error {
+	var err error
+
+	c := rawClient(meta)
+	name := d.Get("name").(string)
+	source := d.Get("source").(string)
+	localSave := d.Get("save_to_file_system").(bool)
+	p := d.Get("file_system_path").(string)
+
+	switch source {
+	case "candidate":
+		c.LogOp("(op) save config to %s", name)
+		_, err = c.Op(saveConfigReq{To: name}, "", nil, nil)
+	case "running":
+		// Running config can only be saved by importing it as a named config.
+		var data string
+		if data, err = getConfigXml(c, "running", ""); err != nil {
+			return err
+		}
+		c.LogImport("(import) running config to %s", name)
+		_, err = c.Import("configuration", data, name, "file", d.Timeout(schema.TimeoutCreate), nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(name)
+
+	if localSave {
+		var data string
+		if data, err = getConfigXml(c, "", name); err != nil {
+			return err
+		}
+
+		path := filepath.Join(p, name)
+		if err = ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			return err
+		}
+		d.Set("filename", path)
+	} else {
+		d.Set("filename", "")
+	}
+
+	return readConfigSnapshot(d, meta)
+}
+
+func readConfigSnapshot(d *schema.ResourceData, meta interface{}) error {
+	c := rawClient(meta)
+	name := d.Id()
+
+	list, err := listSavedConfigs(c)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, x := range list {
+		if x == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", name)
+
+	return nil
+}
+
+func deleteConfigSnapshot(d *schema.ResourceData, meta interface{}) error {
+	c := rawClient(meta)
+	name := d.Id()
+
+	c.LogOp("(op) delete config saved %s", name)
+	if _, err := c.Op(deleteSavedConfigReq{Name: name}, "", nil, nil); err != nil {
+		if !isSavedConfigNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Resource.
+func resourceConfigRollback() *schema.Resource {
+	return &schema.Resource{
+		Create: createConfigRollback,
+		Read:   readConfigRollback,
+		Delete: deleteConfigRollback,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "The name of a saved config snapshot to load",
+				ConflictsWith: []string{"version"},
+			},
+			"version": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ForceNew:      true,
+				Description:   "The running config version to load",
+				ConflictsWith: []string{"name"},
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that cause the config to be loaded again when changed",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func createConfigRollback(d *schema.ResourceData, meta interface{}) error {
+	var id string
+	var req loadConfigReq
+
+	c := rawClient(meta)
+	name := d.Get("name").(string)
+	ver := d.Get("version").(int)
+
+	switch {
+	case name != "":
+		req.From = name
+		id = name
+		c.LogOp("(op) load config from %s", name)
+	case ver > 0:
+		req.Version = ver
+		id = fmt.Sprintf("%d", ver)
+		c.LogOp("(op) load config version %d", ver)
+	default:
+		return fmt.Errorf("One of name or version must be specified")
+	}
+
+	if _, err := c.Op(req, "", nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(id)
+	return nil
+}
+
+func readConfigRollback(d *schema.ResourceData, meta interface{}) error {
+	// Nothing to read, loading a config is a one time action.
+	return nil
+}
+
+func deleteConfigRollback(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
+
+// getConfigXml returns the config XML for the given source (running or
+// candidate) or for the named saved config.
+func getConfigXml(c *pango.Client, source, name string) (string, error) {
+	var ans configXmlAns
+	var req showConfigReq
+
+	switch {
+	case name != "":
+		req.Saved = &name
+		c.LogOp("(op) show config saved %s", name)
+	case source == "candidate":
+		req.Candidate = &emptyString
+		c.LogOp("(op) show config candidate")
+	default:
+		req.Running = &emptyString
+		c.LogOp("(op) show config running")
+	}
+
+	if _, err := c.Op(req, "", nil, &ans); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(ans.Result.Data), nil
+}
+
+// listSavedConfigs returns the names of the saved config snapshots.
+//
+// This uses the CLI completions for "show config saved", which is a cheap
+// listing compared to retrieving a snapshot's config.
+func listSavedConfigs(c *pango.Client) ([]string, error) {
+	var ans savedConfigCompletionsAns
+
+	data := url.Values{}
+	data.Set("type", "op")
+	data.Set("action", "complete")
+	data.Set("xpath", "/operations/show/config/saved")
+	if c.Target != "" {
+		data.Set("target", c.Target)
+	}
+
+	c.LogOp("(op) complete show config saved")
+	if _, _, err := c.Communicate(data, &ans); err != nil {
+		return nil, err
+	}
+
+	return ans.names(), nil
+}
+
+// isSavedConfigNotFound returns if the error is PAN-OS saying that a saved
+// config snapshot doesn't exist.
+//
+// This is not always reported as an object not found error, as snapshots are
+// files instead of config.
+func isSavedConfigNotFound(err error) bool {
+	if isObjectNotFound(err) {
+		return true
+	}
+
+	e, ok := err.(errors.Panos)
+	if !ok {
+		return false
+	}
+	msg := strings.ToLower(e.Msg)
+
+	return strings.Contains(msg, "does not exist") || strings.Contains(msg, "no such file")
+}
+
+type savedConfigCompletionsAns struct {
+	Completions []savedConfigCompletion `xml:"completions>completion"`
+}
+
+type savedConfigCompletion struct {
+	Value string `xml:"value,attr"`
+}
+
+func (o savedConfigCompletionsAns) names() []string {
+	ans := make([]string, 0, len(o.Completions))
+	for _, x := range o.Completions {
+		if x.Value != "" {
+			ans = append(ans, x.Value)
+		}
+	}
+
+	return ans
+}
+
+// Op structs.
+type showConfigReq struct {
+	XMLName   xml.Name `xml:"show"`
+	Running   *string  `xml:"config>running"`
+	Candidate *string  `xml:"config>candidate"`
+	Saved     *string  `xml:"config>saved"`
+}
+
+type configXmlAns struct {
+	Result configXmlResult `xml:"result"`
+}
+
+type configXmlResult struct {
+	Data string `xml:",innerxml"`
+}
+
+type saveConfigReq struct {
+	XMLName xml.Name `xml:"save"`
+	To      string   `xml:"config>to"`
+}
+
+type deleteSavedConfigReq struct {
+	XMLName xml.Name `xml:"delete"`
+	Name    string   `xml:"config>saved"`
+}
+
+type loadConfigReq struct {
+	XMLName xml.Name `xml:"load"`
+	From    string   `xml:"config>from,omitempty"`
+	Version int      `xml:"config>version,omitempty"`
+}
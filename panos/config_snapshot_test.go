@@ -0,0 +1,109 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/fpluchorg/pango/errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosConfigSnapshot(t *testing.T) {
+	name := fmt.Sprintf("tf%s.xml", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosConfigSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfigSnapshotConfig(name, "candidate"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_config_snapshot.test", "name", name),
+					resource.TestCheckResourceAttrSet("data.panos_config_snapshot.test", "config"),
+				),
+			},
+			{
+				Config: testAccConfigSnapshotConfig(name, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_config_snapshot.test", "source", "running"),
+					resource.TestCheckResourceAttrSet("data.panos_config_snapshot.test", "config"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosConfigSnapshotDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_config_snapshot" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			list, err := listSavedConfigs(rawClient(testAccProvider.Meta()))
+			if err != nil {
+				return err
+			}
+			for _, x := range list {
+				if x == rs.Primary.ID {
+					return fmt.Errorf("Config snapshot %q still exists", rs.Primary.ID)
+				}
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func TestSavedConfigCompletions(t *testing.T) {
+	var ans savedConfigCompletionsAns
+
+	data := `<response status="success"><completions><completion value="running-config.xml" help-string="2026/10/19 10:00:00 12345"/><completion value="tfabc.xml" help-string="2026/10/19 11:00:00 23456"/></completions></response>`
+	if err := xml.Unmarshal([]byte(data), &ans); err != nil {
+		t.Fatalf("Error in unmarshal: %s", err)
+	}
+
+	expected := []string{"running-config.xml", "tfabc.xml"}
+	if names := ans.names(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %#v, got %#v", expected, names)
+	}
+}
+
+func TestIsSavedConfigNotFound(t *testing.T) {
+	testCases := []struct {
+		err error
+		ans bool
+	}{
+		{errors.ObjectNotFound(), true},
+		{errors.Panos{Msg: "tfabc.xml does not exist", Code: 17}, true},
+		{errors.Panos{Msg: "cp: cannot stat: No such file or directory", Code: 1}, true},
+		{errors.Panos{Msg: "Invalid syntax", Code: 17}, false},
+		{fmt.Errorf("tfabc.xml does not exist"), false},
+	}
+
+	for _, tc := range testCases {
+		if ans := isSavedConfigNotFound(tc.err); ans != tc.ans {
+			t.Errorf("%q: expected %t, got %t", tc.err, tc.ans, ans)
+		}
+	}
+}
+
+func testAccConfigSnapshotConfig(name, source string) string {
+	return fmt.Sprintf(`
+data "panos_config_snapshot" "test" {
+    name = panos_config_snapshot.test.name
+}
+
+resource "panos_config_snapshot" "test" {
+    name = %q
+    source = %q
+}
+`, name, source)
+}
@@ -0,0 +1,309 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	ContentUpdateTypeAppsAndThreats = "content"
+	ContentUpdateTypeAntivirus      = "anti-virus"
+	ContentUpdateTypeWildfire       = "wildfire"
+	ContentUpdateTypeUrl            = "url"
+)
+
+// Resource.
+func resourceContentUpdate() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdateContentUpdate,
+		Read:   readContentUpdate,
+		Update: createUpdateContentUpdate,
+		Delete: deleteContentUpdate,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The content type",
+				ValidateFunc: validation.StringInSlice(
+					[]string{
+						ContentUpdateTypeAppsAndThreats,
+						ContentUpdateTypeAntivirus,
+						ContentUpdateTypeWildfire,
+						ContentUpdateTypeUrl,
+					},
+					false,
+				),
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "latest",
+				Description: "The content version, or latest",
+			},
+			"install": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Install the content after it is downloaded",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(type=url) The PAN-DB region to download the seed database for",
+			},
+			"installed_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The content version currently installed",
+			},
+		},
+	}
+}
+
+func createUpdateContentUpdate(d *schema.ResourceData, meta interface{}) error {
+	var timeout time.Duration
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	} else {
+		timeout = d.Timeout(schema.TimeoutUpdate)
+	}
+	deadline := time.Now().Add(timeout)
+
+	c := rawClient(meta)
+	typ := d.Get("type").(string)
+	target := d.Get("version").(string)
+	install := d.Get("install").(bool)
+	region := d.Get("region").(string)
+
+	if typ == ContentUpdateTypeUrl {
+		if region == "" {
+			return fmt.Errorf("region must be specified for type %q", typ)
+		}
+		req := urlDownloadReq{Region: region}
+		if err := runOpJob(c, req, fmt.Sprintf("request url-filtering download paloaltonetworks region %s", region), deadline); err != nil {
+			return err
+		}
+
+		d.SetId(systemOpsId(c))
+		return readContentUpdate(d, meta)
+	} else if region != "" {
+		return fmt.Errorf("region is only valid for type %q", ContentUpdateTypeUrl)
+	}
+
+	list, err := contentUpdateOp(c, typ, contentUpgradeCmd{Check: &emptyString})
+	if err != nil {
+		return err
+	}
+
+	latest := latestContentVersion(list)
+	if latest == nil {
+		return fmt.Errorf("No %s updates are available to this device", typ)
+	}
+
+	var entry *contentUpdateEntry
+	if target == "latest" {
+		entry = latest
+	} else {
+		for i := range list {
+			if list[i].Version == target {
+				entry = &list[i]
+				break
+			}
+		}
+		if entry == nil {
+			return fmt.Errorf("Content version %q is not available for %s", target, typ)
+		}
+	}
+
+	// Only the latest content can be downloaded.
+	if entry.Downloaded != "yes" {
+		if entry.Version != latest.Version {
+			return fmt.Errorf("Content version %q is not downloaded, and only the latest version (%s) can be downloaded", entry.Version, latest.Version)
+		}
+		req := contentUpgradeReq{Cmd: contentUpgradeCmd{
+			XMLName:        xml.Name{Local: typ},
+			DownloadLatest: &emptyString,
+		}}
+		if err = runOpJob(c, req, fmt.Sprintf("request %s upgrade download latest", typ), deadline); err != nil {
+			return err
+		}
+	}
+
+	if install && entry.Current != "yes" {
+		req := contentUpgradeReq{Cmd: contentUpgradeCmd{
+			XMLName: xml.Name{Local: typ},
+			Install: &entry.Version,
+		}}
+		if err = runOpJob(c, req, fmt.Sprintf("request %s upgrade install version %s", typ, entry.Version), deadline); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(systemOpsId(c))
+	return readContentUpdate(d, meta)
+}
+
+func readContentUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := rawClient(meta)
+	typ := d.Get("type").(string)
+
+	// The URL seed database is not versioned.
+	if typ == ContentUpdateTypeUrl {
+		d.Set("installed_version", "")
+		return nil
+	}
+
+	list, err := contentUpdateOp(c, typ, contentUpgradeCmd{Info: &emptyString})
+	if err != nil {
+		return err
+	}
+
+	var installed string
+	for _, x := range list {
+		if x.Current == "yes" {
+			installed = x.Version
+			break
+		}
+	}
+	d.Set("installed_version", installed)
+
+	// Report drift only if the content should be installed.  For "latest",
+	// this checks the update server for newer content.
+	if !d.Get("install").(bool) {
+		return nil
+	}
+	target := d.Get("version").(string)
+	var latest string
+	if target == "latest" {
+		check, err := contentUpdateOp(c, typ, contentUpgradeCmd{Check: &emptyString})
+		if err != nil {
+			return err
+		}
+		if x := latestContentVersion(check); x != nil {
+			latest = x.Version
+		}
+	}
+	if !contentUpdateInSync(target, installed, latest) {
+		d.Set("version", installed)
+	}
+
+	return nil
+}
+
+// contentUpdateInSync returns if the installed content version matches the
+// target, where a target of "latest" is the latest version available.
+func contentUpdateInSync(target, installed, latest string) bool {
+	if target == "latest" {
+		return latest == "" || installed == latest
+	}
+
+	return installed == target
+}
+
+func deleteContentUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Content can't be uninstalled, so just remove it from the state.
+	d.SetId("")
+	return nil
+}
+
+// contentUpdateOp runs a check or info command for the given content type.
+func contentUpdateOp(c *pango.Client, typ string, cmd contentUpgradeCmd) ([]contentUpdateEntry, error) {
+	var ans contentUpdateAns
+
+	cmd.XMLName = xml.Name{Local: typ}
+	if cmd.Check != nil {
+		c.LogOp("(op) request %s upgrade check", typ)
+	} else {
+		c.LogOp("(op) request %s upgrade info", typ)
+	}
+	if _, err := c.Op(contentUpgradeReq{Cmd: cmd}, "", nil, &ans); err != nil {
+		return nil, err
+	}
+
+	return ans.Entries, nil
+}
+
+// latestContentVersion returns the newest entry in the list.
+func latestContentVersion(list []contentUpdateEntry) *contentUpdateEntry {
+	var ans *contentUpdateEntry
+
+	for i := range list {
+		if ans == nil || compareContentVersions(list[i].Version, ans.Version) > 0 {
+			ans = &list[i]
+		}
+	}
+
+	return ans
+}
+
+// compareContentVersions compares content versions such as "8795-8476",
+// returning -1, 0, or 1.
+func compareContentVersions(a, b string) int {
+	ap := strings.Split(a, "-")
+	bp := strings.Split(b, "-")
+
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		x, _ := strconv.Atoi(ap[i])
+		y, _ := strconv.Atoi(bp[i])
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+	}
+
+	switch {
+	case len(ap) < len(bp):
+		return -1
+	case len(ap) > len(bp):
+		return 1
+	}
+
+	return 0
+}
+
+var emptyString string
+
+// Op structs.
+type contentUpgradeReq struct {
+	XMLName xml.Name `xml:"request"`
+	Cmd     contentUpgradeCmd
+}
+
+type contentUpgradeCmd struct {
+	XMLName        xml.Name
+	Check          *string `xml:"upgrade>check"`
+	Info           *string `xml:"upgrade>info"`
+	DownloadLatest *string `xml:"upgrade>download>latest"`
+	Install        *string `xml:"upgrade>install>version"`
+}
+
+type contentUpdateAns struct {
+	Entries []contentUpdateEntry `xml:"result>content-updates>entry"`
+}
+
+type contentUpdateEntry struct {
+	Version    string `xml:"version"`
+	Downloaded string `xml:"downloaded"`
+	Current    string `xml:"current"`
+}
+
+type urlDownloadReq struct {
+	XMLName xml.Name `xml:"request"`
+	Region  string   `xml:"url-filtering>download>paloaltonetworks>region"`
+}
@@ -0,0 +1,102 @@
+package panos
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosContentUpdate(t *testing.T) {
+	// This acctest requires that the device be licensed for and able to
+	// reach the update server.
+	if os.Getenv("PANOS_CONTENT_UPDATE") == "" {
+		t.Skip("Env PANOS_CONTENT_UPDATE must be specified to run this acc test")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContentUpdateConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_content_update.test", "type", "content"),
+					resource.TestCheckResourceAttr("panos_content_update.test", "version", "latest"),
+					resource.TestCheckResourceAttrSet("panos_content_update.test", "installed_version"),
+				),
+			},
+		},
+	})
+}
+
+func TestContentUpdateInSync(t *testing.T) {
+	testCases := []struct {
+		target    string
+		installed string
+		latest    string
+		ans       bool
+	}{
+		{"latest", "8795-8476", "8795-8476", true},
+		{"latest", "8794-8470", "8795-8476", false},
+		{"latest", "", "8795-8476", false},
+		{"latest", "8795-8476", "", true},
+		{"8794-8470", "8794-8470", "8795-8476", true},
+		{"8794-8470", "8795-8476", "", false},
+	}
+
+	for _, tc := range testCases {
+		if ans := contentUpdateInSync(tc.target, tc.installed, tc.latest); ans != tc.ans {
+			t.Errorf("target:%q installed:%q latest:%q: expected %t, got %t", tc.target, tc.installed, tc.latest, tc.ans, ans)
+		}
+	}
+}
+
+func testAccContentUpdateConfig() string {
+	return `
+resource "panos_content_update" "test" {
+    type = "content"
+}
+`
+}
+
+func TestCompareContentVersions(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		ans  int
+	}{
+		{"8795-8476", "8795-8476", 0},
+		{"8795-8476", "8796-8480", -1},
+		{"8800-8500", "8796-8480", 1},
+		{"8795-8476", "8795-8477", -1},
+		{"8795", "8795-8476", -1},
+		{"4471-4958", "4471", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+" vs "+tc.b, func(t *testing.T) {
+			if ans := compareContentVersions(tc.a, tc.b); ans != tc.ans {
+				t.Errorf("Expected %d, got %d", tc.ans, ans)
+			}
+		})
+	}
+}
+
+func TestLatestContentVersion(t *testing.T) {
+	if ans := latestContentVersion(nil); ans != nil {
+		t.Fatalf("Expected nil, got %#v", ans)
+	}
+
+	list := []contentUpdateEntry{
+		{Version: "8795-8476", Current: "yes"},
+		{Version: "8810-8590"},
+		{Version: "8801-8530", Downloaded: "yes"},
+	}
+
+	ans := latestContentVersion(list)
+	if ans == nil {
+		t.Fatalf("Expected an entry, got nil")
+	} else if ans.Version != "8810-8590" {
+		t.Errorf("Expected 8810-8590, got %s", ans.Version)
+	}
+}
@@ -0,0 +1,88 @@
+package panos
+
+import (
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/custom/spyware"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// customSpywareSignature is the custom spyware signature type.
+var customSpywareSignature = customThreatSignatureType{
+	getList: func(meta interface{}, loc string) ([]string, error) {
+		return customSpywareNamespace(meta).GetList(loc)
+	},
+	get: func(meta interface{}, loc, name string) (interface{}, error) {
+		return customSpywareNamespace(meta).Get(loc, name)
+	},
+	set: func(meta interface{}, loc string, o interface{}) error {
+		return customSpywareNamespace(meta).Set(loc, o.(spyware.Entry))
+	},
+	edit: func(meta interface{}, loc string, o interface{}) error {
+		ns := customSpywareNamespace(meta)
+		e := o.(spyware.Entry)
+		lo, err := ns.Get(loc, e.Name)
+		if err != nil {
+			return err
+		}
+		lo.Copy(e)
+		return ns.Edit(loc, lo)
+	},
+	remove: func(meta interface{}, loc, name string) error {
+		return customSpywareNamespace(meta).Delete(loc, name)
+	},
+	load: func(d *schema.ResourceData) interface{} {
+		return loadCustomThreatSignature(d)
+	},
+	save: func(d *schema.ResourceData, o interface{}) {
+		saveCustomThreatSignature(d, o.(spyware.Entry))
+	},
+}
+
+// customSpywareNs is the part of pango's custom spyware namespace that is
+// the same for NGFW and Panorama.
+type customSpywareNs interface {
+	GetList(string) ([]string, error)
+	Get(string, string) (spyware.Entry, error)
+	Set(string, ...spyware.Entry) error
+	Edit(string, spyware.Entry) error
+	Delete(string, ...interface{}) error
+}
+
+func customSpywareNamespace(meta interface{}) customSpywareNs {
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		return con.Objects.CustomSpyware
+	case *pango.Panorama:
+		return con.Objects.CustomSpyware
+	}
+
+	return nil
+}
+
+// Data source (listing).
+func dataSourceCustomSpywareSignatures() *schema.Resource {
+	s := listingSchema()
+	s["vsys"] = vsysSchema("vsys1")
+	s["device_group"] = deviceGroupSchema()
+
+	return &schema.Resource{
+		Read: customSpywareSignature.dataSourceListingRead,
+
+		Schema: s,
+	}
+}
+
+// Data source.
+func dataSourceCustomSpywareSignature() *schema.Resource {
+	return &schema.Resource{
+		Read: customSpywareSignature.dataSourceRead,
+
+		Schema: customThreatSignatureSchema(false),
+	}
+}
+
+// Resource.
+func resourceCustomSpywareSignature() *schema.Resource {
+	return customSpywareSignature.resource(customThreatSignatureSchema(true))
+}
@@ -0,0 +1,229 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/custom/spyware"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Data source listing tests.
+func TestAccPanosDsCustomSpywareSignatureList(t *testing.T) {
+	name := fmt.Sprintf("%d", acctest.RandIntRange(6900001, 7000000))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsCustomSpywareSignatureConfig(name),
+				Check:  checkDataSourceListing("panos_custom_spyware_signatures"),
+			},
+		},
+	})
+}
+
+// Data source tests.
+func TestAccPanosDsCustomSpywareSignature_basic(t *testing.T) {
+	name := fmt.Sprintf("%d", acctest.RandIntRange(6900001, 7000000))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsCustomSpywareSignatureConfig(name),
+				Check: checkDataSource("panos_custom_spyware_signature", []string{
+					"name", "threat_name", "severity", "default_action",
+					"standard_signature.0.name",
+					"standard_signature.0.and_condition.0.or_condition.0.pattern_match.0.context",
+					"standard_signature.0.and_condition.0.or_condition.0.pattern_match.0.pattern",
+				}),
+			},
+		},
+	})
+}
+
+func testAccDsCustomSpywareSignatureConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_custom_spyware_signatures" "test" {}
+
+data "panos_custom_spyware_signature" "test" {
+    name = panos_custom_spyware_signature.x.name
+}
+
+resource "panos_custom_spyware_signature" "x" {
+    name = %q
+    threat_name = "ds acctest"
+    severity = "high"
+    standard_signature {
+        name = "first"
+        and_condition {
+            or_condition {
+                pattern_match {
+                    context = "http-req-headers"
+                    pattern = "acctest"
+                }
+            }
+        }
+    }
+}
+`, name)
+}
+
+// Resource tests.
+func TestAccPanosCustomSpywareSignature_basic(t *testing.T) {
+	var o spyware.Entry
+	name := fmt.Sprintf("%d", acctest.RandIntRange(6900001, 7000000))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosCustomSpywareSignatureDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomSpywareSignatureConfig(name, "high", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosCustomSpywareSignatureExists("panos_custom_spyware_signature.test", &o),
+					testAccCheckPanosCustomSpywareSignatureAttributes(&o, name, "high", "first"),
+				),
+			},
+			{
+				Config: testAccCustomSpywareSignatureConfig(name, "critical", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosCustomSpywareSignatureExists("panos_custom_spyware_signature.test", &o),
+					testAccCheckPanosCustomSpywareSignatureAttributes(&o, name, "critical", "second"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPanosCustomSpywareSignatureExists(n string, o *spyware.Entry) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("Object label ID is not set")
+		}
+
+		var err error
+		var v spyware.Entry
+
+		loc, name := parseCustomThreatSignatureId(rs.Primary.ID)
+		switch con := testAccProvider.Meta().(type) {
+		case *pango.Firewall:
+			v, err = con.Objects.CustomSpyware.Get(loc, name)
+		case *pango.Panorama:
+			v, err = con.Objects.CustomSpyware.Get(loc, name)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Error in get: %s", err)
+		}
+
+		*o = v
+
+		return nil
+	}
+}
+
+func testAccCheckPanosCustomSpywareSignatureAttributes(o *spyware.Entry, name, severity, pattern string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if o.Name != name {
+			return fmt.Errorf("Name is %s, not %s", o.Name, name)
+		}
+
+		if o.Severity != severity {
+			return fmt.Errorf("Severity is %s, not %s", o.Severity, severity)
+		}
+
+		if o.StandardSignatureType == nil || len(o.StandardSignatureType.Signatures) != 1 {
+			return fmt.Errorf("Expected one standard signature: %#v", o.StandardSignatureType)
+		}
+
+		sig := o.StandardSignatureType.Signatures[0]
+		if len(sig.StandardAnds) != 1 || len(sig.StandardAnds[0].StandardOrs) != 2 {
+			return fmt.Errorf("Standard signature conditions are wrong: %#v", sig.StandardAnds)
+		}
+
+		x := sig.StandardAnds[0].StandardOrs[0].PatternMatch
+		if x == nil {
+			return fmt.Errorf("First or condition is not a pattern match")
+		} else if x.Pattern != pattern {
+			return fmt.Errorf("Pattern is %q, not %q", x.Pattern, pattern)
+		}
+
+		y := sig.StandardAnds[0].StandardOrs[1].GreaterThan
+		if y == nil {
+			return fmt.Errorf("Second or condition is not greater than")
+		} else if y.Value != 10 {
+			return fmt.Errorf("Greater than value is %d, not 10", y.Value)
+		}
+
+		return nil
+	}
+}
+
+func testAccPanosCustomSpywareSignatureDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_custom_spyware_signature" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			var err error
+
+			loc, name := parseCustomThreatSignatureId(rs.Primary.ID)
+			switch con := testAccProvider.Meta().(type) {
+			case *pango.Firewall:
+				_, err = con.Objects.CustomSpyware.Get(loc, name)
+			case *pango.Panorama:
+				_, err = con.Objects.CustomSpyware.Get(loc, name)
+			}
+			if err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccCustomSpywareSignatureConfig(name, severity, pattern string) string {
+	return fmt.Sprintf(`
+resource "panos_custom_spyware_signature" "test" {
+    name = %q
+    threat_name = "acctest"
+    severity = %q
+    direction = "client2server"
+    standard_signature {
+        name = "sig"
+        scope = "protocol-data-unit"
+        and_condition {
+            or_condition {
+                pattern_match {
+                    context = "http-req-headers"
+                    pattern = %q
+                }
+            }
+            or_condition {
+                greater_than {
+                    context = "http-req-param-length"
+                    value = 10
+                }
+            }
+        }
+    }
+}
+`, name, severity, pattern)
+}
@@ -0,0 +1,723 @@
+package panos
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/custom/spyware"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// customThreatSignatureSchema returns the schema shared by the custom spyware
+// and custom vulnerability signatures.
+//
+// Standard signatures use the same and/or condition layout as the
+// application signatures; the and and or condition names are generated.
+func customThreatSignatureSchema(isResource bool) map[string]*schema.Schema {
+	ans := map[string]*schema.Schema{
+		"vsys":         vsysSchema("vsys1"),
+		"device_group": deviceGroupSchema(),
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The threat ID",
+		},
+		"threat_name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The threat name",
+		},
+		"comment": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The comment",
+		},
+		"severity": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "The severity",
+			ValidateFunc: validateStringIn("informational", "low", "medium", "high", "critical"),
+		},
+		"direction": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The direction",
+			ValidateFunc: validateStringIn("", "client2server", "server2client", "both"),
+		},
+		"default_action": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     spyware.DefaultActionAlert,
+			Description: "The default action",
+			ValidateFunc: validateStringIn(
+				spyware.DefaultActionAllow,
+				spyware.DefaultActionAlert,
+				spyware.DefaultActionDrop,
+				spyware.DefaultActionResetClient,
+				spyware.DefaultActionResetServer,
+				spyware.DefaultActionResetBoth,
+				spyware.DefaultActionBlockIp,
+			),
+		},
+		"block_ip_track_by": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "For default_action = block-ip: the track by setting",
+			ValidateFunc: validateStringIn("", "source", "source-and-destination"),
+		},
+		"block_ip_duration": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "For default_action = block-ip: the block duration in seconds",
+		},
+		"cves": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "List of CVEs",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"bugtraqs": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "List of bugtraq IDs",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"vendors": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "List of vendor IDs",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"references": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "List of references",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"standard_signature": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			Description:   "List of standard signatures",
+			ConflictsWith: []string{"combination_signature"},
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The name",
+					},
+					"comment": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "The comment",
+					},
+					"scope": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "The scope",
+						ValidateFunc: validateStringIn("", "protocol-data-unit", "session"),
+					},
+					"ordered_match": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Match the and conditions in order",
+					},
+					"and_condition": customThreatStandardAndConditionSchema(),
+				},
+			},
+		},
+		"combination_signature": {
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			Description:   "The combination signature",
+			ConflictsWith: []string{"standard_signature"},
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"ordered_match": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Match the and conditions in order",
+					},
+					"threshold_time": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Number of hits",
+					},
+					"interval_time": {
+						Type:        schema.TypeInt,
+						Optional:    true,
+						Description: "Time interval in seconds",
+					},
+					"aggregation_criteria": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Description:  "The aggregation criteria",
+						ValidateFunc: validateStringIn("", "source", "destination", "source-and-destination"),
+					},
+					"and_condition": {
+						Type:        schema.TypeList,
+						Required:    true,
+						MinItems:    1,
+						Description: "List of and conditions",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:     schema.TypeString,
+									Computed: true,
+								},
+								"threat_ids": {
+									Type:        schema.TypeList,
+									Required:    true,
+									MinItems:    1,
+									Description: "The threat IDs that are or'ed together",
+									Elem: &schema.Schema{
+										Type: schema.TypeString,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !isResource {
+		computed(ans, "", []string{"vsys", "device_group", "name"})
+	}
+
+	return ans
+}
+
+func customThreatStandardAndConditionSchema() *schema.Schema {
+	condition := func(negate bool) *schema.Schema {
+		s := map[string]*schema.Schema{
+			"context": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"value": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"qualifiers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		}
+		if negate {
+			s["negate"] = &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+			}
+		}
+
+		return &schema.Schema{
+			Type:     schema.TypeList,
+			MaxItems: 1,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: s,
+			},
+		}
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"or_condition": {
+					Type:     schema.TypeList,
+					MinItems: 1,
+					Required: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Computed: true,
+							},
+							"pattern_match": {
+								Type:     schema.TypeList,
+								MaxItems: 1,
+								Optional: true,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"context": {
+											Type:     schema.TypeString,
+											Required: true,
+										},
+										"pattern": {
+											Type:     schema.TypeString,
+											Required: true,
+										},
+										"negate": {
+											Type:     schema.TypeBool,
+											Optional: true,
+										},
+										"qualifiers": {
+											Type:     schema.TypeMap,
+											Optional: true,
+											Elem: &schema.Schema{
+												Type: schema.TypeString,
+											},
+										},
+									},
+								},
+							},
+							"greater_than": condition(false),
+							"less_than":    condition(false),
+							"equal_to":     condition(true),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// customThreatQualifierNames returns the qualifier names from the given
+// schema map, sorted so the config sent to PAN-OS is stable.
+func customThreatQualifierNames(m map[string]interface{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	ans := make([]string, 0, len(m))
+	for k := range m {
+		ans = append(ans, k)
+	}
+	sort.Strings(ans)
+
+	return ans
+}
+
+// customThreatSignatureType is what differs between the custom spyware and
+// custom vulnerability signatures.
+//
+// The loc param is the vsys for NGFW and the device group for Panorama.
+type customThreatSignatureType struct {
+	getList func(meta interface{}, loc string) ([]string, error)
+	get     func(meta interface{}, loc, name string) (interface{}, error)
+	set     func(meta interface{}, loc string, o interface{}) error
+	edit    func(meta interface{}, loc string, o interface{}) error
+	remove  func(meta interface{}, loc, name string) error
+	load    func(d *schema.ResourceData) interface{}
+	save    func(d *schema.ResourceData, o interface{})
+}
+
+func customThreatSignatureLocation(d *schema.ResourceData, meta interface{}) string {
+	switch meta.(type) {
+	case *pango.Firewall:
+		return d.Get("vsys").(string)
+	case *pango.Panorama:
+		return d.Get("device_group").(string)
+	}
+
+	return ""
+}
+
+// Data source (listing).
+func (t customThreatSignatureType) dataSourceListingRead(d *schema.ResourceData, meta interface{}) error {
+	id := customThreatSignatureLocation(d, meta)
+
+	listing, err := t.getList(meta, id)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(id)
+	saveListing(d, listing)
+	return nil
+}
+
+// Data source.
+func (t customThreatSignatureType) dataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	loc := customThreatSignatureLocation(d, meta)
+	name := d.Get("name").(string)
+
+	o, err := t.get(meta, loc, name)
+	if err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.SetId(buildCustomThreatSignatureId(loc, name))
+	t.save(d, o)
+
+	return nil
+}
+
+// Resource.
+func (t customThreatSignatureType) resource(s map[string]*schema.Schema) *schema.Resource {
+	return &schema.Resource{
+		Create: t.createResource,
+		Read:   t.readResource,
+		Update: t.updateResource,
+		Delete: t.deleteResource,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: s,
+	}
+}
+
+func (t customThreatSignatureType) createResource(d *schema.ResourceData, meta interface{}) error {
+	loc := customThreatSignatureLocation(d, meta)
+	o := t.load(d)
+
+	if err := t.set(meta, loc, o); err != nil {
+		return err
+	}
+
+	d.SetId(buildCustomThreatSignatureId(loc, d.Get("name").(string)))
+	return t.readResource(d, meta)
+}
+
+func (t customThreatSignatureType) readResource(d *schema.ResourceData, meta interface{}) error {
+	loc, name := parseCustomThreatSignatureId(d.Id())
+
+	o, err := t.get(meta, loc, name)
+	if err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	switch meta.(type) {
+	case *pango.Firewall:
+		d.Set("vsys", loc)
+	case *pango.Panorama:
+		d.Set("device_group", loc)
+	}
+	t.save(d, o)
+
+	return nil
+}
+
+func (t customThreatSignatureType) updateResource(d *schema.ResourceData, meta interface{}) error {
+	loc := customThreatSignatureLocation(d, meta)
+	o := t.load(d)
+
+	if err := t.edit(meta, loc, o); err != nil {
+		return err
+	}
+
+	return t.readResource(d, meta)
+}
+
+func (t customThreatSignatureType) deleteResource(d *schema.ResourceData, meta interface{}) error {
+	loc, name := parseCustomThreatSignatureId(d.Id())
+
+	if err := t.remove(meta, loc, name); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema handling.
+//
+// The custom spyware and custom vulnerability signatures have the same layout
+// apart from the vulnerability's affected systems, so both are loaded and
+// saved as a spyware.Entry.
+func loadCustomThreatSignature(d *schema.ResourceData) spyware.Entry {
+	o := spyware.Entry{
+		Name:            d.Get("name").(string),
+		ThreatName:      d.Get("threat_name").(string),
+		Comment:         d.Get("comment").(string),
+		Severity:        d.Get("severity").(string),
+		Direction:       d.Get("direction").(string),
+		DefaultAction:   d.Get("default_action").(string),
+		BlockIpTrackBy:  d.Get("block_ip_track_by").(string),
+		BlockIpDuration: d.Get("block_ip_duration").(int),
+		Cves:            asStringList(d.Get("cves").([]interface{})),
+		Bugtraqs:        asStringList(d.Get("bugtraqs").([]interface{})),
+		Vendors:         asStringList(d.Get("vendors").([]interface{})),
+		References:      asStringList(d.Get("references").([]interface{})),
+	}
+
+	if list := d.Get("standard_signature").([]interface{}); len(list) != 0 {
+		o.StandardSignatureType = &spyware.StandardSignatureType{
+			Signatures: make([]spyware.StandardSignature, 0, len(list)),
+		}
+		for i := range list {
+			elm := list[i].(map[string]interface{})
+			sig := spyware.StandardSignature{
+				Name:      elm["name"].(string),
+				Comment:   elm["comment"].(string),
+				Scope:     elm["scope"].(string),
+				OrderFree: !elm["ordered_match"].(bool),
+			}
+
+			al := elm["and_condition"].([]interface{})
+			for j := range al {
+				andBlock := al[j].(map[string]interface{})
+				and := spyware.StandardAnd{
+					Name: fmt.Sprintf("And Condition %d", j+1),
+				}
+				ol := andBlock["or_condition"].([]interface{})
+				for k := range ol {
+					orBlock := ol[k].(map[string]interface{})
+					or := spyware.StandardOr{
+						Name: fmt.Sprintf("Or Condition %d", k+1),
+					}
+					if x := asInterfaceMap(orBlock, "pattern_match"); len(x) != 0 {
+						or.PatternMatch = &spyware.Pattern{
+							Pattern:    x["pattern"].(string),
+							Context:    x["context"].(string),
+							Negate:     x["negate"].(bool),
+							Qualifiers: loadCustomThreatQualifiers(x),
+						}
+					} else if x := asInterfaceMap(orBlock, "greater_than"); len(x) != 0 {
+						or.GreaterThan = &spyware.Condition{
+							Value:      x["value"].(int),
+							Context:    x["context"].(string),
+							Qualifiers: loadCustomThreatQualifiers(x),
+						}
+					} else if x := asInterfaceMap(orBlock, "less_than"); len(x) != 0 {
+						or.LessThan = &spyware.Condition{
+							Value:      x["value"].(int),
+							Context:    x["context"].(string),
+							Qualifiers: loadCustomThreatQualifiers(x),
+						}
+					} else if x := asInterfaceMap(orBlock, "equal_to"); len(x) != 0 {
+						or.EqualTo = &spyware.EqualTo{
+							Value:      x["value"].(int),
+							Context:    x["context"].(string),
+							Negate:     x["negate"].(bool),
+							Qualifiers: loadCustomThreatQualifiers(x),
+						}
+					}
+					and.StandardOrs = append(and.StandardOrs, or)
+				}
+				sig.StandardAnds = append(sig.StandardAnds, and)
+			}
+
+			o.StandardSignatureType.Signatures = append(o.StandardSignatureType.Signatures, sig)
+		}
+	}
+
+	if list := d.Get("combination_signature").([]interface{}); len(list) != 0 && list[0] != nil {
+		elm := list[0].(map[string]interface{})
+		o.CombinationSignatureType = &spyware.CombinationSignatureType{
+			OrderFree:           !elm["ordered_match"].(bool),
+			ThresholdTime:       elm["threshold_time"].(int),
+			IntervalTime:        elm["interval_time"].(int),
+			AggregationCriteria: elm["aggregation_criteria"].(string),
+		}
+
+		al := elm["and_condition"].([]interface{})
+		for i := range al {
+			andBlock := al[i].(map[string]interface{})
+			sig := spyware.CombinationSignature{
+				Name: fmt.Sprintf("And Condition %d", i+1),
+			}
+			for j, tid := range asStringList(andBlock["threat_ids"].([]interface{})) {
+				sig.CombinationOrs = append(sig.CombinationOrs, spyware.CombinationOr{
+					Name:     fmt.Sprintf("Or Condition %d", j+1),
+					ThreatId: tid,
+				})
+			}
+			o.CombinationSignatureType.Signatures = append(o.CombinationSignatureType.Signatures, sig)
+		}
+	}
+
+	return o
+}
+
+func loadCustomThreatQualifiers(m map[string]interface{}) []spyware.Qualifier {
+	qm, _ := m["qualifiers"].(map[string]interface{})
+	names := customThreatQualifierNames(qm)
+	if len(names) == 0 {
+		return nil
+	}
+
+	ans := make([]spyware.Qualifier, 0, len(names))
+	for _, name := range names {
+		ans = append(ans, spyware.Qualifier{
+			Qualifier: name,
+			Value:     qm[name].(string),
+		})
+	}
+
+	return ans
+}
+
+func saveCustomThreatSignature(d *schema.ResourceData, o spyware.Entry) {
+	var err error
+
+	d.Set("name", o.Name)
+	d.Set("threat_name", o.ThreatName)
+	d.Set("comment", o.Comment)
+	d.Set("severity", o.Severity)
+	d.Set("direction", o.Direction)
+	d.Set("default_action", o.DefaultAction)
+	d.Set("block_ip_track_by", o.BlockIpTrackBy)
+	d.Set("block_ip_duration", o.BlockIpDuration)
+	if err = d.Set("cves", o.Cves); err != nil {
+		log.Printf("[WARN] Error setting 'cves' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("bugtraqs", o.Bugtraqs); err != nil {
+		log.Printf("[WARN] Error setting 'bugtraqs' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("vendors", o.Vendors); err != nil {
+		log.Printf("[WARN] Error setting 'vendors' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("references", o.References); err != nil {
+		log.Printf("[WARN] Error setting 'references' for %q: %s", d.Id(), err)
+	}
+
+	if o.StandardSignatureType == nil || len(o.StandardSignatureType.Signatures) == 0 {
+		d.Set("standard_signature", nil)
+	} else {
+		list := make([]interface{}, 0, len(o.StandardSignatureType.Signatures))
+		for _, sig := range o.StandardSignatureType.Signatures {
+			ac := make([]interface{}, 0, len(sig.StandardAnds))
+			for _, and := range sig.StandardAnds {
+				oc := make([]interface{}, 0, len(and.StandardOrs))
+				for _, or := range and.StandardOrs {
+					orEntry := map[string]interface{}{
+						"name": or.Name,
+					}
+					switch {
+					case or.PatternMatch != nil:
+						orEntry["pattern_match"] = []interface{}{
+							map[string]interface{}{
+								"context":    or.PatternMatch.Context,
+								"pattern":    or.PatternMatch.Pattern,
+								"negate":     or.PatternMatch.Negate,
+								"qualifiers": saveCustomThreatQualifiers(or.PatternMatch.Qualifiers),
+							},
+						}
+					case or.GreaterThan != nil:
+						orEntry["greater_than"] = []interface{}{
+							map[string]interface{}{
+								"context":    or.GreaterThan.Context,
+								"value":      or.GreaterThan.Value,
+								"qualifiers": saveCustomThreatQualifiers(or.GreaterThan.Qualifiers),
+							},
+						}
+					case or.LessThan != nil:
+						orEntry["less_than"] = []interface{}{
+							map[string]interface{}{
+								"context":    or.LessThan.Context,
+								"value":      or.LessThan.Value,
+								"qualifiers": saveCustomThreatQualifiers(or.LessThan.Qualifiers),
+							},
+						}
+					case or.EqualTo != nil:
+						orEntry["equal_to"] = []interface{}{
+							map[string]interface{}{
+								"context":    or.EqualTo.Context,
+								"value":      or.EqualTo.Value,
+								"negate":     or.EqualTo.Negate,
+								"qualifiers": saveCustomThreatQualifiers(or.EqualTo.Qualifiers),
+							},
+						}
+					}
+					oc = append(oc, orEntry)
+				}
+				ac = append(ac, map[string]interface{}{
+					"name":         and.Name,
+					"or_condition": oc,
+				})
+			}
+			list = append(list, map[string]interface{}{
+				"name":          sig.Name,
+				"comment":       sig.Comment,
+				"scope":         sig.Scope,
+				"ordered_match": !sig.OrderFree,
+				"and_condition": ac,
+			})
+		}
+
+		if err = d.Set("standard_signature", list); err != nil {
+			log.Printf("[WARN] Error setting 'standard_signature' for %q: %s", d.Id(), err)
+		}
+	}
+
+	if o.CombinationSignatureType == nil {
+		d.Set("combination_signature", nil)
+	} else {
+		ac := make([]interface{}, 0, len(o.CombinationSignatureType.Signatures))
+		for _, sig := range o.CombinationSignatureType.Signatures {
+			tids := make([]string, 0, len(sig.CombinationOrs))
+			for _, or := range sig.CombinationOrs {
+				tids = append(tids, or.ThreatId)
+			}
+			ac = append(ac, map[string]interface{}{
+				"name":       sig.Name,
+				"threat_ids": tids,
+			})
+		}
+
+		cs := map[string]interface{}{
+			"ordered_match":        !o.CombinationSignatureType.OrderFree,
+			"threshold_time":       o.CombinationSignatureType.ThresholdTime,
+			"interval_time":        o.CombinationSignatureType.IntervalTime,
+			"aggregation_criteria": o.CombinationSignatureType.AggregationCriteria,
+			"and_condition":        ac,
+		}
+
+		if err = d.Set("combination_signature", []interface{}{cs}); err != nil {
+			log.Printf("[WARN] Error setting 'combination_signature' for %q: %s", d.Id(), err)
+		}
+	}
+}
+
+func saveCustomThreatQualifiers(list []spyware.Qualifier) map[string]interface{} {
+	if len(list) == 0 {
+		return nil
+	}
+
+	ans := make(map[string]interface{})
+	for _, x := range list {
+		ans[x.Qualifier] = x.Value
+	}
+
+	return ans
+}
+
+// Id functions.
+func buildCustomThreatSignatureId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parseCustomThreatSignatureId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
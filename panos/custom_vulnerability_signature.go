@@ -0,0 +1,150 @@
+package panos
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/custom/spyware"
+	"github.com/fpluchorg/pango/objs/custom/vulnerability"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// customVulnerabilitySignature is the custom vulnerability signature type.
+var customVulnerabilitySignature = customThreatSignatureType{
+	getList: func(meta interface{}, loc string) ([]string, error) {
+		return customVulnerabilityNamespace(meta).GetList(loc)
+	},
+	get: func(meta interface{}, loc, name string) (interface{}, error) {
+		return customVulnerabilityNamespace(meta).Get(loc, name)
+	},
+	set: func(meta interface{}, loc string, o interface{}) error {
+		return customVulnerabilityNamespace(meta).Set(loc, o.(vulnerability.Entry))
+	},
+	edit: func(meta interface{}, loc string, o interface{}) error {
+		ns := customVulnerabilityNamespace(meta)
+		e := o.(vulnerability.Entry)
+		lo, err := ns.Get(loc, e.Name)
+		if err != nil {
+			return err
+		}
+		lo.Copy(e)
+		return ns.Edit(loc, lo)
+	},
+	remove: func(meta interface{}, loc, name string) error {
+		return customVulnerabilityNamespace(meta).Delete(loc, name)
+	},
+	load: func(d *schema.ResourceData) interface{} {
+		return loadCustomVulnerabilitySignature(d)
+	},
+	save: func(d *schema.ResourceData, o interface{}) {
+		saveCustomVulnerabilitySignature(d, o.(vulnerability.Entry))
+	},
+}
+
+// customVulnerabilityNs is the part of pango's custom vulnerability namespace
+// that is the same for NGFW and Panorama.
+type customVulnerabilityNs interface {
+	GetList(string) ([]string, error)
+	Get(string, string) (vulnerability.Entry, error)
+	Set(string, ...vulnerability.Entry) error
+	Edit(string, vulnerability.Entry) error
+	Delete(string, ...interface{}) error
+}
+
+func customVulnerabilityNamespace(meta interface{}) customVulnerabilityNs {
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		return con.Objects.CustomVulnerability
+	case *pango.Panorama:
+		return con.Objects.CustomVulnerability
+	}
+
+	return nil
+}
+
+// Data source (listing).
+func dataSourceCustomVulnerabilitySignatures() *schema.Resource {
+	s := listingSchema()
+	s["vsys"] = vsysSchema("vsys1")
+	s["device_group"] = deviceGroupSchema()
+
+	return &schema.Resource{
+		Read: customVulnerabilitySignature.dataSourceListingRead,
+
+		Schema: s,
+	}
+}
+
+// Data source.
+func dataSourceCustomVulnerabilitySignature() *schema.Resource {
+	return &schema.Resource{
+		Read: customVulnerabilitySignature.dataSourceRead,
+
+		Schema: customVulnerabilitySignatureSchema(false),
+	}
+}
+
+// Resource.
+func resourceCustomVulnerabilitySignature() *schema.Resource {
+	return customVulnerabilitySignature.resource(customVulnerabilitySignatureSchema(true))
+}
+
+// Schema handling.
+func customVulnerabilitySignatureSchema(isResource bool) map[string]*schema.Schema {
+	ans := customThreatSignatureSchema(true)
+
+	ans["affected_client"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "The vulnerability affects clients",
+	}
+	ans["affected_server"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "The vulnerability affects servers",
+	}
+
+	if !isResource {
+		computed(ans, "", []string{"vsys", "device_group", "name"})
+	}
+
+	return ans
+}
+
+func loadCustomVulnerabilitySignature(d *schema.ResourceData) vulnerability.Entry {
+	var o vulnerability.Entry
+
+	convertCustomThreatSignature(loadCustomThreatSignature(d), &o)
+	o.AffectedSystemClient = d.Get("affected_client").(bool)
+	o.AffectedSystemServer = d.Get("affected_server").(bool)
+
+	return o
+}
+
+func saveCustomVulnerabilitySignature(d *schema.ResourceData, o vulnerability.Entry) {
+	var s spyware.Entry
+
+	convertCustomThreatSignature(o, &s)
+	saveCustomThreatSignature(d, s)
+	d.Set("affected_client", o.AffectedSystemClient)
+	d.Set("affected_server", o.AffectedSystemServer)
+}
+
+// convertCustomThreatSignature converts between spyware.Entry and
+// vulnerability.Entry.
+//
+// The two are separate types in pango, but their fields (and the fields of
+// their signature types) have the same names and types, so they are
+// converted by field name.  The vulnerability's affected systems are left
+// as is.
+func convertCustomThreatSignature(src, dst interface{}) {
+	b, err := json.Marshal(src)
+	if err == nil {
+		err = json.Unmarshal(b, dst)
+	}
+	if err != nil {
+		log.Printf("[WARN] Error converting custom signature: %s", err)
+	}
+}
@@ -0,0 +1,293 @@
+package panos
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/custom/spyware"
+	"github.com/fpluchorg/pango/objs/custom/vulnerability"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Data source listing tests.
+func TestAccPanosDsCustomVulnerabilitySignatureList(t *testing.T) {
+	name := fmt.Sprintf("%d", acctest.RandIntRange(6800001, 6900000))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsCustomVulnerabilitySignatureConfig(name),
+				Check:  checkDataSourceListing("panos_custom_vulnerability_signatures"),
+			},
+		},
+	})
+}
+
+// Data source tests.
+func TestAccPanosDsCustomVulnerabilitySignature_basic(t *testing.T) {
+	name := fmt.Sprintf("%d", acctest.RandIntRange(6800001, 6900000))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsCustomVulnerabilitySignatureConfig(name),
+				Check: checkDataSource("panos_custom_vulnerability_signature", []string{
+					"name", "threat_name", "severity", "default_action", "affected_client",
+					"standard_signature.0.name",
+					"standard_signature.0.and_condition.0.or_condition.0.pattern_match.0.context",
+					"standard_signature.0.and_condition.0.or_condition.0.pattern_match.0.pattern",
+				}),
+			},
+		},
+	})
+}
+
+func testAccDsCustomVulnerabilitySignatureConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_custom_vulnerability_signatures" "test" {}
+
+data "panos_custom_vulnerability_signature" "test" {
+    name = panos_custom_vulnerability_signature.x.name
+}
+
+resource "panos_custom_vulnerability_signature" "x" {
+    name = %q
+    threat_name = "ds acctest"
+    severity = "high"
+    affected_client = true
+    standard_signature {
+        name = "first"
+        and_condition {
+            or_condition {
+                pattern_match {
+                    context = "http-req-headers"
+                    pattern = "acctest"
+                }
+            }
+        }
+    }
+}
+`, name)
+}
+
+// Resource tests.
+func TestAccPanosCustomVulnerabilitySignature_basic(t *testing.T) {
+	var o vulnerability.Entry
+	name := fmt.Sprintf("%d", acctest.RandIntRange(6800001, 6900000))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosCustomVulnerabilitySignatureDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomVulnerabilitySignatureConfig(name, "high", true, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosCustomVulnerabilitySignatureExists("panos_custom_vulnerability_signature.test", &o),
+					testAccCheckPanosCustomVulnerabilitySignatureAttributes(&o, name, "high", true, "first"),
+				),
+			},
+			{
+				Config: testAccCustomVulnerabilitySignatureConfig(name, "critical", false, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosCustomVulnerabilitySignatureExists("panos_custom_vulnerability_signature.test", &o),
+					testAccCheckPanosCustomVulnerabilitySignatureAttributes(&o, name, "critical", false, "second"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPanosCustomVulnerabilitySignatureExists(n string, o *vulnerability.Entry) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("Object label ID is not set")
+		}
+
+		var err error
+		var v vulnerability.Entry
+
+		loc, name := parseCustomThreatSignatureId(rs.Primary.ID)
+		switch con := testAccProvider.Meta().(type) {
+		case *pango.Firewall:
+			v, err = con.Objects.CustomVulnerability.Get(loc, name)
+		case *pango.Panorama:
+			v, err = con.Objects.CustomVulnerability.Get(loc, name)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Error in get: %s", err)
+		}
+
+		*o = v
+
+		return nil
+	}
+}
+
+func testAccCheckPanosCustomVulnerabilitySignatureAttributes(o *vulnerability.Entry, name, severity string, client bool, pattern string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if o.Name != name {
+			return fmt.Errorf("Name is %s, not %s", o.Name, name)
+		}
+
+		if o.AffectedSystemClient != client {
+			return fmt.Errorf("Affected client is %t, not %t", o.AffectedSystemClient, client)
+		}
+
+		if !o.AffectedSystemServer {
+			return fmt.Errorf("Affected server is not set")
+		}
+
+		if o.Severity != severity {
+			return fmt.Errorf("Severity is %s, not %s", o.Severity, severity)
+		}
+
+		if o.StandardSignatureType == nil || len(o.StandardSignatureType.Signatures) != 1 {
+			return fmt.Errorf("Expected one standard signature: %#v", o.StandardSignatureType)
+		}
+
+		sig := o.StandardSignatureType.Signatures[0]
+		if len(sig.StandardAnds) != 1 || len(sig.StandardAnds[0].StandardOrs) != 2 {
+			return fmt.Errorf("Standard signature conditions are wrong: %#v", sig.StandardAnds)
+		}
+
+		x := sig.StandardAnds[0].StandardOrs[0].PatternMatch
+		if x == nil {
+			return fmt.Errorf("First or condition is not a pattern match")
+		} else if x.Pattern != pattern {
+			return fmt.Errorf("Pattern is %q, not %q", x.Pattern, pattern)
+		}
+
+		y := sig.StandardAnds[0].StandardOrs[1].GreaterThan
+		if y == nil {
+			return fmt.Errorf("Second or condition is not greater than")
+		} else if y.Value != 10 {
+			return fmt.Errorf("Greater than value is %d, not 10", y.Value)
+		}
+
+		return nil
+	}
+}
+
+func testAccPanosCustomVulnerabilitySignatureDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_custom_vulnerability_signature" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			var err error
+
+			loc, name := parseCustomThreatSignatureId(rs.Primary.ID)
+			switch con := testAccProvider.Meta().(type) {
+			case *pango.Firewall:
+				_, err = con.Objects.CustomVulnerability.Get(loc, name)
+			case *pango.Panorama:
+				_, err = con.Objects.CustomVulnerability.Get(loc, name)
+			}
+			if err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccCustomVulnerabilitySignatureConfig(name, severity string, client bool, pattern string) string {
+	return fmt.Sprintf(`
+resource "panos_custom_vulnerability_signature" "test" {
+    name = %q
+    threat_name = "acctest"
+    severity = %q
+    direction = "client2server"
+    affected_client = %t
+    affected_server = true
+    standard_signature {
+        name = "sig"
+        scope = "protocol-data-unit"
+        and_condition {
+            or_condition {
+                pattern_match {
+                    context = "http-req-headers"
+                    pattern = %q
+                }
+            }
+            or_condition {
+                greater_than {
+                    context = "http-req-param-length"
+                    value = 10
+                }
+            }
+        }
+    }
+}
+`, name, severity, client, pattern)
+}
+
+func TestConvertCustomThreatSignature(t *testing.T) {
+	o := vulnerability.Entry{
+		Name:                 "6800001",
+		ThreatName:           "conversion",
+		AffectedSystemClient: true,
+		Severity:             "high",
+		Cves:                 []string{"CVE-2020-0001"},
+		StandardSignatureType: &vulnerability.StandardSignatureType{
+			Signatures: []vulnerability.StandardSignature{{
+				Name:  "sig",
+				Scope: "session",
+				StandardAnds: []vulnerability.StandardAnd{{
+					Name: "And Condition 1",
+					StandardOrs: []vulnerability.StandardOr{{
+						Name: "Or Condition 1",
+						PatternMatch: &vulnerability.Pattern{
+							Context: "http-req-headers",
+							Pattern: "acctest",
+							Qualifiers: []vulnerability.Qualifier{{
+								Qualifier: "http-method",
+								Value:     "GET",
+							}},
+						},
+					}, {
+						Name: "Or Condition 2",
+						EqualTo: &vulnerability.EqualTo{
+							Context: "http-req-param-length",
+							Value:   10,
+							Negate:  true,
+						},
+					}},
+				}},
+			}},
+		},
+	}
+
+	var s spyware.Entry
+	var ans vulnerability.Entry
+
+	convertCustomThreatSignature(o, &s)
+	convertCustomThreatSignature(s, &ans)
+	if ans.AffectedSystemClient {
+		t.Errorf("Affected systems should not survive being a spyware entry")
+	}
+
+	ans.AffectedSystemClient = o.AffectedSystemClient
+	if !reflect.DeepEqual(o, ans) {
+		t.Errorf("Conversion mismatch:\nexpected %#v\n     got %#v", o, ans)
+	}
+}
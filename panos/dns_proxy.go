@@ -0,0 +1,399 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceDnsProxy() *schema.Resource {
+	return &schema.Resource{
+		Create: createDnsProxy,
+		Read:   readDnsProxy,
+		Update: updateDnsProxy,
+		Delete: deleteDnsProxy,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: dnsProxySchema(),
+	}
+}
+
+func createDnsProxy(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	o := loadDnsProxy(d)
+
+	path, err := dnsProxyXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("DNS proxy %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildDnsProxyId(tmpl, ts, o.Name))
+	return readDnsProxy(d, meta)
+}
+
+func readDnsProxy(d *schema.ResourceData, meta interface{}) error {
+	var ans dnsProxyAns
+
+	tmpl, ts, name := parseDnsProxyId(d.Id())
+
+	path, err := dnsProxyXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	saveDnsProxy(d, *ans.Entry)
+
+	return nil
+}
+
+func updateDnsProxy(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, name := parseDnsProxyId(d.Id())
+	o := loadDnsProxy(d)
+
+	path, err := dnsProxyXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readDnsProxy(d, meta)
+}
+
+func deleteDnsProxy(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, name := parseDnsProxyId(d.Id())
+
+	path, err := dnsProxyXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func dnsProxySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template":       templateSchema(true),
+		"template_stack": templateStackSchema(),
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name",
+		},
+		"enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Enable the DNS proxy",
+		},
+		"interfaces": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Interfaces the DNS proxy listens on",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"primary": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The default primary DNS server",
+		},
+		"secondary": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The default secondary DNS server",
+		},
+		"inherit_source": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Dynamic interface to inherit the default DNS servers from",
+		},
+		"cache_enabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Cache DNS responses",
+		},
+		"static_entry": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Static FQDN to address mappings",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"domain": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"addresses": {
+						Type:     schema.TypeList,
+						Required: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		"domain_rule": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "DNS servers to use for specific domains",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"domain_names": {
+						Type:     schema.TypeList,
+						Required: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"cacheable": {
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+					"primary": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"secondary": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func loadDnsProxy(d *schema.ResourceData) dnsProxyEntry {
+	o := dnsProxyEntry{
+		Name:       d.Get("name").(string),
+		Enabled:    util.YesNo(d.Get("enabled").(bool)),
+		Interfaces: util.StrToMem(asStringList(d.Get("interfaces").([]interface{}))),
+		Default: &dnsProxyDefault{
+			Primary:   d.Get("primary").(string),
+			Secondary: d.Get("secondary").(string),
+		},
+		Cache: &dnsProxyCache{
+			Enabled: util.YesNo(d.Get("cache_enabled").(bool)),
+		},
+	}
+
+	if v := d.Get("inherit_source").(string); v != "" {
+		o.Default.Inheritance = &dnsProxyInheritance{Source: v}
+	}
+
+	if list := d.Get("static_entry").([]interface{}); len(list) != 0 {
+		o.StaticEntries = &dnsProxyStaticEntries{}
+		for i := range list {
+			x := list[i].(map[string]interface{})
+			o.StaticEntries.Entries = append(o.StaticEntries.Entries, dnsProxyStaticEntry{
+				Name:      x["name"].(string),
+				Domain:    x["domain"].(string),
+				Addresses: util.StrToMem(asStringList(x["addresses"].([]interface{}))),
+			})
+		}
+	}
+
+	if list := d.Get("domain_rule").([]interface{}); len(list) != 0 {
+		o.DomainServers = &dnsProxyDomainServers{}
+		for i := range list {
+			x := list[i].(map[string]interface{})
+			o.DomainServers.Entries = append(o.DomainServers.Entries, dnsProxyDomainServer{
+				Name:        x["name"].(string),
+				Cacheable:   util.YesNo(x["cacheable"].(bool)),
+				DomainNames: util.StrToMem(asStringList(x["domain_names"].([]interface{}))),
+				Primary:     x["primary"].(string),
+				Secondary:   x["secondary"].(string),
+			})
+		}
+	}
+
+	return o
+}
+
+func saveDnsProxy(d *schema.ResourceData, o dnsProxyEntry) {
+	var err error
+	var def dnsProxyDefault
+	var inherit string
+
+	if o.Default != nil {
+		def = *o.Default
+		if def.Inheritance != nil {
+			inherit = def.Inheritance.Source
+		}
+	}
+
+	d.Set("name", o.Name)
+	d.Set("enabled", o.Enabled == "" || util.AsBool(o.Enabled))
+	if err = d.Set("interfaces", util.MemToStr(o.Interfaces)); err != nil {
+		log.Printf("[WARN] Error setting 'interfaces' for %q: %s", d.Id(), err)
+	}
+	d.Set("primary", def.Primary)
+	d.Set("secondary", def.Secondary)
+	d.Set("inherit_source", inherit)
+	d.Set("cache_enabled", o.Cache == nil || o.Cache.Enabled == "" || util.AsBool(o.Cache.Enabled))
+
+	var statics []interface{}
+	if o.StaticEntries != nil {
+		for _, x := range o.StaticEntries.Entries {
+			statics = append(statics, map[string]interface{}{
+				"name":      x.Name,
+				"domain":    x.Domain,
+				"addresses": util.MemToStr(x.Addresses),
+			})
+		}
+	}
+	if err = d.Set("static_entry", statics); err != nil {
+		log.Printf("[WARN] Error setting 'static_entry' for %q: %s", d.Id(), err)
+	}
+
+	var rules []interface{}
+	if o.DomainServers != nil {
+		for _, x := range o.DomainServers.Entries {
+			rules = append(rules, map[string]interface{}{
+				"name":         x.Name,
+				"domain_names": util.MemToStr(x.DomainNames),
+				"cacheable":    util.AsBool(x.Cacheable),
+				"primary":      x.Primary,
+				"secondary":    x.Secondary,
+			})
+		}
+	}
+	if err = d.Set("domain_rule", rules); err != nil {
+		log.Printf("[WARN] Error setting 'domain_rule' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildDnsProxyId(a, b, c string) string {
+	return strings.Join([]string{a, b, c}, IdSeparator)
+}
+
+func parseDnsProxyId(v string) (string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2]
+}
+
+func dnsProxyXpath(meta interface{}, tmpl, ts string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "network", "dns-proxy"), nil
+}
+
+// Config structs.
+type dnsProxyEntry struct {
+	XMLName       xml.Name               `xml:"entry"`
+	Name          string                 `xml:"name,attr"`
+	Enabled       string                 `xml:"enabled,omitempty"`
+	Default       *dnsProxyDefault       `xml:"default"`
+	Interfaces    *util.MemberType       `xml:"interface"`
+	DomainServers *dnsProxyDomainServers `xml:"domain-servers"`
+	StaticEntries *dnsProxyStaticEntries `xml:"static-entries"`
+	Cache         *dnsProxyCache         `xml:"cache"`
+}
+
+type dnsProxyDefault struct {
+	Inheritance *dnsProxyInheritance `xml:"inheritance"`
+	Primary     string               `xml:"primary,omitempty"`
+	Secondary   string               `xml:"secondary,omitempty"`
+}
+
+type dnsProxyInheritance struct {
+	Source string `xml:"source"`
+}
+
+type dnsProxyDomainServers struct {
+	Entries []dnsProxyDomainServer `xml:"entry"`
+}
+
+type dnsProxyDomainServer struct {
+	Name        string           `xml:"name,attr"`
+	Cacheable   string           `xml:"cacheable"`
+	DomainNames *util.MemberType `xml:"domain-name"`
+	Primary     string           `xml:"primary,omitempty"`
+	Secondary   string           `xml:"secondary,omitempty"`
+}
+
+type dnsProxyStaticEntries struct {
+	Entries []dnsProxyStaticEntry `xml:"entry"`
+}
+
+type dnsProxyStaticEntry struct {
+	Name      string           `xml:"name,attr"`
+	Domain    string           `xml:"domain"`
+	Addresses *util.MemberType `xml:"address"`
+}
+
+type dnsProxyCache struct {
+	Enabled string `xml:"enabled"`
+}
+
+type dnsProxyAns struct {
+	Entry *dnsProxyEntry `xml:"result>entry"`
+}
@@ -0,0 +1,86 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosDnsProxy(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosDnsProxyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDnsProxyConfig(name, "10.1.1.1", "10.2.2.2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_dns_proxy.test", "primary", "10.1.1.1"),
+					resource.TestCheckResourceAttr("panos_dns_proxy.test", "static_entry.0.addresses.0", "10.2.2.2"),
+					resource.TestCheckResourceAttr("panos_dns_proxy.test", "domain_rule.0.domain_names.0", "*.example.com"),
+				),
+			},
+			{
+				Config: testAccDnsProxyConfig(name, "10.3.3.3", "10.4.4.4"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_dns_proxy.test", "primary", "10.3.3.3"),
+					resource.TestCheckResourceAttr("panos_dns_proxy.test", "static_entry.0.addresses.0", "10.4.4.4"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosDnsProxyDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_dns_proxy" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, name := parseDnsProxyId(rs.Primary.ID)
+			path, err := dnsProxyXpath(testAccProvider.Meta(), tmpl, ts)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccDnsProxyConfig(name, primary, addr string) string {
+	return fmt.Sprintf(`
+resource "panos_dns_proxy" "test" {
+    name = %q
+    primary = %q
+    static_entry {
+        name = "host"
+        domain = "host.example.com"
+        addresses = [%q]
+    }
+    domain_rule {
+        name = "internal"
+        domain_names = ["*.example.com"]
+        cacheable = true
+        primary = %q
+    }
+}
+`, name, primary, addr, primary)
+}
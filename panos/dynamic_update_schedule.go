@@ -0,0 +1,311 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Resource.
+func resourceDynamicUpdateSchedule() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdateDynamicUpdateSchedule,
+		Read:   readDynamicUpdateSchedule,
+		Update: createUpdateDynamicUpdateSchedule,
+		Delete: deleteDynamicUpdateSchedule,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: dynamicUpdateScheduleSchema(),
+	}
+}
+
+func createUpdateDynamicUpdateSchedule(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	typ := d.Get("type").(string)
+	o := loadDynamicUpdateSchedule(d)
+
+	if err := validateDynamicUpdateSchedule(typ, o); err != nil {
+		return err
+	}
+
+	path, err := dynamicUpdateScheduleXpath(meta, tmpl, ts, typ)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildDynamicUpdateScheduleId(tmpl, ts, typ))
+	return readDynamicUpdateSchedule(d, meta)
+}
+
+func readDynamicUpdateSchedule(d *schema.ResourceData, meta interface{}) error {
+	var ans dynamicUpdateScheduleAns
+
+	tmpl, ts, typ := parseDynamicUpdateScheduleId(d.Id())
+
+	path, err := dynamicUpdateScheduleXpath(meta, tmpl, ts, typ)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Result.Config == nil || ans.Result.Config.Recurring == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("type", typ)
+	saveDynamicUpdateSchedule(d, *ans.Result.Config.Recurring)
+
+	return nil
+}
+
+func deleteDynamicUpdateSchedule(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, typ := parseDynamicUpdateScheduleId(d.Id())
+
+	path, err := dynamicUpdateScheduleXpath(meta, tmpl, ts, typ)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(path, nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func dynamicUpdateScheduleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template":       templateSchema(true),
+		"template_stack": templateStackSchema(),
+		"type": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The dynamic update type",
+			ValidateFunc: validation.StringInSlice([]string{
+				"threats",
+				"anti-virus",
+				"wildfire",
+				"global-protect-datafile",
+				"global-protect-clientless-vpn",
+			}, false),
+		},
+		"recurrence": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "none",
+			Description: "How often to check for updates",
+			ValidateFunc: validation.StringInSlice([]string{
+				"none",
+				"real-time",
+				"every-min",
+				"every-15-mins",
+				"every-30-mins",
+				"every-hour",
+				"hourly",
+				"daily",
+				"weekly",
+			}, false),
+		},
+		"day_of_week": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "(recurrence=weekly) The day of the week",
+			ValidateFunc: validation.StringInSlice([]string{
+				"sunday",
+				"monday",
+				"tuesday",
+				"wednesday",
+				"thursday",
+				"friday",
+				"saturday",
+			}, false),
+		},
+		"at": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The minutes past the hour, or the time (HH:MM) for daily and weekly recurrences",
+		},
+		"action": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "download-and-install",
+			Description:  "The action to take",
+			ValidateFunc: validation.StringInSlice([]string{"download-only", "download-and-install"}, false),
+		},
+		"disable_new_content": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "(type=threats) Disable new App-IDs when installing the content",
+		},
+		"threshold": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Hours that content must have been available before it is installed",
+			ValidateFunc: validation.IntBetween(0, 336),
+		},
+		"new_app_threshold": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "(type=threats) Hours that content with new App-IDs must have been available before it is installed",
+			ValidateFunc: validation.IntBetween(0, 336),
+		},
+		"sync_to_peer": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Sync the content to the HA peer",
+		},
+	}
+}
+
+func loadDynamicUpdateSchedule(d *schema.ResourceData) dynamicUpdateScheduleConfig {
+	o := dynamicUpdateScheduleRecurring{
+		Threshold:       d.Get("threshold").(int),
+		NewAppThreshold: d.Get("new_app_threshold").(int),
+		Schedule: &dynamicUpdateScheduleEntry{
+			XMLName: xml.Name{Local: d.Get("recurrence").(string)},
+		},
+	}
+
+	if d.Get("sync_to_peer").(bool) {
+		o.SyncToPeer = util.YesNo(true)
+	}
+
+	switch o.Schedule.XMLName.Local {
+	case "none", "real-time":
+	default:
+		o.Schedule.DayOfWeek = d.Get("day_of_week").(string)
+		o.Schedule.At = d.Get("at").(string)
+		o.Schedule.Action = d.Get("action").(string)
+		if d.Get("disable_new_content").(bool) {
+			o.Schedule.DisableNewContent = util.YesNo(true)
+		}
+	}
+
+	return dynamicUpdateScheduleConfig{
+		XMLName:   xml.Name{Local: d.Get("type").(string)},
+		Recurring: &o,
+	}
+}
+
+func validateDynamicUpdateSchedule(typ string, o dynamicUpdateScheduleConfig) error {
+	s := o.Recurring.Schedule
+
+	if s.DayOfWeek != "" && s.XMLName.Local != "weekly" {
+		return fmt.Errorf("day_of_week is only valid for a weekly recurrence")
+	} else if s.DayOfWeek == "" && s.XMLName.Local == "weekly" {
+		return fmt.Errorf("day_of_week must be specified for a weekly recurrence")
+	}
+
+	if typ != "threats" {
+		if s.DisableNewContent != "" {
+			return fmt.Errorf("disable_new_content is only valid for type threats")
+		}
+		if o.Recurring.NewAppThreshold != 0 {
+			return fmt.Errorf("new_app_threshold is only valid for type threats")
+		}
+	}
+
+	return nil
+}
+
+func saveDynamicUpdateSchedule(d *schema.ResourceData, o dynamicUpdateScheduleRecurring) {
+	var s dynamicUpdateScheduleEntry
+
+	recurrence := "none"
+	if o.Schedule != nil {
+		s = *o.Schedule
+		recurrence = s.XMLName.Local
+	}
+
+	action := s.Action
+	if action == "" {
+		action = d.Get("action").(string)
+	}
+
+	d.Set("recurrence", recurrence)
+	d.Set("day_of_week", s.DayOfWeek)
+	d.Set("at", s.At)
+	d.Set("action", action)
+	d.Set("disable_new_content", util.AsBool(s.DisableNewContent))
+	d.Set("threshold", o.Threshold)
+	d.Set("new_app_threshold", o.NewAppThreshold)
+	d.Set("sync_to_peer", util.AsBool(o.SyncToPeer))
+}
+
+// Id functions.
+func buildDynamicUpdateScheduleId(a, b, c string) string {
+	return strings.Join([]string{a, b, c}, IdSeparator)
+}
+
+func parseDynamicUpdateScheduleId(v string) (string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2]
+}
+
+func dynamicUpdateScheduleXpath(meta interface{}, tmpl, ts, typ string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "deviceconfig", "system", "update-schedule", typ), nil
+}
+
+// Config structs.
+type dynamicUpdateScheduleConfig struct {
+	XMLName   xml.Name
+	Recurring *dynamicUpdateScheduleRecurring `xml:"recurring"`
+}
+
+type dynamicUpdateScheduleRecurring struct {
+	SyncToPeer      string                      `xml:"sync-to-peer,omitempty"`
+	Threshold       int                         `xml:"threshold,omitempty"`
+	NewAppThreshold int                         `xml:"new-app-threshold,omitempty"`
+	Schedule        *dynamicUpdateScheduleEntry `xml:",any"`
+}
+
+type dynamicUpdateScheduleEntry struct {
+	XMLName           xml.Name
+	DayOfWeek         string `xml:"day-of-week,omitempty"`
+	At                string `xml:"at,omitempty"`
+	Action            string `xml:"action,omitempty"`
+	DisableNewContent string `xml:"disable-new-content,omitempty"`
+}
+
+type dynamicUpdateScheduleAns struct {
+	Result dynamicUpdateScheduleResult `xml:"result"`
+}
+
+type dynamicUpdateScheduleResult struct {
+	Config *dynamicUpdateScheduleConfig `xml:",any"`
+}
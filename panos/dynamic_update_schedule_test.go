@@ -0,0 +1,57 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosDynamicUpdateSchedule(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDynamicUpdateScheduleConfig("daily", "", "01:15", 24),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "recurrence", "daily"),
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "at", "01:15"),
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "threshold", "24"),
+				),
+			},
+			{
+				Config: testAccDynamicUpdateScheduleConfig("weekly", "sunday", "03:30", 48),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "recurrence", "weekly"),
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "day_of_week", "sunday"),
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "at", "03:30"),
+					resource.TestCheckResourceAttr("panos_dynamic_update_schedule.test", "threshold", "48"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDynamicUpdateScheduleConfig(recurrence, day, at string, threshold int) string {
+	var dow string
+	if day != "" {
+		dow = fmt.Sprintf("day_of_week = %q", day)
+	}
+
+	return fmt.Sprintf(`
+resource "panos_dynamic_update_schedule" "test" {
+    type = "threats"
+    recurrence = %q
+    %s
+    at = %q
+    action = "download-and-install"
+    threshold = %d
+    disable_new_content = true
+}
+`, recurrence, dow, at, threshold)
+}
@@ -0,0 +1,300 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Data source.
+func dataSourceHaState() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSourceHaState,
+
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If high availability is enabled",
+			},
+			"mode": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The HA mode (firewall only)",
+			},
+			"is_active": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the local device is the active (or active-primary) member",
+			},
+			"running_sync": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The running config sync status",
+			},
+			"running_sync_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If running config sync is enabled",
+			},
+			"local": haStateInfoSchema("Local device HA state"),
+			"peer":  haStateInfoSchema("Peer device HA state"),
+		},
+	}
+}
+
+func readDataSourceHaState(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var id string
+	var o haState
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		id = con.Hostname
+		o, err = getHaState(&con.Client)
+	case *pango.Panorama:
+		id = con.Hostname
+		o, err = getHaState(&con.Client)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(id)
+	d.Set("enabled", o.Enabled)
+	d.Set("mode", o.Mode)
+	d.Set("is_active", o.IsActive())
+	d.Set("running_sync", o.RunningSync)
+	d.Set("running_sync_enabled", o.RunningSyncEnabled)
+	if err = d.Set("local", dumpHaStateInfo(o.Local)); err != nil {
+		return fmt.Errorf("Error setting 'local' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("peer", dumpHaStateInfo(o.Peer)); err != nil {
+		return fmt.Errorf("Error setting 'peer' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// Schema functions.
+func haStateInfoSchema(desc string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: desc,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"priority": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"preemptive": {
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+				"state_sync": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"management_ip": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"serial_number": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"build_release": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"connection_status": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"ha1_link_state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"ha1_backup_link_state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"ha2_link_state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"ha2_backup_link_state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+func dumpHaStateInfo(o haStateInfo) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"state":                 o.State,
+			"priority":              o.Priority,
+			"preemptive":            o.Preemptive == "yes",
+			"state_sync":            o.StateSync,
+			"management_ip":         o.MgmtIp,
+			"serial_number":         o.Serial,
+			"build_release":         o.BuildRelease,
+			"connection_status":     o.ConnStatus,
+			"ha1_link_state":        o.Ha1,
+			"ha1_backup_link_state": o.Ha1Backup,
+			"ha2_link_state":        o.Ha2,
+			"ha2_backup_link_state": o.Ha2Backup,
+		},
+	}
+}
+
+// Operational state.
+type haState struct {
+	Enabled            bool
+	Panorama           bool
+	Mode               string
+	Local              haStateInfo
+	Peer               haStateInfo
+	RunningSync        string
+	RunningSyncEnabled bool
+}
+
+// IsActive returns if the local device should receive configuration.
+//
+// For active/active firewalls this is the active-primary member, which is
+// the member that owns config sync.  Panorama reports its state as
+// primary-active or secondary-active, only one of which is ever active.
+// Standalone devices are always active.
+func (o haState) IsActive() bool {
+	if !o.Enabled {
+		return true
+	}
+
+	state := strings.ToLower(o.Local.State)
+	if o.Panorama {
+		return state == "primary-active" || state == "secondary-active"
+	}
+
+	return state == "active" || state == "active-primary"
+}
+
+type haStateInfo struct {
+	State        string `xml:"state"`
+	Priority     string `xml:"priority"`
+	Preemptive   string `xml:"preemptive"`
+	StateSync    string `xml:"state-sync"`
+	MgmtIp       string `xml:"mgmt-ip"`
+	Serial       string `xml:"serial-num"`
+	BuildRelease string `xml:"build-rel"`
+	ConnStatus   string `xml:"conn-status"`
+	Ha1          string `xml:"conn-ha1>conn-status"`
+	Ha1Backup    string `xml:"conn-ha1-backup>conn-status"`
+	Ha2          string `xml:"conn-ha2>conn-status"`
+	Ha2Backup    string `xml:"conn-ha2-backup>conn-status"`
+}
+
+type haStateGroup struct {
+	Mode               string       `xml:"mode"`
+	Local              *haStateInfo `xml:"local-info"`
+	Peer               *haStateInfo `xml:"peer-info"`
+	RunningSync        string       `xml:"running-sync"`
+	RunningSyncEnabled string       `xml:"running-sync-enabled"`
+}
+
+type haStateReq struct {
+	XMLName xml.Name `xml:"show"`
+	Cmd     string   `xml:"high-availability>state"`
+}
+
+// Firewalls nest the state under "group", while Panorama does not.
+type haStateAns struct {
+	Result haStateResult `xml:"result"`
+}
+
+type haStateResult struct {
+	haStateGroup
+	Enabled string        `xml:"enabled"`
+	Group   *haStateGroup `xml:"group"`
+}
+
+func getHaState(c *pango.Client) (haState, error) {
+	var ans haStateAns
+
+	c.LogOp("(op) show high-availability state")
+	if _, err := c.Op(haStateReq{}, "", nil, &ans); err != nil {
+		return haState{}, err
+	}
+
+	grp := ans.Result.Group
+	if grp == nil {
+		grp = &ans.Result.haStateGroup
+	}
+
+	o := haState{
+		Enabled:            ans.Result.Enabled == "yes",
+		Panorama:           ans.Result.Group == nil,
+		Mode:               grp.Mode,
+		RunningSync:        grp.RunningSync,
+		RunningSyncEnabled: grp.RunningSyncEnabled == "yes",
+	}
+	if grp.Local != nil {
+		o.Local = *grp.Local
+	}
+	if grp.Peer != nil {
+		o.Peer = *grp.Peer
+	}
+
+	return o, nil
+}
+
+// connectToActiveHaPeer connects to each of the given HA peers in turn and
+// returns the connection to the first one that is active.
+func connectToActiveHaPeer(c pango.Client, filename string, peers []string) (interface{}, error) {
+	errs := make([]string, 0, len(peers))
+
+	for _, peer := range peers {
+		var o haState
+		c.Hostname = peer
+
+		con, err := pango.ConnectUsing(c, filename, true)
+		if err == nil {
+			switch x := con.(type) {
+			case *pango.Firewall:
+				// HA state must come from the peer itself, not a target.
+				tgt := x.Target
+				x.Target = ""
+				o, err = getHaState(&x.Client)
+				x.Target = tgt
+			case *pango.Panorama:
+				tgt := x.Target
+				x.Target = ""
+				o, err = getHaState(&x.Client)
+				x.Target = tgt
+			}
+		}
+
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("%s: %s", peer, err))
+		case o.IsActive():
+			return con, nil
+		default:
+			errs = append(errs, fmt.Sprintf("%s: HA state is %q", peer, o.Local.State))
+		}
+	}
+
+	return nil, fmt.Errorf("No active HA peer found: %s", strings.Join(errs, "; "))
+}
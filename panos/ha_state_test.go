@@ -0,0 +1,61 @@
+package panos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosDsHaState(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsHaStateConfig(),
+				Check: checkDataSource("panos_ha_state", []string{
+					"enabled", "is_active",
+				}),
+			},
+		},
+	})
+}
+
+func TestHaStateIsActive(t *testing.T) {
+	testCases := []struct {
+		enabled  bool
+		panorama bool
+		state    string
+		ans      bool
+	}{
+		{false, false, "", true},
+		{true, false, "active", true},
+		{true, false, "passive", false},
+		{true, false, "active-primary", true},
+		{true, false, "Active-Primary", true},
+		{true, false, "active-secondary", false},
+		{true, false, "primary-active", false},
+		{true, false, "secondary-active", false},
+		{true, false, "suspended", false},
+		{true, false, "initial", false},
+		{false, true, "", true},
+		{true, true, "primary-active", true},
+		{true, true, "secondary-active", true},
+		{true, true, "primary-passive", false},
+		{true, true, "secondary-passive", false},
+		{true, true, "active", false},
+	}
+
+	for _, tc := range testCases {
+		o := haState{Enabled: tc.enabled, Panorama: tc.panorama, Local: haStateInfo{State: tc.state}}
+		if ans := o.IsActive(); ans != tc.ans {
+			t.Errorf("enabled:%t panorama:%t state:%q: expected %t, got %t", tc.enabled, tc.panorama, tc.state, tc.ans, ans)
+		}
+	}
+}
+
+func testAccDsHaStateConfig() string {
+	return `
+data "panos_ha_state" "test" {}
+`
+}
@@ -0,0 +1,297 @@
+package panos
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Data source.
+func dataSourceInitCfg() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSourceInitCfg,
+
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "dhcp-client",
+				Description:  "The management interface type",
+				ValidateFunc: validation.StringInSlice([]string{"dhcp-client", "static"}, false),
+			},
+			"ip_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(type=static) The management IP address",
+			},
+			"netmask": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(type=static) The management netmask",
+			},
+			"default_gateway": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(type=static) The management default gateway",
+			},
+			"ipv6_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(type=static) The management IPv6 address",
+			},
+			"ipv6_default_gateway": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(type=static) The management IPv6 default gateway",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The hostname",
+			},
+			"vm_auth_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The VM auth key from Panorama",
+			},
+			"panorama_server": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The primary Panorama server",
+			},
+			"panorama_server_2": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The secondary Panorama server",
+			},
+			"tplname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Panorama template stack",
+			},
+			"dgname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Panorama device group",
+			},
+			"cgname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Panorama log collector group",
+			},
+			"dns_primary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The primary DNS server",
+			},
+			"dns_secondary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The secondary DNS server",
+			},
+			"auth_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "License auth codes",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"op_command_modes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Op command modes, such as multi-vsys or jumbo-frame",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"plugin_op_commands": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Plugin op commands, such as aws-gwlb-inspect:enable",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"dhcp_send_hostname": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "(type=dhcp-client) Send the hostname to the DHCP server",
+			},
+			"dhcp_send_client_id": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "(type=dhcp-client) Send the client ID to the DHCP server",
+			},
+			"dhcp_accept_server_hostname": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "(type=dhcp-client) Accept the hostname from the DHCP server",
+			},
+			"dhcp_accept_server_domain": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "(type=dhcp-client) Accept the domain from the DHCP server",
+			},
+			"additional_params": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Other init-cfg.txt params",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			// Bootstrap directory variables.
+			"bootstrap_directory": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Write a bootstrap package to this local directory",
+			},
+			"bootstrap_xml": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The bootstrap.xml to include in the bootstrap package",
+			},
+
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The init-cfg.txt content",
+			},
+		},
+	}
+}
+
+func readDataSourceInitCfg(d *schema.ResourceData, meta interface{}) error {
+	content, err := renderInitCfg(d)
+	if err != nil {
+		return err
+	}
+
+	if dir := d.Get("bootstrap_directory").(string); dir != "" {
+		authCodes := asStringList(d.Get("auth_codes").([]interface{}))
+		if err = writeBootstrapDirectory(dir, content, d.Get("bootstrap_xml").(string), authCodes); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(content))))
+	d.Set("content", content)
+
+	return nil
+}
+
+// initCfgParams are the init-cfg.txt params that have their own attribute,
+// and so cannot be given in additional_params.
+var initCfgParams = []string{
+	"type", "ip-address", "default-gateway", "netmask", "ipv6-address",
+	"ipv6-default-gateway", "hostname", "vm-auth-key", "panorama-server",
+	"panorama-server-2", "tplname", "dgname", "cgname", "dns-primary",
+	"dns-secondary", "authcodes", "op-command-modes", "plugin-op-commands",
+	"dhcp-send-hostname", "dhcp-send-client-id",
+	"dhcp-accept-server-hostname", "dhcp-accept-server-domain",
+}
+
+// renderInitCfg returns the init-cfg.txt content.
+func renderInitCfg(d *schema.ResourceData) (string, error) {
+	var b strings.Builder
+
+	add := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s=%s\n", key, value)
+		}
+	}
+	addBool := func(key, param string) {
+		if v, ok := d.GetOk(param); ok && v.(bool) {
+			add(key, "yes")
+		}
+	}
+	addList := func(key, param string) {
+		add(key, strings.Join(asStringList(d.Get(param).([]interface{})), ","))
+	}
+
+	typ := d.Get("type").(string)
+	add("type", typ)
+	if typ == "static" {
+		add("ip-address", d.Get("ip_address").(string))
+		add("default-gateway", d.Get("default_gateway").(string))
+		add("netmask", d.Get("netmask").(string))
+		add("ipv6-address", d.Get("ipv6_address").(string))
+		add("ipv6-default-gateway", d.Get("ipv6_default_gateway").(string))
+	}
+	add("hostname", d.Get("hostname").(string))
+	add("vm-auth-key", d.Get("vm_auth_key").(string))
+	add("panorama-server", d.Get("panorama_server").(string))
+	add("panorama-server-2", d.Get("panorama_server_2").(string))
+	add("tplname", d.Get("tplname").(string))
+	add("dgname", d.Get("dgname").(string))
+	add("cgname", d.Get("cgname").(string))
+	add("dns-primary", d.Get("dns_primary").(string))
+	add("dns-secondary", d.Get("dns_secondary").(string))
+	addList("authcodes", "auth_codes")
+	addList("op-command-modes", "op_command_modes")
+	addList("plugin-op-commands", "plugin_op_commands")
+	if typ == "dhcp-client" {
+		addBool("dhcp-send-hostname", "dhcp_send_hostname")
+		addBool("dhcp-send-client-id", "dhcp_send_client_id")
+		addBool("dhcp-accept-server-hostname", "dhcp_accept_server_hostname")
+		addBool("dhcp-accept-server-domain", "dhcp_accept_server_domain")
+	}
+
+	extra := d.Get("additional_params").(map[string]interface{})
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		for _, param := range initCfgParams {
+			if k == param {
+				return "", fmt.Errorf("additional_params: %q has its own attribute, set that instead", k)
+			}
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		add(k, extra[k].(string))
+	}
+
+	return b.String(), nil
+}
+
+// writeBootstrapDirectory writes the bootstrap package directory layout.
+func writeBootstrapDirectory(dir, initCfg, bootstrapXml string, authCodes []string) error {
+	for _, sub := range []string{"config", "content", "license", "software"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "config", "init-cfg.txt"), []byte(initCfg), 0600); err != nil {
+		return err
+	}
+
+	if bootstrapXml != "" {
+		if err := ioutil.WriteFile(filepath.Join(dir, "config", "bootstrap.xml"), []byte(bootstrapXml), 0600); err != nil {
+			return err
+		}
+	}
+
+	if len(authCodes) > 0 {
+		data := strings.Join(authCodes, "\n") + "\n"
+		if err := ioutil.WriteFile(filepath.Join(dir, "license", "authcodes"), []byte(data), 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
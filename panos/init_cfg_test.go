@@ -0,0 +1,127 @@
+package panos
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccPanosDsInitCfg_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsInitCfgConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.panos_init_cfg.test", "content", `type=static
+ip-address=10.0.0.10
+default-gateway=10.0.0.1
+netmask=255.255.255.0
+hostname=fw01
+panorama-server=10.0.0.5
+tplname=stack1
+dgname=dg1
+op-command-modes=multi-vsys,jumbo-frame
+`),
+				),
+			},
+		},
+	})
+}
+
+func TestRenderInitCfg(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceInitCfg().Schema, map[string]interface{}{
+		"hostname":                    "fw01",
+		"vm_auth_key":                 "123456789",
+		"auth_codes":                  []interface{}{"I1234567", "I7654321"},
+		"dhcp_send_hostname":          true,
+		"dhcp_send_client_id":         true,
+		"dhcp_accept_server_hostname": false,
+		"ip_address":                  "10.0.0.10",
+		"additional_params": map[string]interface{}{
+			"vm-series-auto-registration-pin-value": "abc",
+		},
+	})
+
+	expected := `type=dhcp-client
+hostname=fw01
+vm-auth-key=123456789
+authcodes=I1234567,I7654321
+dhcp-send-hostname=yes
+dhcp-send-client-id=yes
+vm-series-auto-registration-pin-value=abc
+`
+
+	ans, err := renderInitCfg(d)
+	if err != nil {
+		t.Fatalf("Error in render: %s", err)
+	}
+	if ans != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, ans)
+	}
+}
+
+func TestRenderInitCfgDuplicateParam(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceInitCfg().Schema, map[string]interface{}{
+		"additional_params": map[string]interface{}{
+			"dhcp-send-client-id": "yes",
+		},
+	})
+
+	if _, err := renderInitCfg(d); err == nil {
+		t.Errorf("Expected an error for a param that has its own attribute")
+	}
+}
+
+func TestWriteBootstrapDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootstrap")
+	if err != nil {
+		t.Fatalf("Error making temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = writeBootstrapDirectory(dir, "type=dhcp-client\n", "<config/>", []string{"I1", "I2"}); err != nil {
+		t.Fatalf("Error writing bootstrap directory: %s", err)
+	}
+
+	for _, sub := range []string{"config", "content", "license", "software"} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err != nil || !info.IsDir() {
+			t.Errorf("Missing directory %q", sub)
+		}
+	}
+
+	files := map[string]string{
+		filepath.Join("config", "init-cfg.txt"):  "type=dhcp-client\n",
+		filepath.Join("config", "bootstrap.xml"): "<config/>",
+		filepath.Join("license", "authcodes"):    "I1\nI2\n",
+	}
+	for name, expected := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("Error reading %q: %s", name, err)
+		} else if string(b) != expected {
+			t.Errorf("%q: expected %q, got %q", name, expected, string(b))
+		}
+	}
+}
+
+func testAccDsInitCfgConfig() string {
+	return `
+data "panos_init_cfg" "test" {
+    type = "static"
+    ip_address = "10.0.0.10"
+    netmask = "255.255.255.0"
+    default_gateway = "10.0.0.1"
+    hostname = "fw01"
+    panorama_server = "10.0.0.5"
+    tplname = "stack1"
+    dgname = "dg1"
+    op_command_modes = ["multi-vsys", "jumbo-frame"]
+}
+`
+}
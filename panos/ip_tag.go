@@ -8,6 +8,7 @@ import (
 	"github.com/fpluchorg/pango/userid"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // Data source.
@@ -131,63 +132,48 @@ func resourceIpTag() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Seconds until the tags expire",
+				ValidateFunc: validation.IntBetween(0, 2592000),
+			},
+			"persistent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Keep the tags across reboots",
+			},
+			"remaining_lifetime": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds until the tags expire",
+			},
 		},
 	}
 }
 
 func createIpTag(d *schema.ResourceData, meta interface{}) error {
-	var cur map[string][]string
-	var err error
-
 	vsys := d.Get("vsys").(string)
 	ip := d.Get("ip").(string)
 	tagList := d.Get("tags").(*schema.Set).List()
 
-	switch con := meta.(type) {
-	case *pango.Firewall:
-		cur, err = con.UserId.GetIpTags(ip, "", vsys)
-	case *pango.Panorama:
-		cur, err = con.UserId.GetIpTags(ip, "", vsys)
+	// All tags are registered, even if already present, so that the timeout
+	// and persistence are applied to them.
+	reg := ipTagRegistration{
+		Ip:         ip,
+		Tags:       make([]string, 0, len(tagList)),
+		Timeout:    d.Get("timeout").(int),
+		Persistent: d.Get("persistent").(bool),
 	}
-
-	curTags := cur[ip]
-
-	missing := make([]string, 0, len(tagList))
 	for i := range tagList {
-		tag := tagList[i].(string)
-		var found bool
-		for _, x := range curTags {
-			if x == tag {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			missing = append(missing, tag)
-		}
+		reg.Tags = append(reg.Tags, tagList[i].(string))
 	}
 
-	if len(missing) > 0 {
-		msg := &userid.Message{
-			TagIps: []userid.TagIp{
-				userid.TagIp{
-					Ip:   ip,
-					Tags: missing,
-				},
-			},
-		}
-
-		switch con := meta.(type) {
-		case *pango.Firewall:
-			err = con.UserId.Run(msg, vsys)
-		case *pango.Panorama:
-			err = con.UserId.Run(msg, vsys)
-		}
-
-		if err != nil {
-			return err
-		}
+	if err := registerIpTags(rawClient(meta), vsys, []ipTagRegistration{reg}); err != nil {
+		return err
 	}
 
 	d.SetId(buildIpTagId(vsys, ip, tagList))
@@ -196,32 +182,20 @@ func createIpTag(d *schema.ResourceData, meta interface{}) error {
 }
 
 func readIpTag(d *schema.ResourceData, meta interface{}) error {
-	var cur map[string][]string
-	var err error
-
 	vsys, ip, tagList := parseIpTagId(d.Id())
 
-	switch con := meta.(type) {
-	case *pango.Firewall:
-		cur, err = con.UserId.GetIpTags(ip, "", vsys)
-	case *pango.Panorama:
-		cur, err = con.UserId.GetIpTags(ip, "", vsys)
-	}
-
+	cur, err := getRegisteredIpTags(rawClient(meta), ip, vsys)
 	if err != nil || len(cur) == 0 {
 		d.SetId("")
 		return nil
 	}
 
-	curTags := cur[ip]
+	info := cur[ip]
 
 	list := make([]string, 0, len(tagList))
 	for _, tag := range tagList {
-		for _, curTag := range curTags {
-			if tag == curTag {
-				list = append(list, tag)
-				break
-			}
+		if info.Has(tag) {
+			list = append(list, tag)
 		}
 	}
 
@@ -231,6 +205,10 @@ func readIpTag(d *schema.ResourceData, meta interface{}) error {
 	} else if err = d.Set("tags", listAsSet(list)); err != nil {
 		log.Printf("[WARN] Error setting 'tags' for %q: %s", d.Id(), err)
 	}
+	if info.Persistent != "" {
+		d.Set("persistent", info.Persistent == "1")
+	}
+	d.Set("remaining_lifetime", info.RemainingLifetime(list))
 
 	return nil
 }
@@ -78,6 +78,30 @@ func TestAccPanosIpTag(t *testing.T) {
 	})
 }
 
+func TestAccPanosIpTag_timeout(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	ip := fmt.Sprintf("10.1.59.%d", acctest.RandInt()%50+50)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosIpTagDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIpTagTimeoutConfig(ip, 3600, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_ip_tag.test", "timeout", "3600"),
+					resource.TestCheckResourceAttr("panos_ip_tag.test", "persistent", "false"),
+					resource.TestCheckResourceAttrSet("panos_ip_tag.test", "remaining_lifetime"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckPanosIpTagExists(n string, o *map[string][]string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -175,3 +199,14 @@ resource "panos_ip_tag" "test" {
 }
 `, ip, tag1, tag2)
 }
+
+func testAccIpTagTimeoutConfig(ip string, timeout int, persistent bool) string {
+	return fmt.Sprintf(`
+resource "panos_ip_tag" "test" {
+    ip = %q
+    tags = ["timeout"]
+    timeout = %d
+    persistent = %t
+}
+`, ip, timeout, persistent)
+}
@@ -0,0 +1,403 @@
+package panos
+
+import (
+	"encoding/xml"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/objs/profile/logfwd/matchlist/action"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Valid values for the log settings kind.
+const (
+	logSettingsSystem        = "system"
+	logSettingsConfig        = "config"
+	logSettingsUserId        = "userid"
+	logSettingsHipMatch      = "hipmatch"
+	logSettingsGlobalProtect = "globalprotect"
+	logSettingsIpTag         = "iptag"
+	logSettingsCorrelation   = "correlation"
+)
+
+// Resources.
+func resourceLogSettingsSystem() *schema.Resource {
+	return logSettingsResource(logSettingsSystem)
+}
+
+func resourceLogSettingsConfig() *schema.Resource {
+	return logSettingsResource(logSettingsConfig)
+}
+
+func resourceLogSettingsUserId() *schema.Resource {
+	return logSettingsResource(logSettingsUserId)
+}
+
+func resourceLogSettingsHipMatch() *schema.Resource {
+	return logSettingsResource(logSettingsHipMatch)
+}
+
+func resourceLogSettingsGlobalProtect() *schema.Resource {
+	return logSettingsResource(logSettingsGlobalProtect)
+}
+
+func resourceLogSettingsIpTag() *schema.Resource {
+	return logSettingsResource(logSettingsIpTag)
+}
+
+func resourceLogSettingsCorrelation() *schema.Resource {
+	return logSettingsResource(logSettingsCorrelation)
+}
+
+func logSettingsResource(kind string) *schema.Resource {
+	return &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return createUpdateLogSettings(d, meta, kind)
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return readLogSettings(d, meta, kind)
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return createUpdateLogSettings(d, meta, kind)
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return deleteLogSettings(d, meta, kind)
+		},
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: logSettingsSchema(),
+	}
+}
+
+func createUpdateLogSettings(d *schema.ResourceData, meta interface{}, kind string) error {
+	var err error
+
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	o := loadLogSettings(d)
+
+	path, err := logSettingsXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if len(o.Entries) == 0 {
+		_, err = c.Delete(path, nil, nil)
+		if isObjectNotFound(err) {
+			err = nil
+		}
+	} else {
+		_, err = c.Edit(path, o, nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(buildLogSettingsId(tmpl, ts))
+	return readLogSettings(d, meta, kind)
+}
+
+func readLogSettings(d *schema.ResourceData, meta interface{}, kind string) error {
+	var ans logSettingsAns
+
+	tmpl, ts := parseLogSettingsId(d.Id())
+
+	path, err := logSettingsXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	saveLogSettings(d, ans.MatchList)
+
+	return nil
+}
+
+func deleteLogSettings(d *schema.ResourceData, meta interface{}, kind string) error {
+	tmpl, ts := parseLogSettingsId(d.Id())
+
+	path, err := logSettingsXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(path, nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func logSettingsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template":       templateSchema(true),
+		"template_stack": templateStackSchema(),
+		"match_list": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"description": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"filter": {
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  "All Logs",
+					},
+					"send_to_panorama": {
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+					"snmptrap_server_profiles": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"email_server_profiles": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"syslog_server_profiles": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"http_server_profiles": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+					"action": logForwardingActionSchema(),
+				},
+			},
+		},
+	}
+}
+
+func loadLogSettings(d *schema.ResourceData) logSettingsMatchList {
+	var o logSettingsMatchList
+
+	mle := d.Get("match_list").([]interface{})
+	for i := range mle {
+		mm := mle[i].(map[string]interface{})
+		entry := logSettingsEntry{
+			Name:           mm["name"].(string),
+			Description:    mm["description"].(string),
+			Filter:         mm["filter"].(string),
+			SendToPanorama: util.YesNo(mm["send_to_panorama"].(bool)),
+			SnmpProfiles:   util.StrToMem(setAsList(mm["snmptrap_server_profiles"].(*schema.Set))),
+			EmailProfiles:  util.StrToMem(setAsList(mm["email_server_profiles"].(*schema.Set))),
+			SyslogProfiles: util.StrToMem(setAsList(mm["syslog_server_profiles"].(*schema.Set))),
+			HttpProfiles:   util.StrToMem(setAsList(mm["http_server_profiles"].(*schema.Set))),
+		}
+
+		if ael := mm["action"].([]interface{}); len(ael) != 0 {
+			entry.Actions = &logSettingsActions{}
+			for _, x := range loadLogForwardingActions(ael) {
+				entry.Actions.Entries = append(entry.Actions.Entries, specifyLogSettingsAction(x))
+			}
+		}
+
+		o.Entries = append(o.Entries, entry)
+	}
+
+	return o
+}
+
+func saveLogSettings(d *schema.ResourceData, o *logSettingsMatchList) {
+	if o == nil || len(o.Entries) == 0 {
+		d.Set("match_list", nil)
+		return
+	}
+
+	mle := make([]interface{}, 0, len(o.Entries))
+	for _, entry := range o.Entries {
+		mm := map[string]interface{}{
+			"name":                     entry.Name,
+			"description":              entry.Description,
+			"filter":                   entry.Filter,
+			"send_to_panorama":         util.AsBool(entry.SendToPanorama),
+			"snmptrap_server_profiles": listAsSet(util.MemToStr(entry.SnmpProfiles)),
+			"email_server_profiles":    listAsSet(util.MemToStr(entry.EmailProfiles)),
+			"syslog_server_profiles":   listAsSet(util.MemToStr(entry.SyslogProfiles)),
+			"http_server_profiles":     listAsSet(util.MemToStr(entry.HttpProfiles)),
+		}
+
+		if entry.Actions == nil || len(entry.Actions.Entries) == 0 {
+			mm["action"] = nil
+		} else {
+			list := make([]action.Entry, 0, len(entry.Actions.Entries))
+			for _, x := range entry.Actions.Entries {
+				list = append(list, x.normalize())
+			}
+			mm["action"] = saveLogForwardingActions(list)
+		}
+
+		mle = append(mle, mm)
+	}
+
+	if err := d.Set("match_list", mle); err != nil {
+		log.Printf("[WARN] Error setting 'match_list' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildLogSettingsId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parseLogSettingsId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
+
+// logSettingsXpath returns the xpath of the match list.  Log settings are
+// part of the shared config, not the device config.
+func logSettingsXpath(meta interface{}, tmpl, ts, kind string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, "shared")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "log-settings", kind, "match-list"), nil
+}
+
+// Config structs.
+type logSettingsMatchList struct {
+	XMLName xml.Name           `xml:"match-list"`
+	Entries []logSettingsEntry `xml:"entry"`
+}
+
+type logSettingsEntry struct {
+	Name           string              `xml:"name,attr"`
+	Description    string              `xml:"description,omitempty"`
+	Filter         string              `xml:"filter"`
+	SendToPanorama string              `xml:"send-to-panorama"`
+	SnmpProfiles   *util.MemberType    `xml:"send-snmptrap"`
+	EmailProfiles  *util.MemberType    `xml:"send-email"`
+	SyslogProfiles *util.MemberType    `xml:"send-syslog"`
+	HttpProfiles   *util.MemberType    `xml:"send-http"`
+	Actions        *logSettingsActions `xml:"actions"`
+}
+
+type logSettingsActions struct {
+	Entries []logSettingsAction `xml:"entry"`
+}
+
+type logSettingsAction struct {
+	Name        string                  `xml:"name,attr"`
+	Tagging     *logSettingsTagging     `xml:"type>tagging"`
+	Integration *logSettingsIntegration `xml:"type>integration"`
+}
+
+type logSettingsTagging struct {
+	Action       string                  `xml:"action"`
+	Target       string                  `xml:"target"`
+	Registration logSettingsRegistration `xml:"registration"`
+	Tags         *util.MemberType        `xml:"tags"`
+	Timeout      int                     `xml:"timeout,omitempty"`
+}
+
+type logSettingsRegistration struct {
+	Local    *string                        `xml:"localhost"`
+	Panorama *string                        `xml:"panorama"`
+	Remote   *logSettingsRemoteRegistration `xml:"remote"`
+}
+
+type logSettingsRemoteRegistration struct {
+	HttpProfile string `xml:"http-profile"`
+}
+
+type logSettingsIntegration struct {
+	Action string `xml:"action"`
+}
+
+type logSettingsAns struct {
+	MatchList *logSettingsMatchList `xml:"result>match-list"`
+}
+
+// specifyLogSettingsAction converts the log forwarding profile action to XML.
+func specifyLogSettingsAction(e action.Entry) logSettingsAction {
+	ans := logSettingsAction{Name: e.Name}
+
+	switch e.ActionType {
+	case action.ActionTypeTagging:
+		s := ""
+		ans.Tagging = &logSettingsTagging{
+			Action:  e.Action,
+			Target:  e.Target,
+			Tags:    util.StrToMem(e.Tags),
+			Timeout: e.Timeout,
+		}
+		switch e.Registration {
+		case action.RegistrationLocal:
+			ans.Tagging.Registration.Local = &s
+		case action.RegistrationPanorama:
+			ans.Tagging.Registration.Panorama = &s
+		case action.RegistrationRemote:
+			ans.Tagging.Registration.Remote = &logSettingsRemoteRegistration{HttpProfile: e.HttpProfile}
+		}
+	case action.ActionTypeIntegration:
+		ans.Integration = &logSettingsIntegration{Action: e.Action}
+	}
+
+	return ans
+}
+
+func (o logSettingsAction) normalize() action.Entry {
+	ans := action.Entry{Name: o.Name}
+
+	if o.Tagging != nil {
+		ans.ActionType = action.ActionTypeTagging
+		ans.Action = o.Tagging.Action
+		ans.Target = o.Tagging.Target
+		ans.Tags = util.MemToStr(o.Tagging.Tags)
+		ans.Timeout = o.Tagging.Timeout
+		switch {
+		case o.Tagging.Registration.Local != nil:
+			ans.Registration = action.RegistrationLocal
+		case o.Tagging.Registration.Panorama != nil:
+			ans.Registration = action.RegistrationPanorama
+		case o.Tagging.Registration.Remote != nil:
+			ans.Registration = action.RegistrationRemote
+			ans.HttpProfile = o.Tagging.Registration.Remote.HttpProfile
+		}
+	} else if o.Integration != nil {
+		ans.ActionType = action.ActionTypeIntegration
+		ans.Action = o.Integration.Action
+	}
+
+	return ans
+}
@@ -0,0 +1,84 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosLogSettingsSystem(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosLogSettingsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLogSettingsSystemConfig(name, "(severity eq critical)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_log_settings_system.test", "match_list.#", "1"),
+					resource.TestCheckResourceAttr("panos_log_settings_system.test", "match_list.0.filter", "(severity eq critical)"),
+					resource.TestCheckResourceAttr("panos_log_settings_system.test", "match_list.0.syslog_server_profiles.#", "1"),
+				),
+			},
+			{
+				Config: testAccLogSettingsSystemConfig(name, "(severity eq high)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_log_settings_system.test", "match_list.0.filter", "(severity eq high)"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosLogSettingsDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_log_settings_system" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts := parseLogSettingsId(rs.Primary.ID)
+			path, err := logSettingsXpath(testAccProvider.Meta(), tmpl, ts, logSettingsSystem)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(path, nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccLogSettingsSystemConfig(name, filter string) string {
+	return fmt.Sprintf(`
+resource "panos_syslog_server_profile" "test" {
+    vsys = "shared"
+    name = %q
+    syslog_server {
+        name = "server"
+        server = "10.1.1.1"
+    }
+}
+
+resource "panos_log_settings_system" "test" {
+    match_list {
+        name = "critical"
+        filter = %q
+        syslog_server_profiles = [panos_syslog_server_profile.test.name]
+    }
+}
+`, name, filter)
+}
@@ -0,0 +1,322 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceMfaServerProfile() *schema.Resource {
+	return &schema.Resource{
+		Create: createMfaServerProfile,
+		Read:   readMfaServerProfile,
+		Update: updateMfaServerProfile,
+		Delete: deleteMfaServerProfile,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("shared"),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name",
+			},
+			"certificate_profile": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The certificate profile to verify the MFA vendor's certificate",
+			},
+			"vendor": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The MFA vendor type, such as duo-security-v2 or okta-adaptive-v1",
+			},
+			"config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Vendor specific settings",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_config": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Vendor specific settings that PAN-OS encrypts, such as API keys",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_config_enc": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_config_raw": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func createMfaServerProfile(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadMfaServerProfile(d)
+
+	path, err := mfaServerProfileXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("MFA server profile %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildMfaServerProfileId(tmpl, ts, vsys, o.Name))
+	if err = saveMfaServerProfileSecrets(d, meta); err != nil {
+		return err
+	}
+
+	return readMfaServerProfile(d, meta)
+}
+
+func readMfaServerProfile(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, _ := parseMfaServerProfileId(d.Id())
+
+	o, err := getMfaServerProfile(d, meta)
+	if err != nil {
+		return err
+	}
+	if o == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveMfaServerProfile(d, *o)
+
+	return nil
+}
+
+func updateMfaServerProfile(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseMfaServerProfileId(d.Id())
+	o := loadMfaServerProfile(d)
+
+	path, err := mfaServerProfileXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	if err = saveMfaServerProfileSecrets(d, meta); err != nil {
+		return err
+	}
+
+	return readMfaServerProfile(d, meta)
+}
+
+func deleteMfaServerProfile(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseMfaServerProfileId(d.Id())
+
+	path, err := mfaServerProfileXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadMfaServerProfile(d *schema.ResourceData) mfaServerProfileEntry {
+	o := mfaServerProfileEntry{
+		Name:               d.Get("name").(string),
+		CertificateProfile: d.Get("certificate_profile").(string),
+		Vendor:             d.Get("vendor").(string),
+	}
+
+	var list []mfaServerProfileConfig
+	for _, key := range []string{"config", "secret_config"} {
+		for k, v := range d.Get(key).(map[string]interface{}) {
+			list = append(list, mfaServerProfileConfig{Name: k, Value: v.(string)})
+		}
+	}
+	if len(list) != 0 {
+		o.Config = &mfaServerProfileConfigs{Entries: list}
+	}
+
+	return o
+}
+
+// saveMfaServerProfileSecrets saves the encrypted form of the secret config
+// values so that drift can be detected.
+func saveMfaServerProfileSecrets(d *schema.ResourceData, meta interface{}) error {
+	o, err := getMfaServerProfile(d, meta)
+	if err != nil {
+		return err
+	}
+
+	cur := make(map[string]string)
+	if o != nil && o.Config != nil {
+		for _, x := range o.Config.Entries {
+			cur[x.Name] = x.Value
+		}
+	}
+
+	raw := make(map[string]interface{})
+	enc := make(map[string]interface{})
+	for k, v := range d.Get("secret_config").(map[string]interface{}) {
+		raw[k] = v
+		enc[k] = cur[k]
+	}
+
+	if err = d.Set("secret_config_raw", raw); err != nil {
+		log.Printf("[WARN] Error setting 'secret_config_raw' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("secret_config_enc", enc); err != nil {
+		log.Printf("[WARN] Error setting 'secret_config_enc' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func saveMfaServerProfile(d *schema.ResourceData, o mfaServerProfileEntry) {
+	var err error
+
+	enc := d.Get("secret_config_enc").(map[string]interface{})
+	raw := d.Get("secret_config_raw").(map[string]interface{})
+
+	config := make(map[string]interface{})
+	secrets := make(map[string]interface{})
+	if o.Config != nil {
+		for _, x := range o.Config.Entries {
+			if _, ok := raw[x.Name]; !ok {
+				config[x.Name] = x.Value
+			} else if enc[x.Name] != nil && enc[x.Name].(string) == x.Value {
+				secrets[x.Name] = raw[x.Name]
+			} else {
+				secrets[x.Name] = "(incorrect value)"
+			}
+		}
+	}
+
+	d.Set("name", o.Name)
+	d.Set("certificate_profile", o.CertificateProfile)
+	d.Set("vendor", o.Vendor)
+	if err = d.Set("config", config); err != nil {
+		log.Printf("[WARN] Error setting 'config' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("secret_config", secrets); err != nil {
+		log.Printf("[WARN] Error setting 'secret_config' for %q: %s", d.Id(), err)
+	}
+}
+
+// getMfaServerProfile returns the profile, or nil if it does not exist.
+func getMfaServerProfile(d *schema.ResourceData, meta interface{}) (*mfaServerProfileEntry, error) {
+	var ans mfaServerProfileAns
+
+	tmpl, ts, vsys, name := parseMfaServerProfileId(d.Id())
+
+	path, err := mfaServerProfileXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return ans.Entry, nil
+}
+
+// Id functions.
+func buildMfaServerProfileId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseMfaServerProfileId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func mfaServerProfileXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "server-profile", "mfa-server"), nil
+}
+
+// Config structs.
+type mfaServerProfileEntry struct {
+	XMLName            xml.Name                 `xml:"entry"`
+	Name               string                   `xml:"name,attr"`
+	CertificateProfile string                   `xml:"mfa-cert-profile"`
+	Vendor             string                   `xml:"mfa-vendor-type"`
+	Config             *mfaServerProfileConfigs `xml:"mfa-config"`
+}
+
+type mfaServerProfileConfigs struct {
+	Entries []mfaServerProfileConfig `xml:"entry"`
+}
+
+type mfaServerProfileConfig struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type mfaServerProfileAns struct {
+	Entry *mfaServerProfileEntry `xml:"result>entry"`
+}
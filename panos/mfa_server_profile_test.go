@@ -0,0 +1,101 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosMfaServerProfile(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosMfaServerProfileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMfaServerProfileConfig(name, "api-1234.duosecurity.com", "30", "secret1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "vendor", "duo-security-v2"),
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "config.duo-api-host", "api-1234.duosecurity.com"),
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "config.duo-timeout", "30"),
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "secret_config.duo-secret-key", "secret1"),
+					resource.TestCheckResourceAttrSet("panos_mfa_server_profile.test", "secret_config_enc.duo-secret-key"),
+				),
+			},
+			{
+				Config: testAccMfaServerProfileConfig(name, "api-5678.duosecurity.com", "60", "secret2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "config.duo-api-host", "api-5678.duosecurity.com"),
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "config.duo-timeout", "60"),
+					resource.TestCheckResourceAttr("panos_mfa_server_profile.test", "secret_config.duo-secret-key", "secret2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosMfaServerProfileDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_mfa_server_profile" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, vsys, name := parseMfaServerProfileId(rs.Primary.ID)
+			path, err := mfaServerProfileXpath(testAccProvider.Meta(), tmpl, ts, vsys)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccMfaServerProfileConfig(name, host, timeout, secret string) string {
+	return fmt.Sprintf(`
+resource "panos_certificate" "test" {
+    name = %q
+    common_name = "tf acctest mfa"
+    ca = true
+}
+
+resource "panos_certificate_profile" "test" {
+    name = %q
+    certificate {
+        name = panos_certificate.test.name
+    }
+}
+
+resource "panos_mfa_server_profile" "test" {
+    name = %q
+    certificate_profile = panos_certificate_profile.test.name
+    vendor = "duo-security-v2"
+    config = {
+        "duo-api-host" = %q
+        "duo-integration-key" = "DIXXXXXXXXXXXXXXXXXX"
+        "duo-timeout" = %q
+        "duo-baseuri" = "/auth/v2"
+    }
+    secret_config = {
+        "duo-secret-key" = %q
+    }
+}
+`, name, name, name, host, timeout, secret)
+}
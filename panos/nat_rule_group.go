@@ -121,6 +121,8 @@ func resourceNatRuleGroup() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: natRuleGroupCustomizeDiff,
+
 		Schema: natRuleGroupSchema(true, []string{"device_group", "rulebase"}),
 	}
 }
@@ -138,6 +140,8 @@ func resourcePanoramaNatRuleGroup() *schema.Resource {
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: natRuleGroupCustomizeDiff,
+
 		Schema: natRuleGroupSchema(true, []string{"vsys"}),
 	}
 }
@@ -670,7 +674,14 @@ func natRuleGroupSchema(isResource bool, rmKeys []string) map[string]*schema.Sch
 		delete(ans, rmKey)
 	}
 
-	if !isResource {
+	if isResource {
+		ans["plan_validation"] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Cross-check referenced zones, interfaces, and addresses during plan",
+		}
+	} else {
 		delete(ans, "position_keyword")
 		delete(ans, "position_reference")
 		ans["name"] = &schema.Schema{
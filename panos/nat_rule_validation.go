@@ -0,0 +1,409 @@
+package panos
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/netw/interface/eth"
+	"github.com/fpluchorg/pango/netw/interface/subinterface/layer3"
+	"github.com/fpluchorg/pango/netw/zone"
+	"github.com/fpluchorg/pango/objs/addr"
+	"github.com/fpluchorg/pango/objs/addrgrp"
+	"github.com/fpluchorg/pango/poli/nat"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// natRuleGroupCustomizeDiff validates NAT rules at plan time.
+//
+// Structural problems are always reported.  If "plan_validation" is enabled,
+// the zones, interfaces, and address objects referenced are also checked
+// against what is currently configured.  Referenced objects that do not
+// exist yet are skipped, as they may be created in the same apply.
+func natRuleGroupCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("rule") {
+		return nil
+	}
+
+	v := &natRuleValidator{
+		meta:  meta,
+		check: d.Get("plan_validation").(bool),
+	}
+	switch meta.(type) {
+	case *pango.Firewall:
+		v.loc = d.Get("vsys").(string)
+	case *pango.Panorama:
+		v.loc = d.Get("device_group").(string)
+	}
+
+	rlist := d.Get("rule").([]interface{})
+	for i := range rlist {
+		x, ok := rlist[i].(map[string]interface{})
+		if !ok || len(x["original_packet"].([]interface{})) == 0 || len(x["translated_packet"].([]interface{})) == 0 {
+			continue
+		}
+		if err := v.validate(loadNatEntry(x)); err != nil {
+			return err
+		}
+	}
+
+	if len(v.problems) != 0 {
+		return fmt.Errorf("NAT rule validation failed:\n%s", strings.Join(v.problems, "\n"))
+	}
+
+	return nil
+}
+
+type natRuleValidator struct {
+	meta     interface{}
+	loc      string
+	check    bool
+	problems []string
+
+	// Lazily loaded device config.
+	zones    map[string]zone.Entry
+	groups   map[string][]string
+	addrs    map[string]addr.Entry
+	ifaceIps map[string][]string
+}
+
+func (v *natRuleValidator) addf(rule, msg string, i ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf("rule %q: %s", rule, fmt.Sprintf(msg, i...)))
+}
+
+func (v *natRuleValidator) validate(o nat.Entry) error {
+	var err error
+
+	v.validateType(o)
+
+	if o.SatType == nat.StaticIp && o.SatStaticBiDirectional {
+		if o.DatType != "" {
+			v.addf(o.Name, "bi-directional static NAT cannot also do destination translation")
+		}
+		if v.check {
+			names := append([]string{o.SatStaticTranslatedAddress}, o.SourceAddresses...)
+			for _, name := range names {
+				var isGroup bool
+				if isGroup, err = v.isAddressGroup(name); err != nil {
+					return err
+				} else if isGroup {
+					v.addf(o.Name, "bi-directional static NAT cannot use address group %q", name)
+				}
+			}
+		}
+	}
+
+	if !v.check {
+		return nil
+	}
+
+	if _, ok := v.meta.(*pango.Firewall); !ok {
+		// On Panorama, zones and interfaces live in templates, and a device
+		// group is not tied to any one template, so there is no single
+		// config to check the rule against.
+		log.Printf("[DEBUG] NAT rule %q: skipping zone and interface checks on Panorama", o.Name)
+		return nil
+	}
+
+	if err = v.validateZones(o); err != nil {
+		return err
+	}
+
+	return v.validatePools(o)
+}
+
+func (v *natRuleValidator) validateType(o nat.Entry) {
+	switch o.Type {
+	case nat.TypeNat64:
+		if o.SatType != nat.DynamicIpAndPort && o.SatType != nat.None && o.SatType != "" {
+			v.addf(o.Name, "nat64 rules only support dynamic_ip_and_port source translation")
+		}
+		if o.DatType == nat.DatTypeDynamic {
+			v.addf(o.Name, "nat64 rules do not support dynamic destination translation")
+		}
+	case nat.TypeNptv6:
+		if o.SatType != nat.StaticIp && o.SatType != nat.None && o.SatType != "" {
+			v.addf(o.Name, "nptv6 rules only support static_ip source translation")
+		}
+		if o.DatType == nat.DatTypeDynamic {
+			v.addf(o.Name, "nptv6 rules do not support dynamic destination translation")
+		}
+		if o.DatPort != 0 {
+			v.addf(o.Name, "nptv6 rules do not support port translation")
+		}
+	}
+}
+
+func (v *natRuleValidator) validateZones(o nat.Entry) error {
+	if v.zones == nil {
+		fw := v.meta.(*pango.Firewall)
+		list, err := fw.Network.Zone.GetAll(v.loc)
+		if err != nil && !isObjectNotFound(err) {
+			return err
+		}
+		v.zones = make(map[string]zone.Entry, len(list))
+		for _, z := range list {
+			v.zones[z.Name] = z
+		}
+	}
+
+	dz, ok := v.zones[o.DestinationZone]
+	if !ok {
+		log.Printf("[DEBUG] NAT rule %q: skipping checks for unknown zone %q", o.Name, o.DestinationZone)
+		return nil
+	}
+
+	if o.ToInterface != "" && o.ToInterface != "any" && len(dz.Interfaces) != 0 {
+		found := false
+		for _, x := range dz.Interfaces {
+			if x == o.ToInterface {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.addf(o.Name, "destination interface %q is not in destination zone %q", o.ToInterface, o.DestinationZone)
+		}
+	}
+
+	return nil
+}
+
+func (v *natRuleValidator) validatePools(o nat.Entry) error {
+	var pool []string
+
+	switch o.SatType {
+	case nat.DynamicIpAndPort:
+		if o.SatAddressType == nat.TranslatedAddress {
+			pool = o.SatTranslatedAddresses
+		}
+	case nat.DynamicIp:
+		pool = o.SatTranslatedAddresses
+	}
+
+	if len(pool) == 0 {
+		return nil
+	}
+
+	if err := v.loadInterfaceIps(); err != nil {
+		return err
+	}
+
+	for _, name := range pool {
+		values, err := v.resolveAddress(name)
+		if err != nil {
+			return err
+		}
+		for _, val := range values {
+			lo, hi, ok := addressRange(val)
+			if !ok {
+				continue
+			}
+			for iface, ips := range v.ifaceIps {
+				for _, ip := range ips {
+					if x := net.ParseIP(ip); x != nil && ipInRange(x, lo, hi) {
+						v.addf(o.Name, "translated address %q overlaps %s address %s", name, iface, ip)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isAddressGroup returns if the given name is an address group in either the
+// current location or shared.
+func (v *natRuleValidator) isAddressGroup(name string) (bool, error) {
+	if err := v.loadAddressGroups(); err != nil {
+		return false, err
+	}
+
+	_, ok := v.groups[name]
+	return ok, nil
+}
+
+func (v *natRuleValidator) loadAddressGroups() error {
+	if v.groups != nil {
+		return nil
+	}
+
+	v.groups = make(map[string][]string)
+	for _, loc := range []string{v.loc, "shared"} {
+		var err error
+		var list []addrgrp.Entry
+
+		switch con := v.meta.(type) {
+		case *pango.Firewall:
+			list, err = con.Objects.AddressGroup.GetAll(loc)
+		case *pango.Panorama:
+			list, err = con.Objects.AddressGroup.GetAll(loc)
+		}
+
+		if err != nil && !isObjectNotFound(err) {
+			return err
+		}
+		for _, x := range list {
+			if _, ok := v.groups[x.Name]; !ok {
+				v.groups[x.Name] = x.StaticAddresses
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveAddress resolves an address object or static address group into
+// its values.  Literal values and unknown names are returned as is.
+func (v *natRuleValidator) resolveAddress(name string) ([]string, error) {
+	if err := v.loadAddressGroups(); err != nil {
+		return nil, err
+	}
+
+	if v.addrs == nil {
+		v.addrs = make(map[string]addr.Entry)
+		for _, loc := range []string{v.loc, "shared"} {
+			var err error
+			var list []addr.Entry
+
+			switch con := v.meta.(type) {
+			case *pango.Firewall:
+				list, err = con.Objects.Address.GetAll(loc)
+			case *pango.Panorama:
+				list, err = con.Objects.Address.GetAll(loc)
+			}
+
+			if err != nil && !isObjectNotFound(err) {
+				return nil, err
+			}
+			for _, x := range list {
+				if _, ok := v.addrs[x.Name]; !ok {
+					v.addrs[x.Name] = x
+				}
+			}
+		}
+	}
+
+	return v.resolve(name, map[string]bool{}), nil
+}
+
+func (v *natRuleValidator) resolve(name string, seen map[string]bool) []string {
+	if seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	if o, ok := v.addrs[name]; ok {
+		if o.Type == addr.IpNetmask || o.Type == addr.IpRange {
+			return []string{o.Value}
+		}
+		return nil
+	}
+
+	if members, ok := v.groups[name]; ok {
+		var ans []string
+		for _, m := range members {
+			ans = append(ans, v.resolve(m, seen)...)
+		}
+		return ans
+	}
+
+	return []string{name}
+}
+
+// loadInterfaceIps loads the static IP addresses of all layer3 interfaces.
+func (v *natRuleValidator) loadInterfaceIps() error {
+	if v.ifaceIps != nil {
+		return nil
+	}
+
+	fw := v.meta.(*pango.Firewall)
+	v.ifaceIps = make(map[string][]string)
+
+	add := func(name string, ips []string) {
+		for _, ip := range ips {
+			vals, _ := v.resolveAddress(ip)
+			for _, val := range vals {
+				if i := strings.Index(val, "/"); i != -1 {
+					val = val[:i]
+				}
+				v.ifaceIps[name] = append(v.ifaceIps[name], val)
+			}
+		}
+	}
+
+	ethList, err := fw.Network.EthernetInterface.GetAll()
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range ethList {
+		if x.Mode != eth.ModeLayer3 {
+			continue
+		}
+		add(x.Name, x.StaticIps)
+		subs, err := fw.Network.Layer3Subinterface.GetAll(layer3.EthernetInterface, x.Name)
+		if err != nil && !isObjectNotFound(err) {
+			return err
+		}
+		for _, s := range subs {
+			add(s.Name, s.StaticIps)
+		}
+	}
+
+	loList, err := fw.Network.LoopbackInterface.GetAll()
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range loList {
+		add(x.Name, x.StaticIps)
+	}
+
+	vlanList, err := fw.Network.VlanInterface.GetAll()
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range vlanList {
+		add(x.Name, x.StaticIps)
+	}
+
+	tunList, err := fw.Network.TunnelInterface.GetAll()
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range tunList {
+		add(x.Name, x.StaticIps)
+	}
+
+	return nil
+}
+
+// addressRange returns the first and last IP of an IP, CIDR, or IP range.
+func addressRange(v string) (net.IP, net.IP, bool) {
+	if i := strings.Index(v, "-"); i != -1 {
+		lo, hi := net.ParseIP(v[:i]), net.ParseIP(v[i+1:])
+		return lo, hi, lo != nil && hi != nil
+	}
+
+	if strings.Contains(v, "/") {
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, nil, false
+		}
+		hi := make(net.IP, len(n.IP))
+		for i := range n.IP {
+			hi[i] = n.IP[i] | ^n.Mask[i]
+		}
+		return n.IP, hi, true
+	}
+
+	ip := net.ParseIP(v)
+	return ip, ip, ip != nil
+}
+
+func ipInRange(ip, lo, hi net.IP) bool {
+	return bytes.Compare(ip.To16(), lo.To16()) >= 0 && bytes.Compare(ip.To16(), hi.To16()) <= 0
+}
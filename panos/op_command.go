@@ -0,0 +1,366 @@
+package panos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Data source.
+func dataSourceOpCommand() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOpCommandRead,
+
+		Schema: map[string]*schema.Schema{
+			"cmd": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The op command as XML",
+				ConflictsWith: []string{"cli"},
+			},
+			"cli": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "The op command in CLI form; values must be quoted",
+				ConflictsWith: []string{"cmd"},
+			},
+			"vsys": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The vsys to run the command in",
+			},
+			"target": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(Panorama) The NGFW serial number to run the command on",
+			},
+			"xml_cmd": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The XML op command that was run",
+			},
+			"xml": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The raw XML response",
+			},
+			"json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The JSON rendering of the result",
+			},
+		},
+	}
+}
+
+func dataSourceOpCommandRead(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var c *pango.Client
+	var extras interface{}
+	var b []byte
+
+	cmd := d.Get("cmd").(string)
+	cli := d.Get("cli").(string)
+	vsys := d.Get("vsys").(string)
+	target := d.Get("target").(string)
+
+	switch {
+	case cmd != "":
+	case cli != "":
+		if cmd, err = cliToXml(cli); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Either \"cmd\" or \"cli\" must be specified")
+	}
+
+	if err = checkOpCommand(cmd); err != nil {
+		return err
+	}
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		if target != "" {
+			return fmt.Errorf("\"target\" is only valid for Panorama")
+		}
+		c = &con.Client
+	case *pango.Panorama:
+		c = &con.Client
+		if target != "" {
+			extras = url.Values{"target": []string{target}}
+		}
+	}
+
+	c.LogOp("(op) %s", cmd)
+	if b, err = c.Op(cmd, vsys, extras, nil); err != nil {
+		return err
+	}
+
+	result, err := xmlToJson(b)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(buildOpCommandId(target, vsys, cmd))
+	d.Set("xml_cmd", cmd)
+	d.Set("xml", string(b))
+	d.Set("json", result)
+
+	return nil
+}
+
+// Id functions.
+func buildOpCommandId(target, vsys, cmd string) string {
+	return strings.Join([]string{
+		target, vsys, fmt.Sprintf("%x", sha256.Sum256([]byte(cmd))),
+	}, IdSeparator)
+}
+
+// opCommandVerbs are the op commands that do not change the device's state,
+// and thus are safe to run on every refresh.
+var opCommandVerbs = []string{"show", "test"}
+
+// checkOpCommand returns an error if the given XML op command is not a
+// read-only command.
+func checkOpCommand(cmd string) error {
+	dec := xml.NewDecoder(strings.NewReader(cmd))
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return fmt.Errorf("No command given")
+		} else if err != nil {
+			return fmt.Errorf("Invalid op command: %s", err)
+		}
+
+		if t, ok := tok.(xml.StartElement); ok {
+			for _, v := range opCommandVerbs {
+				if t.Name.Local == v {
+					return nil
+				}
+			}
+			return fmt.Errorf("Only read-only commands (%s) are allowed, not %q", strings.Join(opCommandVerbs, ", "), t.Name.Local)
+		}
+	}
+}
+
+// cliToXml translates a CLI-style op command into XML.
+//
+// Each word is nested inside of the previous word, and a quoted string is the
+// value of the word before it, so `show interface "ethernet1/1"` becomes
+// <show><interface>ethernet1/1</interface></show>.  Words after a value are
+// siblings of the word the value was given to.
+//
+// There is no way to tell a keyword from a value without the device's command
+// schema, so unquoted words are always keywords.  Words that cannot be an XML
+// element (such as "ethernet1/1" or "10.1.1.1") are rejected, but a value that
+// is also a valid keyword (such as a zone name) must be quoted, otherwise it
+// becomes an element of its own.
+func cliToXml(cli string) (string, error) {
+	type node struct {
+		name     string
+		text     *string
+		parent   *node
+		children []*node
+	}
+
+	root := &node{}
+	cur := root
+
+	tokens, err := splitCli(cli)
+	if err != nil {
+		return "", err
+	}
+
+	for _, tok := range tokens {
+		if tok.quoted {
+			if cur == root {
+				return "", fmt.Errorf("Value %q must follow a keyword", tok.value)
+			}
+			v := tok.value
+			cur.text = &v
+			cur = cur.parent
+			continue
+		}
+		if !isXmlName(tok.value) {
+			return "", fmt.Errorf("%q is not a keyword, values must be quoted", tok.value)
+		}
+		n := &node{name: tok.value, parent: cur}
+		cur.children = append(cur.children, n)
+		cur = n
+	}
+
+	if len(root.children) == 0 {
+		return "", fmt.Errorf("No command given")
+	}
+
+	var buf bytes.Buffer
+	var write func(*node)
+	write = func(n *node) {
+		buf.WriteString("<" + n.name)
+		if n.text == nil && len(n.children) == 0 {
+			buf.WriteString("/>")
+			return
+		}
+		buf.WriteString(">")
+		if n.text != nil {
+			xml.EscapeText(&buf, []byte(*n.text))
+		}
+		for _, child := range n.children {
+			write(child)
+		}
+		buf.WriteString("</" + n.name + ">")
+	}
+	for _, n := range root.children {
+		write(n)
+	}
+
+	return buf.String(), nil
+}
+
+// isXmlName returns if v can be used as an XML element name.
+func isXmlName(v string) bool {
+	for i, ch := range v {
+		switch {
+		case ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z'):
+		case i > 0 && (ch == '-' || ch == '.' || (ch >= '0' && ch <= '9')):
+		default:
+			return false
+		}
+	}
+
+	return v != ""
+}
+
+type cliToken struct {
+	value  string
+	quoted bool
+}
+
+func splitCli(cli string) ([]cliToken, error) {
+	var ans []cliToken
+	var cur strings.Builder
+	var quote rune
+	inWord := false
+
+	for _, ch := range cli {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				ans = append(ans, cliToken{value: cur.String(), quoted: true})
+				cur.Reset()
+				quote = 0
+			} else {
+				cur.WriteRune(ch)
+			}
+		case ch == '"' || ch == '\'':
+			if inWord {
+				return nil, fmt.Errorf("Unexpected quote after %q", cur.String())
+			}
+			quote = ch
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inWord {
+				ans = append(ans, cliToken{value: cur.String()})
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			inWord = true
+			cur.WriteRune(ch)
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("Unterminated quote in %q", cli)
+	} else if inWord {
+		ans = append(ans, cliToken{value: cur.String()})
+	}
+
+	return ans, nil
+}
+
+// xmlToJson renders the "result" of a PAN-OS response as JSON.
+//
+// Attributes are keys prefixed with "@", elements that repeat are lists, and
+// the text of elements that also have attributes or children is "#text".
+func xmlToJson(b []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+
+	// Find the result.
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return "null", nil
+		} else if err != nil {
+			return "", err
+		}
+
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "result" {
+			v, err := xmlElementToValue(dec, t)
+			if err != nil {
+				return "", err
+			}
+			ans, err := json.Marshal(v)
+			return string(ans), err
+		}
+	}
+}
+
+func xmlElementToValue(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	var text strings.Builder
+	m := make(map[string]interface{})
+
+	for _, attr := range start.Attr {
+		m["@"+attr.Name.Local] = attr.Value
+	}
+	hasAttrs := len(m) != 0
+	hasChildren := false
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			hasChildren = true
+			v, err := xmlElementToValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			key := t.Name.Local
+			switch prev := m[key].(type) {
+			case nil:
+				m[key] = v
+			case []interface{}:
+				m[key] = append(prev, v)
+			default:
+				m[key] = []interface{}{prev, v}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if !hasAttrs && !hasChildren {
+				if s == "" {
+					return nil, nil
+				}
+				return s, nil
+			}
+			if s != "" {
+				m["#text"] = s
+			}
+			return m, nil
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package panos
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosDsOpCommand(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsOpCommandConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.panos_op_command.test", "xml_cmd", "<show><system><info/></system></show>"),
+					resource.TestCheckResourceAttrSet("data.panos_op_command.test", "xml"),
+					resource.TestCheckResourceAttrSet("data.panos_op_command.test", "json"),
+				),
+			},
+		},
+	})
+}
+
+func TestCliToXml(t *testing.T) {
+	testCases := []struct {
+		cli string
+		cmd string
+		err bool
+	}{
+		{"show system info", "<show><system><info/></system></show>", false},
+		{`show interface "ethernet1/1"`, "<show><interface>ethernet1/1</interface></show>", false},
+		{`test nat-policy-match from "trust" to "untrust"`, "<test><nat-policy-match><from>trust</from><to>untrust</to></nat-policy-match></test>", false},
+		{"show interface ethernet1/1", "", true},
+		{"show routing route destination 10.1.1.0/24", "", true},
+		{`"show"`, "", true},
+		{`show interface "ethernet1/1`, "", true},
+		{"", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cli, func(t *testing.T) {
+			cmd, err := cliToXml(tc.cli)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("Expected an error, got %q", cmd)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error: %s", err)
+			}
+			if cmd != tc.cmd {
+				t.Errorf("Expected %q, got %q", tc.cmd, cmd)
+			}
+		})
+	}
+}
+
+func TestCheckOpCommand(t *testing.T) {
+	testCases := []struct {
+		cmd string
+		err bool
+	}{
+		{"<show><system><info/></system></show>", false},
+		{"<test><security-policy-match><from>z1</from></security-policy-match></test>", false},
+		{"<request><restart><system/></restart></request>", true},
+		{"<clear><session><all/></session></clear>", true},
+		{"<debug><software><restart><process>mgmtsrvr</process></restart></software></debug>", true},
+		{"", true},
+		{"  <show><clock/></show>", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cmd, func(t *testing.T) {
+			err := checkOpCommand(tc.cmd)
+			if tc.err && err == nil {
+				t.Errorf("Expected an error")
+			} else if !tc.err && err != nil {
+				t.Errorf("Error: %s", err)
+			}
+		})
+	}
+}
+
+func testAccDsOpCommandConfig() string {
+	return `
+data "panos_op_command" "test" {
+    cli = "show system info"
+}
+`
+}
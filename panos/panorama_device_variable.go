@@ -0,0 +1,516 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/pnrm/template/variable"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Data source.
+func dataSourcePanoramaDeviceVariables() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSourcePanoramaDeviceVariables,
+
+		Schema: map[string]*schema.Schema{
+			"template_stack": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The template stack",
+			},
+			"serial": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The serial number of the device",
+			},
+			"values": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The effective variable values for the device",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"variable": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The effective variables for the device",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func readDataSourcePanoramaDeviceVariables(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var stack panoramaDeviceVariableStackAns
+
+	ts := d.Get("template_stack").(string)
+	serial := d.Get("serial").(string)
+
+	path, err := panoramaDeviceVariableStackXpath(meta, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &stack); err != nil {
+		return err
+	}
+	if stack.Entry == nil {
+		return fmt.Errorf("Template stack %q does not exist", ts)
+	}
+
+	var dev *panoramaDeviceVariableDevice
+	for i := range stack.Entry.Devices {
+		if stack.Entry.Devices[i].Name == serial {
+			dev = &stack.Entry.Devices[i]
+			break
+		}
+	}
+	if dev == nil {
+		return fmt.Errorf("Device %q is not in template stack %q", serial, ts)
+	}
+
+	// Lowest priority first: templates from the bottom of the stack up, then
+	// the template stack itself, then the device overrides.
+	names := make([]string, 0)
+	vars := make(map[string]panoramaDeviceVariableEntry)
+	sources := make(map[string]string)
+	add := func(list []panoramaDeviceVariableEntry, source string) {
+		for _, x := range list {
+			if _, ok := vars[x.Name]; !ok {
+				names = append(names, x.Name)
+			}
+			vars[x.Name] = x
+			sources[x.Name] = source
+		}
+	}
+
+	for i := len(stack.Entry.Templates) - 1; i >= 0; i-- {
+		var tmpl panoramaDeviceVariableTemplateAns
+
+		name := stack.Entry.Templates[i]
+		if _, err = c.Get(panoramaDeviceVariableTemplateXpath(name), nil, &tmpl); err != nil {
+			if isObjectNotFound(err) {
+				continue
+			}
+			return err
+		}
+		add(tmpl.Entries, "template:"+name)
+	}
+	add(stack.Entry.Variables, "template_stack")
+	add(dev.Variables, "device")
+
+	values := make(map[string]interface{})
+	list := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		x := vars[name].normalize()
+		values[name] = x.Value
+		list = append(list, map[string]interface{}{
+			"name":   name,
+			"type":   x.Type,
+			"value":  x.Value,
+			"source": sources[name],
+		})
+	}
+
+	d.SetId(buildPanoramaDeviceVariablesId(ts, serial))
+	d.Set("template_stack", ts)
+	d.Set("serial", serial)
+	if err = d.Set("values", values); err != nil {
+		log.Printf("[WARN] Error setting 'values' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("variable", list); err != nil {
+		log.Printf("[WARN] Error setting 'variable' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// Resource.
+func resourcePanoramaDeviceVariable() *schema.Resource {
+	return &schema.Resource{
+		Create: createPanoramaDeviceVariable,
+		Read:   readPanoramaDeviceVariable,
+		Update: updatePanoramaDeviceVariable,
+		Delete: deletePanoramaDeviceVariable,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template_stack": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The template stack",
+			},
+			"serial": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The serial number of the device",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The variable name",
+				ValidateFunc: validateStringHasPrefix("$"),
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      variable.TypeIpNetmask,
+				Description:  "The variable type",
+				ValidateFunc: validateStringIn(variable.TypeIpNetmask, variable.TypeIpRange, variable.TypeFqdn, variable.TypeGroupId, variable.TypeInterface, variable.TypeDevicePriority),
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The value for this device",
+			},
+		},
+	}
+}
+
+func createPanoramaDeviceVariable(d *schema.ResourceData, meta interface{}) error {
+	var ans panoramaDeviceVariableAns
+
+	ts := d.Get("template_stack").(string)
+	serial := d.Get("serial").(string)
+	o := loadPanoramaDeviceVariable(d)
+
+	path, err := panoramaDeviceVariableXpath(meta, ts, serial)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+
+	// Don't implicitly add the device to the template stack.
+	var dev panoramaDeviceVariableDeviceAns
+	if _, err = c.Get(path[:len(path)-1], nil, &dev); err != nil && !isObjectNotFound(err) {
+		return err
+	} else if err != nil || dev.Entry == nil {
+		return fmt.Errorf("Device %q is not in template stack %q", serial, ts)
+	}
+
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{o.Name})), nil, &ans); err == nil && ans.Entry != nil {
+		return fmt.Errorf("Variable %q already exists for device %q", o.Name, serial)
+	} else if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildPanoramaDeviceVariableId(ts, serial, o.Name))
+	return readPanoramaDeviceVariable(d, meta)
+}
+
+func readPanoramaDeviceVariable(d *schema.ResourceData, meta interface{}) error {
+	var ans panoramaDeviceVariableAns
+
+	ts, serial, name := parsePanoramaDeviceVariableId(d.Id())
+
+	path, err := panoramaDeviceVariableXpath(meta, ts, serial)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	o := ans.Entry.normalize()
+	d.Set("template_stack", ts)
+	d.Set("serial", serial)
+	d.Set("name", name)
+	d.Set("type", o.Type)
+	d.Set("value", o.Value)
+
+	return nil
+}
+
+func updatePanoramaDeviceVariable(d *schema.ResourceData, meta interface{}) error {
+	ts, serial, name := parsePanoramaDeviceVariableId(d.Id())
+	o := loadPanoramaDeviceVariable(d)
+
+	path, err := panoramaDeviceVariableXpath(meta, ts, serial)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readPanoramaDeviceVariable(d, meta)
+}
+
+func deletePanoramaDeviceVariable(d *schema.ResourceData, meta interface{}) error {
+	ts, serial, name := parsePanoramaDeviceVariableId(d.Id())
+
+	path, err := panoramaDeviceVariableXpath(meta, ts, serial)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadPanoramaDeviceVariable(d *schema.ResourceData) panoramaDeviceVariableEntry {
+	o := panoramaDeviceVariableEntry{
+		Name: d.Get("name").(string),
+	}
+
+	val := d.Get("value").(string)
+	switch d.Get("type").(string) {
+	case variable.TypeIpNetmask:
+		o.IpNetmask = val
+	case variable.TypeIpRange:
+		o.IpRange = val
+	case variable.TypeFqdn:
+		o.Fqdn = val
+	case variable.TypeGroupId:
+		o.GroupId = val
+	case variable.TypeInterface:
+		o.Interface = val
+	case variable.TypeDevicePriority:
+		o.DevicePriority = val
+	}
+
+	return o
+}
+
+// Id functions.
+func buildPanoramaDeviceVariableId(a, b, c string) string {
+	return strings.Join([]string{a, b, c}, IdSeparator)
+}
+
+func parsePanoramaDeviceVariableId(v string) (string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2]
+}
+
+func buildPanoramaDeviceVariablesId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func panoramaDeviceVariableStackXpath(meta interface{}, ts string) ([]string, error) {
+	if _, err := panorama(meta, ""); err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"config",
+		"devices",
+		util.AsEntryXpath([]string{"localhost.localdomain"}),
+		"template-stack",
+		util.AsEntryXpath([]string{ts}),
+	}, nil
+}
+
+func panoramaDeviceVariableXpath(meta interface{}, ts, serial string) ([]string, error) {
+	ans, err := panoramaDeviceVariableStackXpath(meta, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "devices", util.AsEntryXpath([]string{serial}), "variable"), nil
+}
+
+// getPanoramaDeviceVariableOverrides returns the raw device variable config
+// in the template stack, keyed by serial number.
+//
+// pango models template stack devices as names only, so editing the template
+// stack or one of its devices removes these overrides.  Save them before the
+// edit and put them back with restorePanoramaDeviceVariableOverrides.
+func getPanoramaDeviceVariableOverrides(meta interface{}, ts string) (map[string]string, error) {
+	var ans panoramaDeviceVariableRawStackAns
+
+	path, err := panoramaDeviceVariableStackXpath(meta, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, "devices"), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	overrides := make(map[string]string)
+	for _, x := range ans.Devices {
+		if x.Variable != nil && strings.TrimSpace(x.Variable.Text) != "" {
+			overrides[x.Name] = x.Variable.Text
+		}
+	}
+
+	return overrides, nil
+}
+
+// restorePanoramaDeviceVariableOverrides puts back the device variable config
+// saved by getPanoramaDeviceVariableOverrides for the given devices.
+//
+// Only pass devices that are still in the template stack, as setting the
+// variables would otherwise add the device back to the stack.
+func restorePanoramaDeviceVariableOverrides(meta interface{}, ts string, overrides map[string]string, devices []string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	c := rawClient(meta)
+	for _, serial := range devices {
+		text, ok := overrides[serial]
+		if !ok {
+			continue
+		}
+
+		path, err := panoramaDeviceVariableXpath(meta, ts, serial)
+		if err != nil {
+			return err
+		}
+
+		if _, err = c.Set(path[:len(path)-1], panoramaDeviceVariableRaw{Text: text}, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func panoramaDeviceVariableTemplateXpath(tmpl string) []string {
+	return []string{
+		"config",
+		"devices",
+		util.AsEntryXpath([]string{"localhost.localdomain"}),
+		"template",
+		util.AsEntryXpath([]string{tmpl}),
+		"variable",
+	}
+}
+
+// Config structs.
+type panoramaDeviceVariableEntry struct {
+	XMLName        xml.Name `xml:"entry"`
+	Name           string   `xml:"name,attr"`
+	IpNetmask      string   `xml:"type>ip-netmask,omitempty"`
+	IpRange        string   `xml:"type>ip-range,omitempty"`
+	Fqdn           string   `xml:"type>fqdn,omitempty"`
+	GroupId        string   `xml:"type>group-id,omitempty"`
+	Interface      string   `xml:"type>interface,omitempty"`
+	DevicePriority string   `xml:"type>device-priority,omitempty"`
+}
+
+// normalize returns the variable as a pango template variable.
+func (e panoramaDeviceVariableEntry) normalize() variable.Entry {
+	ans := variable.Entry{Name: e.Name}
+
+	switch {
+	case e.IpNetmask != "":
+		ans.Type, ans.Value = variable.TypeIpNetmask, e.IpNetmask
+	case e.IpRange != "":
+		ans.Type, ans.Value = variable.TypeIpRange, e.IpRange
+	case e.Fqdn != "":
+		ans.Type, ans.Value = variable.TypeFqdn, e.Fqdn
+	case e.GroupId != "":
+		ans.Type, ans.Value = variable.TypeGroupId, e.GroupId
+	case e.Interface != "":
+		ans.Type, ans.Value = variable.TypeInterface, e.Interface
+	case e.DevicePriority != "":
+		ans.Type, ans.Value = variable.TypeDevicePriority, e.DevicePriority
+	}
+
+	return ans
+}
+
+type panoramaDeviceVariableAns struct {
+	Entry *panoramaDeviceVariableEntry `xml:"result>entry"`
+}
+
+type panoramaDeviceVariableStackAns struct {
+	Entry *panoramaDeviceVariableStack `xml:"result>entry"`
+}
+
+type panoramaDeviceVariableStack struct {
+	Templates []string                       `xml:"templates>member"`
+	Variables []panoramaDeviceVariableEntry  `xml:"variable>entry"`
+	Devices   []panoramaDeviceVariableDevice `xml:"devices>entry"`
+}
+
+type panoramaDeviceVariableDevice struct {
+	Name      string                        `xml:"name,attr"`
+	Variables []panoramaDeviceVariableEntry `xml:"variable>entry"`
+}
+
+type panoramaDeviceVariableDeviceAns struct {
+	Entry *panoramaDeviceVariableDevice `xml:"result>entry"`
+}
+
+type panoramaDeviceVariableRawStackAns struct {
+	Devices []panoramaDeviceVariableRawDevice `xml:"result>devices>entry"`
+}
+
+type panoramaDeviceVariableRawDevice struct {
+	Name     string                     `xml:"name,attr"`
+	Variable *panoramaDeviceVariableRaw `xml:"variable"`
+}
+
+type panoramaDeviceVariableRaw struct {
+	XMLName xml.Name `xml:"variable"`
+	Text    string   `xml:",innerxml"`
+}
+
+type panoramaDeviceVariableTemplateAns struct {
+	Entries []panoramaDeviceVariableEntry `xml:"result>variable>entry"`
+}
@@ -0,0 +1,118 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosPanoramaDeviceVariable_basic(t *testing.T) {
+	/*
+	   In order to run this test you'll need:
+
+	   * panorama as the device (PANOS_HOSTNAME, PANOS_USERNAME, PANOS_PASSWORD)
+	   * a firewall that's already added as a managed device
+	     (PANOS_MANAGED_SERIAL_NUMBER)
+	*/
+
+	serial := os.Getenv("PANOS_MANAGED_SERIAL_NUMBER")
+
+	if !testAccIsPanorama {
+		t.Skip(SkipPanoramaAccTest)
+	} else if serial == "" {
+		t.Skip("PANOS_MANAGED_SERIAL_NUMBER must be set")
+	}
+
+	tmpl := fmt.Sprintf("tf%s", acctest.RandString(6))
+	name := fmt.Sprintf("$tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPanoramaDeviceVariableConfig(tmpl, serial, name, "10.2.1.1/24", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_device_variable.test", "value", "10.2.1.1/24"),
+				),
+			},
+			{
+				Config: testAccPanoramaDeviceVariableConfig(tmpl, serial, name, "10.3.1.1/24", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_device_variable.test", "value", "10.3.1.1/24"),
+					resource.TestCheckResourceAttr("data.panos_panorama_device_variables.test", fmt.Sprintf("values.%s", name), "10.3.1.1/24"),
+					resource.TestCheckResourceAttr("data.panos_panorama_device_variables.test", "variable.0.source", "device"),
+				),
+			},
+			{
+				// Editing the template stack must keep the device overrides.
+				Config: testAccPanoramaDeviceVariableConfig(tmpl, serial, name, "10.3.1.1/24", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_template_stack.x", "description", "second"),
+					resource.TestCheckResourceAttr("panos_panorama_device_variable.test", "value", "10.3.1.1/24"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanoramaDeviceVariableConfig(tmpl, serial, name, value, desc string) string {
+	return fmt.Sprintf(`
+data "panos_panorama_device_variables" "test" {
+    template_stack = panos_panorama_device_variable.test.template_stack
+    serial = panos_panorama_device_variable.test.serial
+}
+
+resource "panos_panorama_template" "x" {
+    name = %q
+}
+
+resource "panos_panorama_template_stack" "x" {
+    name = "%s-stack"
+    description = %q
+    templates = [panos_panorama_template.x.name]
+    devices = [%q]
+}
+
+resource "panos_panorama_template_variable" "x" {
+    template_stack = panos_panorama_template_stack.x.name
+    name = %q
+    value = "10.1.1.1/24"
+}
+
+resource "panos_panorama_device_variable" "test" {
+    template_stack = panos_panorama_template_stack.x.name
+    serial = %q
+    name = panos_panorama_template_variable.x.name
+    value = %q
+}
+`, tmpl, tmpl, desc, serial, name, serial, value)
+}
+
+func TestPanoramaDeviceVariableRawStackAns(t *testing.T) {
+	var ans panoramaDeviceVariableRawStackAns
+
+	data := `<response status="success"><result><devices><entry name="0001"><variable><entry name="$v1"><type><ip-netmask>10.1.1.1/24</ip-netmask></type></entry></variable></entry><entry name="0002"/></devices></result></response>`
+	if err := xml.Unmarshal([]byte(data), &ans); err != nil {
+		t.Fatalf("Error in unmarshal: %s", err)
+	}
+	if len(ans.Devices) != 2 {
+		t.Fatalf("expected 2 devices, got %d", len(ans.Devices))
+	}
+	if ans.Devices[1].Variable != nil {
+		t.Errorf("expected no variables for %q", ans.Devices[1].Name)
+	}
+
+	b, err := xml.Marshal(*ans.Devices[0].Variable)
+	if err != nil {
+		t.Fatalf("Error in marshal: %s", err)
+	}
+	expected := `<variable><entry name="$v1"><type><ip-netmask>10.1.1.1/24</ip-netmask></type></entry></variable>`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+}
@@ -0,0 +1,464 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Log collectors are configured as raw XML, as pango's pnrm/logcollector
+// only has the primary Panorama, DNS, and NTP servers and the IPv4 management
+// settings.  It has no secondary servers, IPv6, or eth1 - eth5 interfaces,
+// and the disk pairs are a separate namespace (pnrm/logcollector/diskpair)
+// that can only be configured once the log collector exists.
+
+// Resource.
+func resourcePanoramaLogCollector() *schema.Resource {
+	return &schema.Resource{
+		Create: createPanoramaLogCollector,
+		Read:   readPanoramaLogCollector,
+		Update: updatePanoramaLogCollector,
+		Delete: deletePanoramaLogCollector,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"serial_number": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The serial number of the log collector",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The hostname",
+			},
+			"ip_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Management interface IP address",
+			},
+			"netmask": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Management interface netmask",
+			},
+			"default_gateway": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Management interface default gateway",
+			},
+			"ipv6_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Management interface IPv6 address",
+			},
+			"ipv6_default_gateway": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Management interface IPv6 default gateway",
+			},
+			"panorama_server": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The primary Panorama server",
+			},
+			"panorama_server_2": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The secondary Panorama server",
+			},
+			"dns_primary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Primary DNS server",
+			},
+			"dns_secondary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Secondary DNS server",
+			},
+			"ntp_primary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Primary NTP server",
+			},
+			"ntp_secondary": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Secondary NTP server",
+			},
+			"timezone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The timezone",
+			},
+			"disk_pairs": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Enabled disk pairs",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateStringIn("A", "B", "C", "D"),
+				},
+			},
+			"interface": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Log collector interface (eth1 - eth5) configuration",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The interface name",
+							ValidateFunc: validateStringIn("eth1", "eth2", "eth3", "eth4", "eth5"),
+						},
+						"ip_address": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "IP address",
+						},
+						"netmask": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Netmask",
+						},
+						"default_gateway": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Default gateway",
+						},
+						"ipv6_address": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "IPv6 address",
+						},
+						"ipv6_default_gateway": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "IPv6 default gateway",
+						},
+						"mtu": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "The MTU",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createPanoramaLogCollector(d *schema.ResourceData, meta interface{}) error {
+	o := loadPanoramaLogCollector(d)
+
+	path, err := panoramaLogCollectorXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Log collector %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(o.Name)
+	return readPanoramaLogCollector(d, meta)
+}
+
+func readPanoramaLogCollector(d *schema.ResourceData, meta interface{}) error {
+	var ans panoramaLogCollectorAns
+
+	path, err := panoramaLogCollectorXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{d.Id()})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	savePanoramaLogCollector(d, *ans.Entry)
+
+	return nil
+}
+
+func updatePanoramaLogCollector(d *schema.ResourceData, meta interface{}) error {
+	o := loadPanoramaLogCollector(d)
+
+	path, err := panoramaLogCollectorXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{d.Id()})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readPanoramaLogCollector(d, meta)
+}
+
+func deletePanoramaLogCollector(d *schema.ResourceData, meta interface{}) error {
+	path, err := panoramaLogCollectorXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{d.Id()})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadPanoramaLogCollector(d *schema.ResourceData) panoramaLogCollectorEntry {
+	sys := panoramaLogCollectorSystem{
+		Hostname:           d.Get("hostname").(string),
+		IpAddress:          d.Get("ip_address").(string),
+		Netmask:            d.Get("netmask").(string),
+		DefaultGateway:     d.Get("default_gateway").(string),
+		Ipv6Address:        d.Get("ipv6_address").(string),
+		Ipv6DefaultGateway: d.Get("ipv6_default_gateway").(string),
+		PanoramaServer:     d.Get("panorama_server").(string),
+		PanoramaServer2:    d.Get("panorama_server_2").(string),
+		Timezone:           d.Get("timezone").(string),
+	}
+
+	if p, s := d.Get("dns_primary").(string), d.Get("dns_secondary").(string); p != "" || s != "" {
+		sys.Dns = &panoramaLogCollectorDns{Primary: p, Secondary: s}
+	}
+
+	if p, s := d.Get("ntp_primary").(string), d.Get("ntp_secondary").(string); p != "" || s != "" {
+		sys.Ntp = &panoramaLogCollectorNtp{}
+		if p != "" {
+			sys.Ntp.Primary = &panoramaLogCollectorNtpServer{Address: p}
+		}
+		if s != "" {
+			sys.Ntp.Secondary = &panoramaLogCollectorNtpServer{Address: s}
+		}
+	}
+
+	for _, x := range d.Get("interface").([]interface{}) {
+		elm := x.(map[string]interface{})
+		iface := &panoramaLogCollectorInterface{
+			IpAddress:          elm["ip_address"].(string),
+			Netmask:            elm["netmask"].(string),
+			DefaultGateway:     elm["default_gateway"].(string),
+			Ipv6Address:        elm["ipv6_address"].(string),
+			Ipv6DefaultGateway: elm["ipv6_default_gateway"].(string),
+			Mtu:                elm["mtu"].(int),
+		}
+		switch elm["name"].(string) {
+		case "eth1":
+			sys.Eth1 = iface
+		case "eth2":
+			sys.Eth2 = iface
+		case "eth3":
+			sys.Eth3 = iface
+		case "eth4":
+			sys.Eth4 = iface
+		case "eth5":
+			sys.Eth5 = iface
+		}
+	}
+
+	o := panoramaLogCollectorEntry{
+		Name:   d.Get("serial_number").(string),
+		System: &sys,
+	}
+
+	if pairs := setAsList(d.Get("disk_pairs").(*schema.Set)); len(pairs) != 0 {
+		o.DiskPairs = &panoramaLogCollectorDiskPairs{}
+		for _, x := range pairs {
+			o.DiskPairs.Entries = append(o.DiskPairs.Entries, panoramaLogCollectorDiskPair{Name: x})
+		}
+	}
+
+	return o
+}
+
+func savePanoramaLogCollector(d *schema.ResourceData, o panoramaLogCollectorEntry) {
+	var sys panoramaLogCollectorSystem
+	if o.System != nil {
+		sys = *o.System
+	}
+
+	var dnsPrimary, dnsSecondary string
+	if sys.Dns != nil {
+		dnsPrimary = sys.Dns.Primary
+		dnsSecondary = sys.Dns.Secondary
+	}
+
+	var ntpPrimary, ntpSecondary string
+	if sys.Ntp != nil {
+		if sys.Ntp.Primary != nil {
+			ntpPrimary = sys.Ntp.Primary.Address
+		}
+		if sys.Ntp.Secondary != nil {
+			ntpSecondary = sys.Ntp.Secondary.Address
+		}
+	}
+
+	var pairs []string
+	if o.DiskPairs != nil {
+		for _, x := range o.DiskPairs.Entries {
+			pairs = append(pairs, x.Name)
+		}
+	}
+
+	var ifaces []interface{}
+	for _, x := range []struct {
+		name  string
+		iface *panoramaLogCollectorInterface
+	}{
+		{"eth1", sys.Eth1},
+		{"eth2", sys.Eth2},
+		{"eth3", sys.Eth3},
+		{"eth4", sys.Eth4},
+		{"eth5", sys.Eth5},
+	} {
+		if x.iface == nil {
+			continue
+		}
+		ifaces = append(ifaces, map[string]interface{}{
+			"name":                 x.name,
+			"ip_address":           x.iface.IpAddress,
+			"netmask":              x.iface.Netmask,
+			"default_gateway":      x.iface.DefaultGateway,
+			"ipv6_address":         x.iface.Ipv6Address,
+			"ipv6_default_gateway": x.iface.Ipv6DefaultGateway,
+			"mtu":                  x.iface.Mtu,
+		})
+	}
+
+	d.Set("serial_number", o.Name)
+	d.Set("hostname", sys.Hostname)
+	d.Set("ip_address", sys.IpAddress)
+	d.Set("netmask", sys.Netmask)
+	d.Set("default_gateway", sys.DefaultGateway)
+	d.Set("ipv6_address", sys.Ipv6Address)
+	d.Set("ipv6_default_gateway", sys.Ipv6DefaultGateway)
+	d.Set("panorama_server", sys.PanoramaServer)
+	d.Set("panorama_server_2", sys.PanoramaServer2)
+	d.Set("dns_primary", dnsPrimary)
+	d.Set("dns_secondary", dnsSecondary)
+	d.Set("ntp_primary", ntpPrimary)
+	d.Set("ntp_secondary", ntpSecondary)
+	d.Set("timezone", sys.Timezone)
+	if err := d.Set("disk_pairs", listAsSet(pairs)); err != nil {
+		log.Printf("[WARN] Error setting 'disk_pairs' for %q: %s", d.Id(), err)
+	}
+	if err := d.Set("interface", ifaces); err != nil {
+		log.Printf("[WARN] Error setting 'interface' for %q: %s", d.Id(), err)
+	}
+}
+
+// panoramaLogCollectorXpath returns the xpath of the log collectors.
+func panoramaLogCollectorXpath(meta interface{}) ([]string, error) {
+	if _, err := panorama(meta, ""); err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"config",
+		"devices",
+		util.AsEntryXpath([]string{"localhost.localdomain"}),
+		"log-collector",
+	}, nil
+}
+
+// Config structs.
+type panoramaLogCollectorEntry struct {
+	XMLName   xml.Name                       `xml:"entry"`
+	Name      string                         `xml:"name,attr"`
+	System    *panoramaLogCollectorSystem    `xml:"deviceconfig>system"`
+	DiskPairs *panoramaLogCollectorDiskPairs `xml:"disk-settings>disk-pair"`
+}
+
+type panoramaLogCollectorSystem struct {
+	Hostname           string                         `xml:"hostname,omitempty"`
+	IpAddress          string                         `xml:"ip-address,omitempty"`
+	Netmask            string                         `xml:"netmask,omitempty"`
+	DefaultGateway     string                         `xml:"default-gateway,omitempty"`
+	Ipv6Address        string                         `xml:"ipv6-address,omitempty"`
+	Ipv6DefaultGateway string                         `xml:"ipv6-default-gateway,omitempty"`
+	PanoramaServer     string                         `xml:"panorama-server,omitempty"`
+	PanoramaServer2    string                         `xml:"panorama-server-2,omitempty"`
+	Dns                *panoramaLogCollectorDns       `xml:"dns-setting>servers"`
+	Ntp                *panoramaLogCollectorNtp       `xml:"ntp-servers"`
+	Timezone           string                         `xml:"timezone,omitempty"`
+	Eth1               *panoramaLogCollectorInterface `xml:"eth1"`
+	Eth2               *panoramaLogCollectorInterface `xml:"eth2"`
+	Eth3               *panoramaLogCollectorInterface `xml:"eth3"`
+	Eth4               *panoramaLogCollectorInterface `xml:"eth4"`
+	Eth5               *panoramaLogCollectorInterface `xml:"eth5"`
+}
+
+type panoramaLogCollectorDns struct {
+	Primary   string `xml:"primary,omitempty"`
+	Secondary string `xml:"secondary,omitempty"`
+}
+
+type panoramaLogCollectorNtp struct {
+	Primary   *panoramaLogCollectorNtpServer `xml:"primary-ntp-server"`
+	Secondary *panoramaLogCollectorNtpServer `xml:"secondary-ntp-server"`
+}
+
+type panoramaLogCollectorNtpServer struct {
+	Address string `xml:"ntp-server-address"`
+}
+
+type panoramaLogCollectorInterface struct {
+	IpAddress          string `xml:"ip-address,omitempty"`
+	Netmask            string `xml:"netmask,omitempty"`
+	DefaultGateway     string `xml:"default-gateway,omitempty"`
+	Ipv6Address        string `xml:"ipv6-address,omitempty"`
+	Ipv6DefaultGateway string `xml:"ipv6-default-gateway,omitempty"`
+	Mtu                int    `xml:"mtu,omitempty"`
+}
+
+type panoramaLogCollectorDiskPairs struct {
+	Entries []panoramaLogCollectorDiskPair `xml:"entry"`
+}
+
+type panoramaLogCollectorDiskPair struct {
+	Name string `xml:"name,attr"`
+}
+
+type panoramaLogCollectorAns struct {
+	Entry *panoramaLogCollectorEntry `xml:"result>entry"`
+}
@@ -0,0 +1,587 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourcePanoramaLogCollectorGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: createPanoramaLogCollectorGroup,
+		Read:   readPanoramaLogCollectorGroup,
+		Update: updatePanoramaLogCollectorGroup,
+		Delete: deletePanoramaLogCollectorGroup,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The collector group name",
+			},
+			"min_retention_period": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Minimum log retention period, in days",
+			},
+			"enable_log_redundancy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Keep two copies of each log across the collectors",
+			},
+			"forward_to_all": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Forward logs to all collectors in the preference list",
+			},
+			"collectors": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Serial numbers of the log collectors in this group",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"device_log_forwarding": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Per device log forwarding preferences",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"serial_number": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The serial number of the firewall",
+						},
+						"collectors": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Description: "Ordered list of log collectors the firewall forwards logs to",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"log_storage_quota": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Log storage quotas",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"log_type": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The log type, such as detailed-logs, summary, or extended-threat-pcap",
+						},
+						"quota": {
+							Type:        schema.TypeFloat,
+							Optional:    true,
+							Description: "Storage quota, as a percentage",
+						},
+						"max_days": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Maximum days to keep the logs",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createPanoramaLogCollectorGroup(d *schema.ResourceData, meta interface{}) error {
+	o := loadPanoramaLogCollectorGroup(d)
+
+	path, err := panoramaLogCollectorGroupXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Log collector group %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(o.Name)
+	return readPanoramaLogCollectorGroup(d, meta)
+}
+
+func readPanoramaLogCollectorGroup(d *schema.ResourceData, meta interface{}) error {
+	var ans panoramaLogCollectorGroupAns
+
+	path, err := panoramaLogCollectorGroupXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{d.Id()})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	savePanoramaLogCollectorGroup(d, *ans.Entry)
+
+	return nil
+}
+
+func updatePanoramaLogCollectorGroup(d *schema.ResourceData, meta interface{}) error {
+	o := loadPanoramaLogCollectorGroup(d)
+
+	path, err := panoramaLogCollectorGroupXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	// Only edit the settings managed here so that the collector log
+	// forwarding settings are left alone.
+	c := rawClient(meta)
+	path = append(path, util.AsEntryXpath([]string{d.Id()}))
+	if _, err = c.Edit(append(path, "general-setting"), o.General, nil, nil); err != nil {
+		return err
+	}
+	if _, err = c.Edit(append(path, "logfwd-setting"), o.LogForwarding, nil, nil); err != nil {
+		return err
+	}
+
+	return readPanoramaLogCollectorGroup(d, meta)
+}
+
+func deletePanoramaLogCollectorGroup(d *schema.ResourceData, meta interface{}) error {
+	path, err := panoramaLogCollectorGroupXpath(meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{d.Id()})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadPanoramaLogCollectorGroup(d *schema.ResourceData) panoramaLogCollectorGroupEntry {
+	o := panoramaLogCollectorGroupEntry{
+		Name: d.Get("name").(string),
+		General: &panoramaLogCollectorGroupGeneral{
+			MinRetentionPeriod:  d.Get("min_retention_period").(int),
+			EnableLogRedundancy: util.YesNo(d.Get("enable_log_redundancy").(bool)),
+			ForwardToAll:        util.YesNo(d.Get("forward_to_all").(bool)),
+		},
+		LogForwarding: &panoramaLogCollectorGroupLogForwarding{
+			Collectors: util.StrToMem(asStringList(d.Get("collectors").([]interface{}))),
+		},
+	}
+
+	if list := d.Get("device_log_forwarding").([]interface{}); len(list) != 0 {
+		o.LogForwarding.Devices = &panoramaLogCollectorGroupDevices{}
+		for _, x := range list {
+			elm := x.(map[string]interface{})
+			o.LogForwarding.Devices.Entries = append(o.LogForwarding.Devices.Entries, panoramaLogCollectorGroupDevice{
+				Name:       elm["serial_number"].(string),
+				Collectors: util.StrToMem(asStringList(elm["collectors"].([]interface{}))),
+			})
+		}
+	}
+
+	if list := d.Get("log_storage_quota").([]interface{}); len(list) != 0 {
+		o.General.Quotas = &panoramaLogCollectorGroupQuotas{}
+		for _, x := range list {
+			elm := x.(map[string]interface{})
+			o.General.Quotas.Entries = append(o.General.Quotas.Entries, panoramaLogCollectorGroupQuota{
+				Name:    elm["log_type"].(string),
+				Quota:   elm["quota"].(float64),
+				MaxDays: elm["max_days"].(int),
+			})
+		}
+	}
+
+	return o
+}
+
+func savePanoramaLogCollectorGroup(d *schema.ResourceData, o panoramaLogCollectorGroupEntry) {
+	var err error
+
+	d.Set("name", o.Name)
+
+	if o.General == nil {
+		o.General = &panoramaLogCollectorGroupGeneral{}
+	}
+	d.Set("min_retention_period", o.General.MinRetentionPeriod)
+	d.Set("enable_log_redundancy", util.AsBool(o.General.EnableLogRedundancy))
+	d.Set("forward_to_all", util.AsBool(o.General.ForwardToAll))
+
+	var quotas []interface{}
+	if o.General.Quotas != nil {
+		for _, x := range o.General.Quotas.Entries {
+			quotas = append(quotas, map[string]interface{}{
+				"log_type": x.Name,
+				"quota":    x.Quota,
+				"max_days": x.MaxDays,
+			})
+		}
+	}
+	if err = d.Set("log_storage_quota", quotas); err != nil {
+		log.Printf("[WARN] Error setting 'log_storage_quota' for %q: %s", d.Id(), err)
+	}
+
+	if o.LogForwarding == nil {
+		o.LogForwarding = &panoramaLogCollectorGroupLogForwarding{}
+	}
+	if err = d.Set("collectors", util.MemToStr(o.LogForwarding.Collectors)); err != nil {
+		log.Printf("[WARN] Error setting 'collectors' for %q: %s", d.Id(), err)
+	}
+
+	var devices []interface{}
+	if o.LogForwarding.Devices != nil {
+		for _, x := range o.LogForwarding.Devices.Entries {
+			devices = append(devices, map[string]interface{}{
+				"serial_number": x.Name,
+				"collectors":    util.MemToStr(x.Collectors),
+			})
+		}
+	}
+	if err = d.Set("device_log_forwarding", devices); err != nil {
+		log.Printf("[WARN] Error setting 'device_log_forwarding' for %q: %s", d.Id(), err)
+	}
+}
+
+// panoramaLogCollectorGroupXpath returns the xpath of the collector groups.
+func panoramaLogCollectorGroupXpath(meta interface{}) ([]string, error) {
+	if _, err := panorama(meta, ""); err != nil {
+		return nil, err
+	}
+
+	return []string{
+		"config",
+		"devices",
+		util.AsEntryXpath([]string{"localhost.localdomain"}),
+		"log-collector-group",
+	}, nil
+}
+
+// Config structs.
+type panoramaLogCollectorGroupEntry struct {
+	XMLName       xml.Name                                `xml:"entry"`
+	Name          string                                  `xml:"name,attr"`
+	General       *panoramaLogCollectorGroupGeneral       `xml:"general-setting"`
+	LogForwarding *panoramaLogCollectorGroupLogForwarding `xml:"logfwd-setting"`
+}
+
+type panoramaLogCollectorGroupGeneral struct {
+	XMLName             xml.Name                         `xml:"general-setting"`
+	MinRetentionPeriod  int                              `xml:"management>min-retention-period,omitempty"`
+	EnableLogRedundancy string                           `xml:"management>enable-log-redundancy"`
+	ForwardToAll        string                           `xml:"management>forward-to-all"`
+	Quotas              *panoramaLogCollectorGroupQuotas `xml:"management>quota-settings>quotas"`
+}
+
+type panoramaLogCollectorGroupQuotas struct {
+	Entries []panoramaLogCollectorGroupQuota `xml:"entry"`
+}
+
+type panoramaLogCollectorGroupQuota struct {
+	Name    string  `xml:"name,attr"`
+	Quota   float64 `xml:"quota,omitempty"`
+	MaxDays int     `xml:"max-days,omitempty"`
+}
+
+type panoramaLogCollectorGroupLogForwarding struct {
+	XMLName    xml.Name                          `xml:"logfwd-setting"`
+	Collectors *util.MemberType                  `xml:"collectors"`
+	Devices    *panoramaLogCollectorGroupDevices `xml:"devices"`
+}
+
+type panoramaLogCollectorGroupDevices struct {
+	Entries []panoramaLogCollectorGroupDevice `xml:"entry"`
+}
+
+type panoramaLogCollectorGroupDevice struct {
+	Name       string           `xml:"name,attr"`
+	Collectors *util.MemberType `xml:"collectors"`
+}
+
+type panoramaLogCollectorGroupAns struct {
+	Entry *panoramaLogCollectorGroupEntry `xml:"result>entry"`
+}
+
+// Log forwarding resource.
+func resourcePanoramaLogCollectorGroupLogForwarding() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdatePanoramaLogCollectorGroupLogForwarding,
+		Read:   readPanoramaLogCollectorGroupLogForwarding,
+		Update: createUpdatePanoramaLogCollectorGroupLogForwarding,
+		Delete: deletePanoramaLogCollectorGroupLogForwarding,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"collector_group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The collector group",
+			},
+			"log_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The log type",
+				ValidateFunc: validateStringIn(
+					logSettingsSystem,
+					logSettingsConfig,
+					logSettingsUserId,
+					logSettingsHipMatch,
+					logSettingsGlobalProtect,
+					logSettingsIpTag,
+					logSettingsCorrelation,
+					"traffic",
+					"threat",
+					"wildfire",
+					"url",
+					"data",
+					"gtp",
+					"tunnel",
+					"auth",
+					"sctp",
+					"decryption",
+				),
+			},
+			"match_list": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "List of match list entries",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"filter": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "All Logs",
+						},
+						"snmptrap_server_profiles": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"email_server_profiles": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"syslog_server_profiles": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"http_server_profiles": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createUpdatePanoramaLogCollectorGroupLogForwarding(d *schema.ResourceData, meta interface{}) error {
+	cg := d.Get("collector_group").(string)
+	kind := d.Get("log_type").(string)
+	o := loadPanoramaLogCollectorGroupLogForwarding(d)
+
+	path, err := panoramaLogCollectorGroupLogForwardingXpath(meta, cg, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildPanoramaLogCollectorGroupLogForwardingId(cg, kind))
+	return readPanoramaLogCollectorGroupLogForwarding(d, meta)
+}
+
+func readPanoramaLogCollectorGroupLogForwarding(d *schema.ResourceData, meta interface{}) error {
+	var ans panoramaLogCollectorGroupMatchListAns
+
+	cg, kind := parsePanoramaLogCollectorGroupLogForwardingId(d.Id())
+
+	path, err := panoramaLogCollectorGroupLogForwardingXpath(meta, cg, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.MatchList == nil || len(ans.MatchList.Entries) == 0 {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("collector_group", cg)
+	d.Set("log_type", kind)
+	savePanoramaLogCollectorGroupLogForwarding(d, *ans.MatchList)
+
+	return nil
+}
+
+func deletePanoramaLogCollectorGroupLogForwarding(d *schema.ResourceData, meta interface{}) error {
+	cg, kind := parsePanoramaLogCollectorGroupLogForwardingId(d.Id())
+
+	path, err := panoramaLogCollectorGroupLogForwardingXpath(meta, cg, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(path, nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func loadPanoramaLogCollectorGroupLogForwarding(d *schema.ResourceData) panoramaLogCollectorGroupMatchList {
+	var o panoramaLogCollectorGroupMatchList
+
+	for _, x := range d.Get("match_list").([]interface{}) {
+		mm := x.(map[string]interface{})
+		o.Entries = append(o.Entries, panoramaLogCollectorGroupMatchListEntry{
+			Name:           mm["name"].(string),
+			Description:    mm["description"].(string),
+			Filter:         mm["filter"].(string),
+			SnmpProfiles:   util.StrToMem(setAsList(mm["snmptrap_server_profiles"].(*schema.Set))),
+			EmailProfiles:  util.StrToMem(setAsList(mm["email_server_profiles"].(*schema.Set))),
+			SyslogProfiles: util.StrToMem(setAsList(mm["syslog_server_profiles"].(*schema.Set))),
+			HttpProfiles:   util.StrToMem(setAsList(mm["http_server_profiles"].(*schema.Set))),
+		})
+	}
+
+	return o
+}
+
+func savePanoramaLogCollectorGroupLogForwarding(d *schema.ResourceData, o panoramaLogCollectorGroupMatchList) {
+	mle := make([]interface{}, 0, len(o.Entries))
+	for _, entry := range o.Entries {
+		mle = append(mle, map[string]interface{}{
+			"name":                     entry.Name,
+			"description":              entry.Description,
+			"filter":                   entry.Filter,
+			"snmptrap_server_profiles": listAsSet(util.MemToStr(entry.SnmpProfiles)),
+			"email_server_profiles":    listAsSet(util.MemToStr(entry.EmailProfiles)),
+			"syslog_server_profiles":   listAsSet(util.MemToStr(entry.SyslogProfiles)),
+			"http_server_profiles":     listAsSet(util.MemToStr(entry.HttpProfiles)),
+		})
+	}
+
+	if err := d.Set("match_list", mle); err != nil {
+		log.Printf("[WARN] Error setting 'match_list' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildPanoramaLogCollectorGroupLogForwardingId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parsePanoramaLogCollectorGroupLogForwardingId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
+
+func panoramaLogCollectorGroupLogForwardingXpath(meta interface{}, cg, kind string) ([]string, error) {
+	ans, err := panoramaLogCollectorGroupXpath(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, util.AsEntryXpath([]string{cg}), "log-settings", kind, "match-list"), nil
+}
+
+// Config structs.
+type panoramaLogCollectorGroupMatchList struct {
+	XMLName xml.Name                                  `xml:"match-list"`
+	Entries []panoramaLogCollectorGroupMatchListEntry `xml:"entry"`
+}
+
+type panoramaLogCollectorGroupMatchListEntry struct {
+	Name           string           `xml:"name,attr"`
+	Description    string           `xml:"description,omitempty"`
+	Filter         string           `xml:"filter"`
+	SnmpProfiles   *util.MemberType `xml:"send-snmptrap"`
+	EmailProfiles  *util.MemberType `xml:"send-email"`
+	SyslogProfiles *util.MemberType `xml:"send-syslog"`
+	HttpProfiles   *util.MemberType `xml:"send-http"`
+}
+
+type panoramaLogCollectorGroupMatchListAns struct {
+	MatchList *panoramaLogCollectorGroupMatchList `xml:"result>match-list"`
+}
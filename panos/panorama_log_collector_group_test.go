@@ -0,0 +1,90 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosPanoramaLogCollectorGroup_basic(t *testing.T) {
+	if !testAccIsPanorama {
+		t.Skip(SkipPanoramaAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosPanoramaLogCollectorGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPanoramaLogCollectorGroupConfig(name, 30, true, "high"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_log_collector_group.test", "min_retention_period", "30"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector_group.test", "forward_to_all", "true"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector_group_log_forwarding.test", "match_list.0.filter", "(severity eq high)"),
+				),
+			},
+			{
+				Config: testAccPanoramaLogCollectorGroupConfig(name, 60, false, "critical"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_log_collector_group.test", "min_retention_period", "60"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector_group.test", "forward_to_all", "false"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector_group_log_forwarding.test", "match_list.0.filter", "(severity eq critical)"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosPanoramaLogCollectorGroupDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_panorama_log_collector_group" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			path, err := panoramaLogCollectorGroupXpath(testAccProvider.Meta())
+			if err != nil {
+				return err
+			}
+
+			c := rawClient(testAccProvider.Meta())
+			list, err := c.EntryListUsing(c.Get, path)
+			if err != nil && !isObjectNotFound(err) {
+				return err
+			}
+			for _, x := range list {
+				if x == rs.Primary.ID {
+					return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+				}
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccPanoramaLogCollectorGroupConfig(name string, days int, fwdAll bool, severity string) string {
+	return fmt.Sprintf(`
+resource "panos_panorama_log_collector_group" "test" {
+    name = %q
+    min_retention_period = %d
+    forward_to_all = %t
+}
+
+resource "panos_panorama_log_collector_group_log_forwarding" "test" {
+    collector_group = panos_panorama_log_collector_group.test.name
+    log_type = "system"
+    match_list {
+        name = "acctest"
+        filter = "(severity eq %s)"
+    }
+}
+`, name, days, fwdAll, severity)
+}
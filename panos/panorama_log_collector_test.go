@@ -0,0 +1,92 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosPanoramaLogCollector(t *testing.T) {
+	if !testAccIsPanorama {
+		t.Skip(SkipPanoramaAccTest)
+	}
+
+	serial := fmt.Sprintf("0079%s", acctest.RandStringFromCharSet(8, "0123456789"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosPanoramaLogCollectorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPanoramaLogCollectorConfig(serial, "lc1", "10.1.1.1", "A"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "hostname", "lc1"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "dns_primary", "10.1.1.1"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "ntp_secondary", "1.pool.ntp.org"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "disk_pairs.#", "1"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "interface.0.name", "eth1"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "interface.0.mtu", "1400"),
+				),
+			},
+			{
+				Config: testAccPanoramaLogCollectorConfig(serial, "lc2", "10.2.2.2", "B"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "hostname", "lc2"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "dns_primary", "10.2.2.2"),
+					resource.TestCheckResourceAttr("panos_panorama_log_collector.test", "disk_pairs.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosPanoramaLogCollectorDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_panorama_log_collector" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			path, err := panoramaLogCollectorXpath(testAccProvider.Meta())
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{rs.Primary.ID})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccPanoramaLogCollectorConfig(serial, hostname, dns, pair string) string {
+	return fmt.Sprintf(`
+resource "panos_panorama_log_collector" "test" {
+    serial_number = %q
+    hostname = %q
+    ip_address = "192.168.1.50"
+    netmask = "255.255.255.0"
+    default_gateway = "192.168.1.1"
+    dns_primary = %q
+    ntp_primary = "0.pool.ntp.org"
+    ntp_secondary = "1.pool.ntp.org"
+    timezone = "UTC"
+    disk_pairs = [%q]
+    interface {
+        name = "eth1"
+        ip_address = "10.5.5.5"
+        netmask = "255.255.255.0"
+        mtu = 1400
+    }
+}
+`, serial, hostname, dns, pair)
+}
@@ -0,0 +1,331 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/mgtconfig/device"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourcePanoramaManagedDevice() *schema.Resource {
+	return &schema.Resource{
+		Create: createPanoramaManagedDevice,
+		Read:   readPanoramaManagedDevice,
+		Update: updatePanoramaManagedDevice,
+		Delete: deletePanoramaManagedDevice,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"serial": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The serial number of the device",
+			},
+			"wait_for_connection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Wait for the device to connect to Panorama",
+			},
+			"auth_key_lifetime": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Generate a device registration auth key for this device that is valid for this many minutes",
+			},
+			"auth_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated device registration auth key",
+			},
+
+			// Operational state.
+			"connected": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"hostname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ip_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"model": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sw_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"app_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"av_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"threat_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"wildfire_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func createPanoramaManagedDevice(d *schema.ResourceData, meta interface{}) error {
+	pano, err := panorama(meta, "")
+	if err != nil {
+		return err
+	}
+
+	serial := d.Get("serial").(string)
+	lifetime := d.Get("auth_key_lifetime").(int)
+
+	// Deleting this resource deletes the device, so don't take over a device
+	// that is already there unless it is imported.
+	list, err := pano.MGTConfig.Device.GetList()
+	if err != nil {
+		return err
+	}
+	for _, x := range list {
+		if x == serial {
+			return fmt.Errorf("Managed device %q already exists, import it instead", serial)
+		}
+	}
+
+	if err = pano.MGTConfig.Device.Set(device.Entry{Name: serial}); err != nil {
+		return err
+	}
+	d.SetId(serial)
+
+	if lifetime > 0 {
+		key, err := addPanoramaDeviceAuthKey(pano, serial, lifetime)
+		if err != nil {
+			return err
+		}
+		d.Set("auth_key", key)
+	} else {
+		d.Set("auth_key", "")
+	}
+
+	if d.Get("wait_for_connection").(bool) {
+		deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+		if err = waitForPanoramaManagedDevice(pano, serial, deadline); err != nil {
+			return err
+		}
+	}
+
+	return readPanoramaManagedDevice(d, meta)
+}
+
+func readPanoramaManagedDevice(d *schema.ResourceData, meta interface{}) error {
+	pano, err := panorama(meta, "")
+	if err != nil {
+		return err
+	}
+
+	serial := d.Id()
+
+	if _, err = pano.MGTConfig.Device.Get(serial); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	info, err := getPanoramaManagedDeviceInfo(pano, serial)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		info = &panoramaManagedDeviceInfo{}
+	}
+
+	d.Set("serial", serial)
+	d.Set("connected", info.Connected == "yes")
+	d.Set("hostname", info.Hostname)
+	d.Set("ip_address", info.IpAddress)
+	d.Set("model", info.Model)
+	d.Set("sw_version", info.SwVersion)
+	d.Set("app_version", info.AppVersion)
+	d.Set("av_version", info.AvVersion)
+	d.Set("threat_version", info.ThreatVersion)
+	d.Set("wildfire_version", info.WildfireVersion)
+
+	return nil
+}
+
+func updatePanoramaManagedDevice(d *schema.ResourceData, meta interface{}) error {
+	pano, err := panorama(meta, "")
+	if err != nil {
+		return err
+	}
+
+	// Only wait_for_connection can change, and turning it on after the serial
+	// number has been committed is how the wait is done in two phases.
+	if d.Get("wait_for_connection").(bool) {
+		deadline := time.Now().Add(d.Timeout(schema.TimeoutUpdate))
+		if err = waitForPanoramaManagedDevice(pano, d.Id(), deadline); err != nil {
+			return err
+		}
+	}
+
+	return readPanoramaManagedDevice(d, meta)
+}
+
+func deletePanoramaManagedDevice(d *schema.ResourceData, meta interface{}) error {
+	pano, err := panorama(meta, "")
+	if err != nil {
+		return err
+	}
+
+	if err = pano.MGTConfig.Device.Delete(d.Id()); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// getPanoramaManagedDeviceInfo returns the operational state of the given
+// managed device, or nil if Panorama doesn't know about it yet.
+func getPanoramaManagedDeviceInfo(pano *pango.Panorama, serial string) (*panoramaManagedDeviceInfo, error) {
+	var ans showDevicesAns
+
+	pano.LogOp("(op) show devices all")
+	if _, err := pano.Op(showDevicesReq{}, "", nil, &ans); err != nil {
+		return nil, err
+	}
+
+	for i := range ans.Entries {
+		if ans.Entries[i].Name == serial || ans.Entries[i].Serial == serial {
+			return &ans.Entries[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// waitForPanoramaManagedDevice waits for the given managed device to connect.
+func waitForPanoramaManagedDevice(pano *pango.Panorama, serial string, deadline time.Time) error {
+	return waitForConnection(func() (bool, error) {
+		info, err := getPanoramaManagedDeviceInfo(pano, serial)
+		if err != nil {
+			return false, err
+		}
+		return info != nil && info.Connected == "yes", nil
+	}, deadline, systemOpsPollInterval)
+}
+
+// waitForConnection polls connected until it is true or the deadline passes.
+func waitForConnection(connected func() (bool, error), deadline time.Time, interval time.Duration) error {
+	for {
+		ok, err := connected()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for the device to connect")
+		}
+		time.Sleep(interval)
+	}
+}
+
+var authKeyRegexp = regexp.MustCompile(`(?i)auth\s*key\s*:\s*(\S+)`)
+
+// addPanoramaDeviceAuthKey generates a single use device registration auth
+// key for the given serial number.
+func addPanoramaDeviceAuthKey(pano *pango.Panorama, serial string, lifetime int) (string, error) {
+	var ans authKeyAddAns
+
+	req := authKeyAddReq{
+		Name:     fmt.Sprintf("tf-%s", serial),
+		Lifetime: lifetime,
+		Count:    1,
+		DevType:  "fw",
+		Serials:  []string{serial},
+	}
+
+	pano.LogOp("(op) request authkey add name %s", req.Name)
+	if _, err := pano.Op(req, "", nil, &ans); err != nil {
+		return "", err
+	}
+
+	if m := authKeyRegexp.FindStringSubmatch(ans.Result); m != nil {
+		return m[1], nil
+	}
+
+	fields := strings.Fields(ans.Result)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("No auth key returned for %q", serial)
+	}
+
+	return fields[len(fields)-1], nil
+}
+
+// Op structs.
+type showDevicesReq struct {
+	XMLName xml.Name `xml:"show"`
+	Cmd     string   `xml:"devices>all"`
+}
+
+type showDevicesAns struct {
+	Entries []panoramaManagedDeviceInfo `xml:"result>devices>entry"`
+}
+
+type panoramaManagedDeviceInfo struct {
+	Name            string `xml:"name,attr"`
+	Serial          string `xml:"serial"`
+	Connected       string `xml:"connected"`
+	Hostname        string `xml:"hostname"`
+	IpAddress       string `xml:"ip-address"`
+	Model           string `xml:"model"`
+	SwVersion       string `xml:"sw-version"`
+	AppVersion      string `xml:"app-version"`
+	AvVersion       string `xml:"av-version"`
+	ThreatVersion   string `xml:"threat-version"`
+	WildfireVersion string `xml:"wildfire-version"`
+}
+
+type authKeyAddReq struct {
+	XMLName  xml.Name `xml:"request"`
+	Name     string   `xml:"authkey>add>name"`
+	Lifetime int      `xml:"authkey>add>lifetime"`
+	Count    int      `xml:"authkey>add>count"`
+	DevType  string   `xml:"authkey>add>devtype"`
+	Serials  []string `xml:"authkey>add>serial-numbers>member"`
+}
+
+type authKeyAddAns struct {
+	Result string `xml:"result"`
+}
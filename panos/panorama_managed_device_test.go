@@ -0,0 +1,112 @@
+package panos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosPanoramaManagedDevice_basic(t *testing.T) {
+	if !testAccIsPanorama {
+		t.Skip(SkipPanoramaAccTest)
+	}
+
+	serial := fmt.Sprintf("0000%d", acctest.RandIntRange(10000000, 99999999))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosPanoramaManagedDeviceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPanoramaManagedDeviceConfig(serial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_panorama_managed_device.test", "serial", serial),
+					resource.TestCheckResourceAttr("panos_panorama_managed_device.test", "connected", "false"),
+				),
+			},
+			{
+				ResourceName:      "panos_panorama_managed_device.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The auth key is only known on create.
+				ImportStateVerifyIgnore: []string{"auth_key", "auth_key_lifetime", "wait_for_connection"},
+			},
+			{
+				Config:      testAccPanoramaManagedDeviceDuplicateConfig(serial),
+				ExpectError: regexp.MustCompile("already exists, import it instead"),
+			},
+		},
+	})
+}
+
+func TestWaitForConnection(t *testing.T) {
+	var calls int
+	connected := func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	}
+
+	if err := waitForConnection(connected, time.Now().Add(time.Minute), time.Millisecond); err != nil {
+		t.Fatalf("Error in wait: %s", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+
+	never := func() (bool, error) { return false, nil }
+	if err := waitForConnection(never, time.Now(), time.Millisecond); err == nil {
+		t.Errorf("Expected a timeout error")
+	}
+
+	failed := func() (bool, error) { return false, fmt.Errorf("op failed") }
+	if err := waitForConnection(failed, time.Now().Add(time.Minute), time.Millisecond); err == nil || err.Error() != "op failed" {
+		t.Errorf("Expected the op error, got %v", err)
+	}
+}
+
+func testAccPanosPanoramaManagedDeviceDestroy(s *terraform.State) error {
+	pano := testAccProvider.Meta().(*pango.Panorama)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_panorama_managed_device" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			if _, err := pano.MGTConfig.Device.Get(rs.Primary.ID); err == nil {
+				return fmt.Errorf("Managed device %q still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccPanoramaManagedDeviceConfig(serial string) string {
+	return fmt.Sprintf(`
+resource "panos_panorama_managed_device" "test" {
+    serial = %q
+}
+`, serial)
+}
+
+func testAccPanoramaManagedDeviceDuplicateConfig(serial string) string {
+	return fmt.Sprintf(`
+resource "panos_panorama_managed_device" "test" {
+    serial = %q
+}
+
+resource "panos_panorama_managed_device" "duplicate" {
+    serial = panos_panorama_managed_device.test.serial
+}
+`, serial)
+}
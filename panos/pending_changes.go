@@ -0,0 +1,207 @@
+package panos
+
+import (
+	"encoding/xml"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Data source.
+func dataSourcePendingChanges() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSourcePendingChanges,
+
+		Schema: map[string]*schema.Schema{
+			"include_xml": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Include the running and candidate XML of each changed xpath",
+			},
+			"pending": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "If the candidate config differs from the running config",
+			},
+			"admins": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Admins with pending changes",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"change": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The pending changes",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"xpath": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"admin_history": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "If the xpath was added, modified, or deleted",
+						},
+						"running_xml": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"candidate_xml": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func readDataSourcePendingChanges(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var pending pendingChangesAns
+	var journal configChangesAns
+
+	c := rawClient(meta)
+	includeXml := d.Get("include_xml").(bool)
+
+	c.LogOp("(op) check pending-changes")
+	if _, err = c.Op(pendingChangesReq{}, "", nil, &pending); err != nil {
+		return err
+	}
+
+	c.LogOp("(op) show config list changes")
+	if _, err = c.Op(configChangesReq{}, "", nil, &journal); err != nil {
+		return err
+	}
+
+	adminMap := make(map[string]bool)
+	types := make(map[string]string)
+	running := make(map[string]string)
+	candidate := make(map[string]string)
+	changes := make([]interface{}, 0, len(journal.Entries))
+	for _, x := range journal.Entries {
+		if x.Owner != "" {
+			adminMap[x.Owner] = true
+		}
+
+		if _, ok := types[x.Xpath]; !ok && x.Xpath != "" {
+			if running[x.Xpath], err = getPendingChangeXml(c.Show, x.Xpath); err != nil {
+				return err
+			}
+			if candidate[x.Xpath], err = getPendingChangeXml(c.Get, x.Xpath); err != nil {
+				return err
+			}
+			types[x.Xpath] = pendingChangeType(running[x.Xpath] != "", candidate[x.Xpath] != "")
+		}
+
+		change := map[string]interface{}{
+			"xpath":         x.Xpath,
+			"owner":         x.Owner,
+			"action":        strings.TrimSpace(x.Action),
+			"admin_history": strings.TrimSpace(x.AdminHistory),
+			"type":          types[x.Xpath],
+		}
+		if includeXml {
+			change["running_xml"] = running[x.Xpath]
+			change["candidate_xml"] = candidate[x.Xpath]
+		}
+		changes = append(changes, change)
+	}
+
+	admins := make([]string, 0, len(adminMap))
+	for name := range adminMap {
+		admins = append(admins, name)
+	}
+	sort.Strings(admins)
+
+	d.SetId(c.Hostname)
+	d.Set("include_xml", includeXml)
+	d.Set("pending", strings.TrimSpace(pending.Result) == "yes")
+	if err = d.Set("admins", admins); err != nil {
+		log.Printf("[WARN] Error setting 'admins' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("change", changes); err != nil {
+		log.Printf("[WARN] Error setting 'change' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// getPendingChangeXml returns the config at the given xpath using either
+// show (running config) or get (candidate config).
+//
+// Nothing is returned if the xpath doesn't exist in that config.
+func getPendingChangeXml(fn func(interface{}, interface{}, interface{}) ([]byte, error), xpath string) (string, error) {
+	var ans configXmlAns
+
+	if _, err := fn(xpath, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(ans.Result.Data), nil
+}
+
+// pendingChangeType returns if an xpath was added, modified, or deleted,
+// given if it's in the running and candidate configs.
+//
+// An xpath that is in neither config is "unknown".
+func pendingChangeType(inRunning, inCandidate bool) string {
+	switch {
+	case inRunning && inCandidate:
+		return "modified"
+	case inCandidate:
+		return "added"
+	case inRunning:
+		return "deleted"
+	}
+
+	return "unknown"
+}
+
+// Op structs.
+type pendingChangesReq struct {
+	XMLName xml.Name `xml:"check"`
+	Cmd     string   `xml:"pending-changes"`
+}
+
+type pendingChangesAns struct {
+	Result string `xml:"result"`
+}
+
+type configChangesReq struct {
+	XMLName xml.Name `xml:"show"`
+	Cmd     string   `xml:"config>list>changes"`
+}
+
+type configChangesAns struct {
+	Entries []configChangesEntry `xml:"result>journal>entry"`
+}
+
+type configChangesEntry struct {
+	Xpath        string `xml:"xpath"`
+	Owner        string `xml:"owner"`
+	Action       string `xml:"action"`
+	AdminHistory string `xml:"admin-history"`
+}
@@ -0,0 +1,59 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosDsPendingChanges(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsPendingChangesConfig(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.panos_pending_changes.test", "pending"),
+					resource.TestCheckResourceAttrSet("data.panos_pending_changes.test", "admins.#"),
+					resource.TestCheckResourceAttrSet("data.panos_pending_changes.test", "change.#"),
+				),
+			},
+			{
+				Config: testAccDsPendingChangesConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.panos_pending_changes.test", "include_xml", "true"),
+					resource.TestCheckResourceAttrSet("data.panos_pending_changes.test", "change.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestPendingChangeType(t *testing.T) {
+	testCases := []struct {
+		inRunning   bool
+		inCandidate bool
+		ans         string
+	}{
+		{false, true, "added"},
+		{true, false, "deleted"},
+		{true, true, "modified"},
+		{false, false, "unknown"},
+	}
+
+	for _, tc := range testCases {
+		if ans := pendingChangeType(tc.inRunning, tc.inCandidate); ans != tc.ans {
+			t.Errorf("running:%t candidate:%t: expected %q, got %q", tc.inRunning, tc.inCandidate, tc.ans, ans)
+		}
+	}
+}
+
+func testAccDsPendingChangesConfig(includeXml bool) string {
+	return fmt.Sprintf(`
+data "panos_pending_changes" "test" {
+    include_xml = %t
+}
+`, includeXml)
+}
@@ -0,0 +1,360 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Data sources.
+func dataSourceSecurityPolicyMatch() *schema.Resource {
+	return policyMatchResource("security-policy-match", []string{
+		"from", "to", "source", "destination", "destination_port",
+		"protocol", "application", "source_user", "category", "show_all",
+	})
+}
+
+func dataSourceNatPolicyMatch() *schema.Resource {
+	return policyMatchResource("nat-policy-match", []string{
+		"from", "to", "source", "destination", "source_port",
+		"destination_port", "protocol", "to_interface",
+	})
+}
+
+func dataSourcePbfPolicyMatch() *schema.Resource {
+	return policyMatchResource("pbf-policy-match", []string{
+		"from", "from_interface", "source", "destination",
+		"destination_port", "protocol", "application", "source_user",
+	})
+}
+
+func dataSourceDecryptionPolicyMatch() *schema.Resource {
+	return policyMatchResource("decryption-policy-match", []string{
+		"from", "to", "source", "destination", "destination_port",
+		"protocol", "application", "source_user", "category",
+	})
+}
+
+func policyMatchResource(cmd string, params []string) *schema.Resource {
+	return &schema.Resource{
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return readPolicyMatch(d, meta, cmd, params)
+		},
+
+		Schema: policyMatchSchema(params),
+	}
+}
+
+func readPolicyMatch(d *schema.ResourceData, meta interface{}, cmd string, params []string) error {
+	var err error
+	var loc string
+	var list []policyMatchEntry
+
+	q := loadPolicyMatchQuery(d, cmd, params)
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		loc = d.Get("vsys").(string)
+		d.Set("target", nil)
+		list, err = runPolicyMatch(&con.Client, loc, "", q)
+	case *pango.Panorama:
+		loc = d.Get("target").(string)
+		if loc == "" {
+			loc = con.Target
+		}
+		if loc == "" {
+			return fmt.Errorf("The NGFW serial number to run %q on must be specified as \"target\"", cmd)
+		}
+		d.Set("vsys", nil)
+		list, err = runPolicyMatch(&con.Client, "", loc, q)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.SetId(buildPolicyMatchId(loc, q))
+	savePolicyMatch(d, list)
+
+	return nil
+}
+
+// Schema functions.
+func policyMatchSchema(params []string) map[string]*schema.Schema {
+	ans := map[string]*schema.Schema{
+		"vsys": vsysSchema("vsys1"),
+		"target": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "(Panorama) The NGFW serial number to run the test on",
+		},
+		"matched": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "If a rule matched",
+		},
+		"rule_name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The first matching rule",
+		},
+		"action": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The action of the first matching rule",
+		},
+		"rules": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "All matching rules",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"index": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"action": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+
+	all := map[string]*schema.Schema{
+		"from": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The source zone",
+		},
+		"to": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The destination zone",
+		},
+		"from_interface": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The source interface",
+		},
+		"to_interface": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The egress interface",
+		},
+		"source": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The source IP address",
+		},
+		"destination": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The destination IP address",
+		},
+		"source_port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The source port",
+			ValidateFunc: validation.IntBetween(0, 65535),
+		},
+		"destination_port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The destination port",
+			ValidateFunc: validation.IntBetween(0, 65535),
+		},
+		"protocol": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The IP protocol number (6 for TCP, 17 for UDP)",
+			ValidateFunc: validation.IntBetween(0, 255),
+		},
+		"application": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The application",
+		},
+		"source_user": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The source user",
+		},
+		"category": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The URL category",
+		},
+		"show_all": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Return all matching rules, not just the first",
+		},
+	}
+
+	for _, key := range params {
+		ans[key] = all[key]
+	}
+
+	return ans
+}
+
+func loadPolicyMatchQuery(d *schema.ResourceData, cmd string, params []string) policyMatchQuery {
+	q := policyMatchQuery{XMLName: xml.Name{Local: cmd}}
+
+	for _, key := range params {
+		switch key {
+		case "from":
+			q.From = d.Get(key).(string)
+		case "to":
+			q.To = d.Get(key).(string)
+		case "from_interface":
+			q.FromInterface = d.Get(key).(string)
+		case "to_interface":
+			q.ToInterface = d.Get(key).(string)
+		case "source":
+			q.Source = d.Get(key).(string)
+		case "destination":
+			q.Destination = d.Get(key).(string)
+		case "source_port":
+			q.SourcePort = d.Get(key).(int)
+		case "destination_port":
+			q.DestinationPort = d.Get(key).(int)
+		case "protocol":
+			q.Protocol = d.Get(key).(int)
+		case "application":
+			q.Application = d.Get(key).(string)
+		case "source_user":
+			q.SourceUser = d.Get(key).(string)
+		case "category":
+			q.Category = d.Get(key).(string)
+		case "show_all":
+			if d.Get(key).(bool) {
+				q.ShowAll = "yes"
+			}
+		}
+	}
+
+	return q
+}
+
+func savePolicyMatch(d *schema.ResourceData, list []policyMatchEntry) {
+	var name, action string
+	rules := make([]interface{}, 0, len(list))
+
+	for _, x := range list {
+		rules = append(rules, map[string]interface{}{
+			"name":   x.Name,
+			"index":  x.Index,
+			"action": x.Action,
+		})
+	}
+	if len(list) != 0 {
+		name = list[0].Name
+		action = list[0].Action
+	}
+
+	d.Set("matched", len(list) != 0)
+	d.Set("rule_name", name)
+	d.Set("action", action)
+	if err := d.Set("rules", rules); err != nil {
+		log.Printf("[WARN] Error setting 'rules' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildPolicyMatchId(loc string, q policyMatchQuery) string {
+	return strings.Join([]string{
+		loc, q.XMLName.Local, q.From, q.To, q.Source, q.Destination,
+		strconv.Itoa(q.Protocol), strconv.Itoa(q.DestinationPort),
+	}, IdSeparator)
+}
+
+// Operational state.
+type policyMatchReq struct {
+	XMLName xml.Name `xml:"test"`
+	Query   policyMatchQuery
+}
+
+type policyMatchQuery struct {
+	XMLName         xml.Name
+	From            string `xml:"from,omitempty"`
+	To              string `xml:"to,omitempty"`
+	FromInterface   string `xml:"from-interface,omitempty"`
+	ToInterface     string `xml:"to-interface,omitempty"`
+	Source          string `xml:"source,omitempty"`
+	Destination     string `xml:"destination,omitempty"`
+	SourcePort      int    `xml:"source-port,omitempty"`
+	DestinationPort int    `xml:"destination-port,omitempty"`
+	Protocol        int    `xml:"protocol,omitempty"`
+	Application     string `xml:"application,omitempty"`
+	SourceUser      string `xml:"source-user,omitempty"`
+	Category        string `xml:"category,omitempty"`
+	ShowAll         string `xml:"show-all,omitempty"`
+}
+
+type policyMatchAns struct {
+	Entries []policyMatchAnsEntry `xml:"result>rules>entry"`
+}
+
+// Depending on the PAN-OS version, the rule is either returned as a
+// "name" attribute or as text in the format "name; index: N".
+type policyMatchAnsEntry struct {
+	Name   string `xml:"name,attr"`
+	Text   string `xml:",chardata"`
+	Index  string `xml:"index"`
+	Action string `xml:"action"`
+}
+
+type policyMatchEntry struct {
+	Name   string
+	Index  int
+	Action string
+}
+
+func runPolicyMatch(c *pango.Client, vsys, target string, q policyMatchQuery) ([]policyMatchEntry, error) {
+	var extras interface{}
+	var ans policyMatchAns
+
+	if target != "" {
+		extras = url.Values{"target": []string{target}}
+	}
+
+	c.LogOp("(op) test %s", q.XMLName.Local)
+	if _, err := c.Op(policyMatchReq{Query: q}, vsys, extras, &ans); err != nil {
+		return nil, err
+	}
+
+	list := make([]policyMatchEntry, 0, len(ans.Entries))
+	for _, x := range ans.Entries {
+		o := policyMatchEntry{
+			Name:   x.Name,
+			Action: x.Action,
+		}
+		idx := x.Index
+		if o.Name == "" {
+			tokens := strings.SplitN(strings.TrimSpace(x.Text), ";", 2)
+			o.Name = strings.TrimSpace(tokens[0])
+			if len(tokens) == 2 {
+				idx = strings.TrimPrefix(strings.TrimSpace(tokens[1]), "index:")
+			}
+		}
+		o.Index, _ = strconv.Atoi(strings.TrimSpace(idx))
+		list = append(list, o)
+	}
+
+	return list, nil
+}
@@ -0,0 +1,102 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/commit"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosDsSecurityPolicyMatch(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSecurityPolicyMatchRulesConfig(name),
+			},
+			{
+				// The match runs against the running config, so the rule
+				// has to be committed first.
+				PreConfig: func() { testAccCommitFirewall(t) },
+				Config:    testAccDsSecurityPolicyMatchConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.panos_security_policy_match.test", "matched", "true"),
+					resource.TestCheckResourceAttr("data.panos_security_policy_match.test", "rule_name", name),
+					resource.TestCheckResourceAttr("data.panos_security_policy_match.test", "action", "deny"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCommitFirewall(t *testing.T) {
+	fw := testAccProvider.Meta().(*pango.Firewall)
+
+	id, _, err := fw.Commit(commit.FirewallCommit{Description: "acctest"}, "", nil)
+	if err != nil {
+		t.Fatalf("Error committing: %s", err)
+	}
+	if id == 0 {
+		return
+	}
+	if err = fw.WaitForJob(id, 2*time.Second, nil, nil); err != nil {
+		t.Fatalf("Error waiting for commit job %d: %s", id, err)
+	}
+}
+
+func testAccDsSecurityPolicyMatchConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_security_policy_match" "test" {
+    from = panos_zone.z1.name
+    to = panos_zone.z2.name
+    source = "10.20.30.40"
+    destination = "10.50.60.70"
+    destination_port = 443
+    protocol = 6
+    application = "ssl"
+    depends_on = [panos_security_rule_group.x]
+}
+%s`, testAccSecurityPolicyMatchRulesConfig(name))
+}
+
+func testAccSecurityPolicyMatchRulesConfig(name string) string {
+	return fmt.Sprintf(`
+resource "panos_zone" "z1" {
+    name = "%s1"
+    mode = "layer3"
+}
+
+resource "panos_zone" "z2" {
+    name = "%s2"
+    mode = "layer3"
+}
+
+resource "panos_security_rule_group" "x" {
+    position_keyword = "top"
+    rule {
+        name = %q
+        source_zones = [panos_zone.z1.name]
+        source_addresses = ["10.20.30.0/24"]
+        source_users = ["any"]
+        destination_zones = [panos_zone.z2.name]
+        destination_addresses = ["10.50.60.70"]
+        applications = ["any"]
+        services = ["any"]
+        categories = ["any"]
+        action = "deny"
+    }
+}
+`, name, name, name)
+}
@@ -54,6 +54,16 @@ func Provider() terraform.ResourceProvider {
 				Optional:    true,
 				Description: "Target setting (NGFW serial number)",
 			},
+			"ha_hostnames": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MinItems:    2,
+				MaxItems:    2,
+				Description: "Hostnames/IP addresses of both HA peers; connect to whichever is active instead of hostname",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"additional_headers": {
 				Type:        schema.TypeMap,
 				Optional:    true,
@@ -92,19 +102,28 @@ func Provider() terraform.ResourceProvider {
 			"panos_antivirus_security_profile":          dataSourceAntivirusSecurityProfile(),
 			"panos_antivirus_security_profiles":         dataSourceAntivirusSecurityProfiles(),
 			"panos_api_key":                             dataSourceApiKey(),
+			"panos_application_filter":                  dataSourceApplicationFilter(),
+			"panos_application_filters":                 dataSourceApplicationFilters(),
 			"panos_application_object":                  dataSourceApplicationObject(),
 			"panos_application_objects":                 dataSourceApplicationObjects(),
 			"panos_arp":                                 dataSourceArp(),
 			"panos_arps":                                dataSourceArps(),
 			"panos_audit_comment_history":               dataSourceAuditCommentHistory(),
+			"panos_certificates":                        dataSourceCertificates(),
 			"panos_certificate_profile":                 dataSourceCertificateProfile(),
 			"panos_certificate_profiles":                dataSourceCertificateProfiles(),
+			"panos_config_snapshot":                     dataSourceConfigSnapshot(),
 			"panos_custom_data_pattern_object":          dataSourceCustomDataPatternObject(),
 			"panos_custom_data_pattern_objects":         dataSourceCustomDataPatternObjects(),
+			"panos_custom_spyware_signature":            dataSourceCustomSpywareSignature(),
+			"panos_custom_spyware_signatures":           dataSourceCustomSpywareSignatures(),
 			"panos_custom_url_category":                 dataSourceCustomUrlCategory(),
 			"panos_custom_url_categories":               dataSourceCustomUrlCategories(),
+			"panos_custom_vulnerability_signature":      dataSourceCustomVulnerabilitySignature(),
+			"panos_custom_vulnerability_signatures":     dataSourceCustomVulnerabilitySignatures(),
 			"panos_data_filtering_security_profile":     dataSourceDataFilteringSecurityProfile(),
 			"panos_data_filtering_security_profiles":    dataSourceDataFilteringSecurityProfiles(),
+			"panos_decryption_policy_match":             dataSourceDecryptionPolicyMatch(),
 			"panos_decryption_rule":                     dataSourceDecryptionRule(),
 			"panos_decryption_rules":                    dataSourceDecryptionRules(),
 			"panos_device_group_parent":                 dataSourceDeviceGroupParent(),
@@ -120,13 +139,17 @@ func Provider() terraform.ResourceProvider {
 			"panos_file_blocking_security_profiles":     dataSourceFileBlockingSecurityProfiles(),
 			"panos_globalprotect_ipsec_crypto_profile":  dataSourceGlobalProtectIpsecCryptoProfile(),
 			"panos_globalprotect_ipsec_crypto_profiles": dataSourceGlobalProtectIpsecCryptoProfiles(),
+			"panos_ha_state":                            dataSourceHaState(),
+			"panos_init_cfg":                            dataSourceInitCfg(),
 			"panos_kerberos_profile":                    dataSourceKerberosProfile(),
 			"panos_kerberos_profiles":                   dataSourceKerberosProfiles(),
 			"panos_ldap_profiles":                       dataSourceLdapProfiles(),
 			"panos_local_user_db_group":                 dataSourceLocalUserDbGroup(),
 			"panos_local_user_db_groups":                dataSourceLocalUserDbGroups(),
+			"panos_nat_policy_match":                    dataSourceNatPolicyMatch(),
 			"panos_nat_rule":                            dataSourceNatRule(),
 			"panos_nat_rules":                           dataSourceNatRules(),
+			"panos_op_command":                          dataSourceOpCommand(),
 			"panos_ospf":                                dataSourceOspf(),
 			"panos_ospf_area":                           dataSourceOspfArea(),
 			"panos_ospf_areas":                          dataSourceOspfAreas(),
@@ -137,8 +160,10 @@ func Provider() terraform.ResourceProvider {
 			"panos_ospf_auth_profiles":                  dataSourceOspfAuthProfiles(),
 			"panos_ospf_export":                         dataSourceOspfExport(),
 			"panos_ospf_exports":                        dataSourceOspfExports(),
+			"panos_pbf_policy_match":                    dataSourcePbfPolicyMatch(),
 			"panos_pbf_rule":                            dataSourcePbfRule(),
 			"panos_pbf_rules":                           dataSourcePbfRules(),
+			"panos_pending_changes":                     dataSourcePendingChanges(),
 			"panos_plugin":                              dataSourcePlugin(),
 			"panos_predefined_dlp_file_type":            dataSourcePredefinedDlpFileType(),
 			"panos_predefined_tdb_file_type":            dataSourcePredefinedTdbFileType(),
@@ -146,6 +171,8 @@ func Provider() terraform.ResourceProvider {
 			"panos_radius_profiles":                     dataSourceRadiusProfiles(),
 			"panos_saml_profile":                        dataSourceSamlProfile(),
 			"panos_saml_profiles":                       dataSourceSamlProfiles(),
+			"panos_security_policy_analysis":            dataSourceSecurityPolicyAnalysis(),
+			"panos_security_policy_match":               dataSourceSecurityPolicyMatch(),
 			"panos_security_profile_group":              dataSourceSecurityProfileGroup(),
 			"panos_security_profile_groups":             dataSourceSecurityProfileGroups(),
 			"panos_security_rule":                       dataSourceSecurityRule(),
@@ -166,6 +193,7 @@ func Provider() terraform.ResourceProvider {
 			"panos_vulnerability_security_profiles":     dataSourceVulnerabilitySecurityProfiles(),
 			"panos_wildfire_analysis_security_profile":  dataSourceWildfireAnalysisSecurityProfile(),
 			"panos_wildfire_analysis_security_profiles": dataSourceWildfireAnalysisSecurityProfiles(),
+			"panos_xml_config":                          dataSourceXmlConfig(),
 			"panos_zone":                                dataSourceZone(),
 			"panos_zones":                               dataSourceZones(),
 
@@ -175,9 +203,10 @@ func Provider() terraform.ResourceProvider {
 			"panos_user_tag":            dataSourceUserTag(),
 
 			// Panorama data sources.
-			"panos_vm_auth_key":   dataSourceVmAuthKey(),
-			"panos_device_group":  dataSourceDeviceGroup(),
-			"panos_device_groups": dataSourceDeviceGroups(),
+			"panos_vm_auth_key":               dataSourceVmAuthKey(),
+			"panos_device_group":              dataSourceDeviceGroup(),
+			"panos_device_groups":             dataSourceDeviceGroups(),
+			"panos_panorama_device_variables": dataSourcePanoramaDeviceVariables(),
 
 			// Aliases.
 			"panos_panorama_plugin": dataSourcePlugin(),
@@ -188,17 +217,29 @@ func Provider() terraform.ResourceProvider {
 			"panos_address_object":                        resourceAddressObject(),
 			"panos_address_objects":                       resourceAddressObjects(),
 			"panos_authentication_profile":                resourceAuthenticationProfile(),
+			"panos_authentication_enforcement":            resourceAuthenticationEnforcement(),
+			"panos_authentication_portal":                 resourceAuthenticationPortal(),
+			"panos_authentication_sequence":               resourceAuthenticationSequence(),
 			"panos_anti_spyware_security_profile":         resourceAntiSpywareSecurityProfile(),
 			"panos_antivirus_security_profile":            resourceAntivirusSecurityProfile(),
 			"panos_arp":                                   resourceArp(),
+			"panos_application_filter":                    resourceApplicationFilter(),
+			"panos_certificate":                           resourceCertificate(),
 			"panos_certificate_import":                    resourceCertificateImport(),
 			"panos_certificate_profile":                   resourceCertificateProfile(),
+			"panos_content_update":                        resourceContentUpdate(),
+			"panos_config_rollback":                       resourceConfigRollback(),
+			"panos_config_snapshot":                       resourceConfigSnapshot(),
 			"panos_custom_data_pattern_object":            resourceCustomDataPatternObject(),
+			"panos_custom_spyware_signature":              resourceCustomSpywareSignature(),
 			"panos_custom_url_category":                   resourceCustomUrlCategory(),
 			"panos_custom_url_category_entry":             resourceCustomUrlCategoryEntry(),
+			"panos_custom_vulnerability_signature":        resourceCustomVulnerabilitySignature(),
 			"panos_data_filtering_security_profile":       resourceDataFilteringSecurityProfile(),
 			"panos_decryption_rule_group":                 resourceDecryptionRuleGroup(),
+			"panos_dns_proxy":                             resourceDnsProxy(),
 			"panos_dos_protection_profile":                resourceDosProtectionProfile(),
+			"panos_dynamic_update_schedule":               resourceDynamicUpdateSchedule(),
 			"panos_dynamic_user_group":                    resourceDynamicUserGroup(),
 			"panos_file_blocking_security_profile":        resourceFileBlockingSecurityProfile(),
 			"panos_general_settings":                      resourceGeneralSettings(),
@@ -207,6 +248,14 @@ func Provider() terraform.ResourceProvider {
 			"panos_ldap_profile":                          resourceLdapProfile(),
 			"panos_local_user_db_group":                   resourceLocalUserDbGroup(),
 			"panos_local_user_db_user":                    resourceLocalUserDbUser(),
+			"panos_log_settings_config":                   resourceLogSettingsConfig(),
+			"panos_log_settings_correlation":              resourceLogSettingsCorrelation(),
+			"panos_log_settings_globalprotect":            resourceLogSettingsGlobalProtect(),
+			"panos_log_settings_hipmatch":                 resourceLogSettingsHipMatch(),
+			"panos_log_settings_iptag":                    resourceLogSettingsIpTag(),
+			"panos_log_settings_system":                   resourceLogSettingsSystem(),
+			"panos_log_settings_userid":                   resourceLogSettingsUserId(),
+			"panos_mfa_server_profile":                    resourceMfaServerProfile(),
 			"panos_password_complexity":                   resourcePasswordComplexity(),
 			"panos_ospf":                                  resourceOspf(),
 			"panos_ospf_area":                             resourceOspfArea(),
@@ -215,18 +264,30 @@ func Provider() terraform.ResourceProvider {
 			"panos_ospf_auth_profile":                     resourceOspfAuthProfile(),
 			"panos_ospf_export":                           resourceOspfExport(),
 			"panos_radius_profile":                        resourceRadiusProfile(),
+			"panos_reboot":                                resourceReboot(),
 			"panos_saml_profile":                          resourceSamlProfile(),
 			"panos_security_profile_group":                resourceSecurityProfileGroup(),
+			"panos_service_route":                         resourceServiceRoute(),
 			"panos_setting_management":                    resourceSettingManagement(),
+			"panos_shared_gateway":                        resourceSharedGateway(),
+			"panos_snmp_agent":                            resourceSnmpAgent(),
+			"panos_software_version":                      resourceSoftwareVersion(),
 			"panos_ssl_decrypt":                           resourceSslDecrypt(),
 			"panos_ssl_decrypt_exclude_certificate_entry": resourceSslDecryptExcludeCertificateEntry(),
 			"panos_ssl_decrypt_trusted_root_ca_entry":     resourceSslDecryptTrustedRootCaEntry(),
 			"panos_ssl_tls_service_profile":               resourceSslTlsServiceProfile(),
 			"panos_tacacs_plus_profile":                   resourceTacacsPlusProfile(),
 			"panos_url_filtering_security_profile":        resourceUrlFilteringSecurityProfile(),
+			"panos_userid_agent":                          resourceUseridAgent(),
+			"panos_userid_group_mapping":                  resourceUseridGroupMapping(),
+			"panos_userid_server_monitor":                 resourceUseridServerMonitor(),
 			"panos_vm_information_source":                 resourceVmInformationSource(),
+			"panos_virtual_wire":                          resourceVirtualWire(),
+			"panos_virtual_wire_subinterface":             resourceVirtualWireSubinterface(),
+			"panos_vsys":                                  resourceVsys(),
 			"panos_vulnerability_security_profile":        resourceVulnerabilitySecurityProfile(),
 			"panos_wildfire_analysis_security_profile":    resourceWildfireAnalysisSecurityProfile(),
+			"panos_xml_config":                            resourceXmlConfig(),
 
 			// Panorama resources.
 			"panos_administrators_user":                           resourceAdministratorsUser(),
@@ -256,6 +317,7 @@ func Provider() terraform.ResourceProvider {
 			"panos_panorama_bgp_peer":                             resourcePanoramaBgpPeer(),
 			"panos_panorama_bgp_peer_group":                       resourcePanoramaBgpPeerGroup(),
 			"panos_panorama_bgp_redist_rule":                      resourcePanoramaBgpRedistRule(),
+			"panos_panorama_device_variable":                      resourcePanoramaDeviceVariable(),
 			"panos_panorama_dhcp":                                 resourcePanoramaDHCP(),
 			"panos_panorama_edl":                                  resourcePanoramaEdl(),
 			"panos_panorama_email_server_profile":                 resourcePanoramaEmailServerProfile(),
@@ -274,8 +336,12 @@ func Provider() terraform.ResourceProvider {
 			"panos_panorama_ipsec_tunnel_proxy_id_ipv4":           resourcePanoramaIpsecTunnelProxyIdIpv4(),
 			"panos_panorama_layer2_subinterface":                  resourcePanoramaLayer2Subinterface(),
 			"panos_panorama_layer3_subinterface":                  resourcePanoramaLayer3Subinterface(),
+			"panos_panorama_log_collector":                        resourcePanoramaLogCollector(),
+			"panos_panorama_log_collector_group":                  resourcePanoramaLogCollectorGroup(),
+			"panos_panorama_log_collector_group_log_forwarding":   resourcePanoramaLogCollectorGroupLogForwarding(),
 			"panos_panorama_log_forwarding_profile":               resourcePanoramaLogForwardingProfile(),
 			"panos_panorama_loopback_interface":                   resourcePanoramaLoopbackInterface(),
+			"panos_panorama_managed_device":                       resourcePanoramaManagedDevice(),
 			"panos_panorama_management_profile":                   resourcePanoramaManagementProfile(),
 			"panos_panorama_monitor_profile":                      resourcePanoramaMonitorProfile(),
 			"panos_panorama_nat_rule":                             resourcePanoramaNatRule(),
@@ -370,8 +436,10 @@ func Provider() terraform.ResourceProvider {
 			"panos_syslog_server_profile":                resourceSyslogServerProfile(),
 			"panos_telemetry":                            resourceTelemetry(),
 			"panos_tunnel_interface":                     resourceTunnelInterface(),
+			"panos_user_group_tag":                       resourceUserGroupTag(),
 			"panos_user_tag":                             resourceUserTag(),
 			"panos_userid_login":                         resourceUseridLogin(),
+			"panos_userid_logins":                        resourceUseridLogins(),
 			"panos_haconfig":                             resourceHa(),
 			"panos_virtual_router":                       resourceVirtualRouter(),
 			"panos_virtual_router_entry":                 resourceVirtualRouterEntry(),
@@ -433,23 +501,27 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		}
 	}
 
-	con, err := pango.ConnectUsing(
-		pango.Client{
-			Hostname:          d.Get("hostname").(string),
-			Username:          d.Get("username").(string),
-			Password:          d.Get("password").(string),
-			ApiKey:            d.Get("api_key").(string),
-			Protocol:          d.Get("protocol").(string),
-			Port:              uint(d.Get("port").(int)),
-			Timeout:           d.Get("timeout").(int),
-			Target:            d.Get("target").(string),
-			Headers:           hdrs,
-			Logging:           logging,
-			VerifyCertificate: d.Get("verify_certificate").(bool),
-		},
-		d.Get("json_config_file").(string),
-		true,
-	)
+	client := pango.Client{
+		Hostname:          d.Get("hostname").(string),
+		Username:          d.Get("username").(string),
+		Password:          d.Get("password").(string),
+		ApiKey:            d.Get("api_key").(string),
+		Protocol:          d.Get("protocol").(string),
+		Port:              uint(d.Get("port").(int)),
+		Timeout:           d.Get("timeout").(int),
+		Target:            d.Get("target").(string),
+		Headers:           hdrs,
+		Logging:           logging,
+		VerifyCertificate: d.Get("verify_certificate").(bool),
+	}
+	filename := d.Get("json_config_file").(string)
+
+	var con interface{}
+	if peers := asStringList(d.Get("ha_hostnames").([]interface{})); len(peers) > 0 {
+		con, err = connectToActiveHaPeer(client, filename, peers)
+	} else {
+		con, err = pango.ConnectUsing(client, filename, true)
+	}
 
 	if err != nil {
 		return nil, err
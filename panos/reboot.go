@@ -0,0 +1,61 @@
+package panos
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceReboot() *schema.Resource {
+	return &schema.Resource{
+		Create: createReboot,
+		Read:   readReboot,
+		Delete: deleteReboot,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that cause a reboot when changed",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PAN-OS version running after the reboot",
+			},
+		},
+	}
+}
+
+func createReboot(d *schema.ResourceData, meta interface{}) error {
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+
+	v, err := restartSystem(meta, deadline)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(systemOpsId(rawClient(meta)))
+	d.Set("version", v.String())
+
+	return nil
+}
+
+func readReboot(d *schema.ResourceData, meta interface{}) error {
+	// Nothing to read, a reboot is a one time action.
+	return nil
+}
+
+func deleteReboot(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
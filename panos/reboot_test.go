@@ -0,0 +1,137 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/version"
+)
+
+func TestResourceRebootSchema(t *testing.T) {
+	r := resourceReboot()
+	if err := r.InternalValidate(nil, true); err != nil {
+		t.Fatalf("Schema is invalid: %s", err)
+	}
+
+	if !r.Schema["triggers"].ForceNew {
+		t.Errorf("triggers should be ForceNew")
+	}
+	if !r.Schema["version"].Computed {
+		t.Errorf("version should be computed")
+	}
+	if r.Update != nil {
+		t.Errorf("reboot should not have an update")
+	}
+}
+
+func TestSystemOpsId(t *testing.T) {
+	if id := systemOpsId(&pango.Client{Hostname: "fw.example.com"}); id != "fw.example.com" {
+		t.Errorf("NGFW id is %q", id)
+	}
+
+	if id := systemOpsId(&pango.Client{Hostname: "pano.example.com", Target: "0123456789"}); id != "pano.example.com"+IdSeparator+"0123456789" {
+		t.Errorf("Panorama target id is %q", id)
+	}
+}
+
+func TestWaitsForAutoCommit(t *testing.T) {
+	if !waitsForAutoCommit(&pango.Firewall{}) {
+		t.Errorf("NGFW should wait for the auto-commit")
+	}
+	if waitsForAutoCommit(&pango.Panorama{}) {
+		t.Errorf("Panorama should not wait for the auto-commit")
+	}
+	if !waitsForAutoCommit(&pango.Panorama{Client: pango.Client{Target: "0123456789"}}) {
+		t.Errorf("Panorama with a target should wait for the firewall's auto-commit")
+	}
+}
+
+// testRestartWaiter returns a restartWaiter whose status comes from the given
+// lists, one entry per poll.  The last entry of each list is repeated.
+func testRestartWaiter(up []bool, commits []bool, commitErr error) (*restartWaiter, *int) {
+	var vi, ci int
+	polls := new(int)
+
+	w := &restartWaiter{
+		interval: time.Millisecond,
+		version: func() (version.Number, error) {
+			*polls++
+			i := vi
+			if vi < len(up)-1 {
+				vi++
+			}
+			if !up[i] {
+				return version.Number{}, fmt.Errorf("connection refused")
+			}
+			return version.Number{Major: 10, Minor: 2, Patch: 3}, nil
+		},
+	}
+
+	if commits != nil {
+		w.autoCommit = func() (bool, error) {
+			i := ci
+			if ci < len(commits)-1 {
+				ci++
+			}
+			if commits[i] {
+				return true, commitErr
+			}
+			return false, nil
+		}
+	}
+
+	return w, polls
+}
+
+func TestRestartWaiter(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+
+	w, polls := testRestartWaiter([]bool{true, true, false, false, true}, []bool{false, false, true}, nil)
+	v, err := w.wait(deadline)
+	if err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+	if v.String() != "10.2.3" {
+		t.Errorf("Version is %s, not 10.2.3", v)
+	}
+	if *polls != 5 {
+		t.Errorf("Polled the version %d times, not 5", *polls)
+	}
+}
+
+func TestRestartWaiterNoAutoCommit(t *testing.T) {
+	w, _ := testRestartWaiter([]bool{false, true}, nil, nil)
+	if _, err := w.wait(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Error: %s", err)
+	}
+}
+
+func TestRestartWaiterAutoCommitFailed(t *testing.T) {
+	w, _ := testRestartWaiter([]bool{false, true}, []bool{true}, fmt.Errorf("Auto-commit job 1 failed"))
+	if _, err := w.wait(time.Now().Add(time.Minute)); err == nil {
+		t.Fatalf("Expected the failed auto-commit to be an error")
+	}
+}
+
+func TestRestartWaiterTimeouts(t *testing.T) {
+	testCases := []struct {
+		name    string
+		up      []bool
+		commits []bool
+	}{
+		{"never goes down", []bool{true}, nil},
+		{"never comes back", []bool{false}, nil},
+		{"auto-commit never finishes", []bool{false, true}, []bool{false}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _ := testRestartWaiter(tc.up, tc.commits, nil)
+			if _, err := w.wait(time.Now().Add(20 * time.Millisecond)); err == nil {
+				t.Fatalf("Expected a timeout")
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/fpluchorg/pango/userid"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 func resourceDagTags() *schema.Resource {
@@ -43,69 +44,86 @@ func resourceDagTags() *schema.Resource {
 								Type: schema.TypeString,
 							},
 						},
+						"timeout": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Seconds until the tags expire",
+							ValidateFunc: validation.IntBetween(0, 2592000),
+						},
+						"persistent": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Keep the tags across reboots",
+						},
 					},
 				},
 			},
+			"remaining_lifetime": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Seconds until each IP's tags expire",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
 		},
 	}
 }
 
-func parseDagTags(cur map[string][]string, d *schema.ResourceData) (*userid.Message, *userid.Message, *schema.Set, error) {
+func parseDagTags(cur map[string]registeredTags, d *schema.ResourceData) ([]ipTagRegistration, *userid.Message, *schema.Set, map[string]interface{}, error) {
 	dag := d.Get("register").(*schema.Set)
-	missingMap := make(map[string][]string)
+	registerMap := make(map[string]bool)
 	overlapMap := make(map[string][]string)
 	overlapSet := &schema.Set{F: dag.F}
-	missing := &userid.Message{}
+	register := make([]ipTagRegistration, 0, dag.Len())
 	overlap := &userid.Message{}
+	lifetimes := make(map[string]interface{})
 
 	osl := dag.List()
 	for i := range osl {
 		group := osl[i].(map[string]interface{})
 		key := group["ip"].(string)
-		if _, ok := missingMap[key]; ok {
-			return nil, nil, nil, fmt.Errorf("IP %q already defined, please merge these groups", key)
-		} else if _, ok := overlapMap[key]; ok {
-			return nil, nil, nil, fmt.Errorf("IP %q already defined, please merge these groups", key)
+		if registerMap[key] {
+			return nil, nil, nil, nil, fmt.Errorf("IP %q already defined, please merge these groups", key)
 		}
+		registerMap[key] = true
 		info := cur[key]
 		tl := group["tags"].(*schema.Set).List()
+		tags := make([]string, 0, len(tl))
 		otags := make([]string, 0, len(tl))
-		mtags := make([]string, 0, len(tl))
 		for j := range tl {
 			tag := tl[j].(string)
-			found := false
-			for _, v := range info {
-				if v == tag {
-					found = true
-					break
-				}
-			}
-			if found {
+			tags = append(tags, tag)
+			if info.Has(tag) {
 				otags = append(otags, tag)
-			} else {
-				mtags = append(mtags, tag)
 			}
 		}
+		// Everything is registered each time so that timeouts are refreshed.
+		register = append(register, ipTagRegistration{
+			Ip:         key,
+			Tags:       tags,
+			Timeout:    group["timeout"].(int),
+			Persistent: group["persistent"].(bool),
+		})
 		if len(otags) > 0 {
+			persistent := group["persistent"].(bool)
+			if info.Persistent != "" {
+				persistent = info.Persistent == "1"
+			}
 			ogroup := make(map[string]interface{})
 			ogroup["ip"] = key
 			ogroup["tags"] = listAsSet(otags)
+			ogroup["timeout"] = group["timeout"]
+			ogroup["persistent"] = persistent
 			overlapSet.Add(ogroup)
 			overlapMap[key] = otags
-		}
-		if len(mtags) > 0 {
-			missingMap[key] = mtags
+			if v := info.RemainingLifetime(otags); v > 0 {
+				lifetimes[key] = v
+			}
 		}
 	}
 
-	missing.TagIps = make([]userid.TagIp, 0, len(missingMap))
-	for key, tags := range missingMap {
-		missing.TagIps = append(missing.TagIps, userid.TagIp{
-			Ip:   key,
-			Tags: tags,
-		})
-	}
-
 	overlap.UntagIps = make([]userid.UntagIp, 0, len(overlapMap))
 	for key, tags := range overlapMap {
 		overlap.UntagIps = append(overlap.UntagIps, userid.UntagIp{
@@ -114,25 +132,24 @@ func parseDagTags(cur map[string][]string, d *schema.ResourceData) (*userid.Mess
 		})
 	}
 
-	return missing, overlap, overlapSet, nil
-	//return dag, missingMap, overlapMap, overlapSet, nil
+	return register, overlap, overlapSet, lifetimes, nil
 }
 
 func createUpdateDagTags(d *schema.ResourceData, meta interface{}) error {
 	fw := meta.(*pango.Firewall)
 	vsys := d.Get("vsys").(string)
 
-	cur, err := fw.UserId.GetIpTags("", "", vsys)
+	cur, err := getRegisteredIpTags(&fw.Client, "", vsys)
 	if err != nil {
 		return err
 	}
 
-	missing, _, _, err := parseDagTags(cur, d)
+	register, _, _, _, err := parseDagTags(cur, d)
 	if err != nil {
 		return err
 	}
 
-	if err = fw.UserId.Run(missing, vsys); err != nil {
+	if err = registerIpTags(&fw.Client, vsys, register); err != nil {
 		return err
 	}
 
@@ -144,13 +161,13 @@ func readDagTags(d *schema.ResourceData, meta interface{}) error {
 	fw := meta.(*pango.Firewall)
 	vsys := d.Get("vsys").(string)
 
-	cur, err := fw.UserId.GetIpTags("", "", vsys)
+	cur, err := getRegisteredIpTags(&fw.Client, "", vsys)
 	if err != nil || len(cur) == 0 {
 		d.SetId("")
 		return nil
 	}
 
-	_, _, overlapSet, err := parseDagTags(cur, d)
+	_, _, overlapSet, lifetimes, err := parseDagTags(cur, d)
 	if err != nil {
 		return err
 	}
@@ -159,6 +176,9 @@ func readDagTags(d *schema.ResourceData, meta interface{}) error {
 	if err := d.Set("register", overlapSet); err != nil {
 		log.Printf("[WARN] Error setting 'register' param for %q: %s", d.Id(), err)
 	}
+	if err := d.Set("remaining_lifetime", lifetimes); err != nil {
+		log.Printf("[WARN] Error setting 'remaining_lifetime' param for %q: %s", d.Id(), err)
+	}
 
 	return nil
 }
@@ -167,13 +187,13 @@ func deleteDagTags(d *schema.ResourceData, meta interface{}) error {
 	fw := meta.(*pango.Firewall)
 	vsys := d.Get("vsys").(string)
 
-	cur, err := fw.UserId.GetIpTags("", "", vsys)
+	cur, err := getRegisteredIpTags(&fw.Client, "", vsys)
 	if err != nil {
 		d.SetId("")
 		return nil
 	}
 
-	_, overlap, _, err := parseDagTags(cur, d)
+	_, overlap, _, _, err := parseDagTags(cur, d)
 	if err != nil {
 		return err
 	}
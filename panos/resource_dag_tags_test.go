@@ -141,3 +141,37 @@ resource "panos_dag_tags" "test" {
 }
 `, ip1, ip1t1, ip1t2, ip2, ip2t1, ip3, ip3t1, ip3t2, ip3t3)
 }
+
+func TestAccPanosDagTags_timeout(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDagTagsTimeoutConfig("10.5.5.5", "tag1", 3600, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_dag_tags.test", "register.#", "1"),
+					resource.TestCheckResourceAttrSet("panos_dag_tags.test", "remaining_lifetime.10.5.5.5"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDagTagsTimeoutConfig(ip, tag string, timeout int, persistent bool) string {
+	return fmt.Sprintf(`
+resource "panos_dag_tags" "test" {
+    vsys = "vsys1"
+    register {
+        ip = %q
+        tags = [%q]
+        timeout = %d
+        persistent = %t
+    }
+}
+`, ip, tag, timeout, persistent)
+}
@@ -109,125 +109,137 @@ func logForwardingProfileSchema(p bool) map[string]*schema.Schema {
 							Type: schema.TypeString,
 						},
 					},
-					"action": {
-						Type:     schema.TypeList,
-						Optional: true,
-						Elem: &schema.Resource{
-							Schema: map[string]*schema.Schema{
-								"name": {
-									Type:     schema.TypeString,
-									Required: true,
-								},
-								"azure_integration": {
-									Type:     schema.TypeList,
-									MaxItems: 1,
-									Optional: true,
-									//ConflictsWith: []string{"match_list.action.tagging_integration"},
-									Elem: &schema.Resource{
-										Schema: map[string]*schema.Schema{
-											"azure_integration": {
-												Type:     schema.TypeBool,
-												Optional: true,
-												Default:  true,
+					"action": logForwardingActionSchema(),
+				},
+			},
+		},
+	}
+
+	if p {
+		ans["device_group"] = deviceGroupSchema()
+	} else {
+		ans["vsys"] = vsysSchema("vsys1")
+	}
+
+	return ans
+}
+
+func logForwardingActionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"azure_integration": {
+					Type:     schema.TypeList,
+					MaxItems: 1,
+					Optional: true,
+					//ConflictsWith: []string{"match_list.action.tagging_integration"},
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"azure_integration": {
+								Type:     schema.TypeBool,
+								Optional: true,
+								Default:  true,
+							},
+						},
+					},
+				},
+				"tagging_integration": {
+					Type:     schema.TypeList,
+					MaxItems: 1,
+					Optional: true,
+					//ConflictsWith: []string{"match_list.action.azure_integration"},
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"action": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      action.ActionAddTag,
+								ValidateFunc: validateStringIn(action.ActionAddTag, action.ActionRemoveTag),
+							},
+							"target": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								Default:      action.TargetSource,
+								ValidateFunc: validateStringIn(action.TargetSource, action.TargetDestination),
+							},
+							"timeout": {
+								Type:     schema.TypeInt,
+								Optional: true,
+							},
+							"local_registration": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								/*
+									ConflictsWith: []string{
+										"match_list.action.tagging_integration.remote_registration",
+										"match_list.action.tagging_integration.panorama_registration",
+									},
+								*/
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"tags": {
+											Type:     schema.TypeList,
+											Required: true,
+											MinItems: 1,
+											Elem: &schema.Schema{
+												Type: schema.TypeString,
 											},
 										},
 									},
 								},
-								"tagging_integration": {
-									Type:     schema.TypeList,
-									MaxItems: 1,
-									Optional: true,
-									//ConflictsWith: []string{"match_list.action.azure_integration"},
-									Elem: &schema.Resource{
-										Schema: map[string]*schema.Schema{
-											"action": {
-												Type:         schema.TypeString,
-												Optional:     true,
-												Default:      action.ActionAddTag,
-												ValidateFunc: validateStringIn(action.ActionAddTag, action.ActionRemoveTag),
-											},
-											"target": {
-												Type:         schema.TypeString,
-												Optional:     true,
-												Default:      action.TargetSource,
-												ValidateFunc: validateStringIn(action.TargetSource, action.TargetDestination),
-											},
-											"timeout": {
-												Type:     schema.TypeInt,
-												Optional: true,
-											},
-											"local_registration": {
-												Type:     schema.TypeList,
-												Optional: true,
-												MaxItems: 1,
-												/*
-													ConflictsWith: []string{
-														"match_list.action.tagging_integration.remote_registration",
-														"match_list.action.tagging_integration.panorama_registration",
-													},
-												*/
-												Elem: &schema.Resource{
-													Schema: map[string]*schema.Schema{
-														"tags": {
-															Type:     schema.TypeList,
-															Required: true,
-															MinItems: 1,
-															Elem: &schema.Schema{
-																Type: schema.TypeString,
-															},
-														},
-													},
-												},
-											},
-											"remote_registration": {
-												Type:     schema.TypeList,
-												Optional: true,
-												MaxItems: 1,
-												/*
-													ConflictsWith: []string{
-														"match_list.action.tagging_integration.local_registration",
-														"match_list.action.tagging_integration.panorama_registration",
-													},
-												*/
-												Elem: &schema.Resource{
-													Schema: map[string]*schema.Schema{
-														"http_profile": {
-															Type:     schema.TypeString,
-															Required: true,
-														},
-														"tags": {
-															Type:     schema.TypeList,
-															Required: true,
-															MinItems: 1,
-															Elem: &schema.Schema{
-																Type: schema.TypeString,
-															},
-														},
-													},
-												},
+							},
+							"remote_registration": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								/*
+									ConflictsWith: []string{
+										"match_list.action.tagging_integration.local_registration",
+										"match_list.action.tagging_integration.panorama_registration",
+									},
+								*/
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"http_profile": {
+											Type:     schema.TypeString,
+											Required: true,
+										},
+										"tags": {
+											Type:     schema.TypeList,
+											Required: true,
+											MinItems: 1,
+											Elem: &schema.Schema{
+												Type: schema.TypeString,
 											},
-											"panorama_registration": {
-												Type:     schema.TypeList,
-												Optional: true,
-												MaxItems: 1,
-												/*
-													ConflictsWith: []string{
-														"match_list.action.tagging_integration.local_registration",
-														"match_list.action.tagging_integration.remote_registration",
-													},
-												*/
-												Elem: &schema.Resource{
-													Schema: map[string]*schema.Schema{
-														"tags": {
-															Type:     schema.TypeList,
-															Required: true,
-															MinItems: 1,
-															Elem: &schema.Schema{
-																Type: schema.TypeString,
-															},
-														},
-													},
-												},
+										},
+									},
+								},
+							},
+							"panorama_registration": {
+								Type:     schema.TypeList,
+								Optional: true,
+								MaxItems: 1,
+								/*
+									ConflictsWith: []string{
+										"match_list.action.tagging_integration.local_registration",
+										"match_list.action.tagging_integration.remote_registration",
+									},
+								*/
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"tags": {
+											Type:     schema.TypeList,
+											Required: true,
+											MinItems: 1,
+											Elem: &schema.Schema{
+												Type: schema.TypeString,
 											},
 										},
 									},
@@ -239,14 +251,6 @@ func logForwardingProfileSchema(p bool) map[string]*schema.Schema {
 			},
 		},
 	}
-
-	if p {
-		ans["device_group"] = deviceGroupSchema()
-	} else {
-		ans["vsys"] = vsysSchema("vsys1")
-	}
-
-	return ans
 }
 
 func parseLogForwardingProfile(d *schema.ResourceData) (string, logfwd.Entry, []matchlist.Entry, map[string][]action.Entry) {
@@ -290,34 +294,7 @@ func loadLogForwardingProfile(d *schema.ResourceData) (logfwd.Entry, []matchlist
 			continue
 		}
 
-		action_list := make([]action.Entry, 0, len(ael))
-		for j := range ael {
-			ae := ael[j].(map[string]interface{})
-			action_entry := action.Entry{
-				Name: ae["name"].(string),
-			}
-			if x := asInterfaceMap(ae, "azure_integration"); len(x) != 0 {
-				action_entry.ActionType = action.ActionTypeIntegration
-				action_entry.Action = action.ActionAzure
-			} else if x := asInterfaceMap(ae, "tagging_integration"); len(x) != 0 {
-				action_entry.ActionType = action.ActionTypeTagging
-				action_entry.Action = x["action"].(string)
-				action_entry.Target = x["target"].(string)
-				action_entry.Timeout = x["timeout"].(int)
-				if y := asInterfaceMap(x, "local_registration"); len(y) != 0 {
-					action_entry.Registration = action.RegistrationLocal
-					action_entry.Tags = asStringList(y["tags"].([]interface{}))
-				} else if y := asInterfaceMap(x, "remote_registration"); len(y) != 0 {
-					action_entry.Registration = action.RegistrationRemote
-					action_entry.Tags = asStringList(y["tags"].([]interface{}))
-					action_entry.HttpProfile = y["http_profile"].(string)
-				} else if y := asInterfaceMap(x, "panorama_registration"); len(y) != 0 {
-					action_entry.Registration = action.RegistrationPanorama
-					action_entry.Tags = asStringList(y["tags"].([]interface{}))
-				}
-			}
-			action_list = append(action_list, action_entry)
-		}
+		action_list := loadLogForwardingActions(ael)
 		mla[match_entry.Name] = action_list
 	}
 
@@ -354,50 +331,7 @@ func saveLogForwardingProfile(d *schema.ResourceData, o logfwd.Entry, ml []match
 		if len(action_list) == 0 {
 			mm["action"] = nil
 		} else {
-			ael := make([]interface{}, 0, len(action_list))
-			for _, action_entry := range action_list {
-				ae := map[string]interface{}{
-					"name": action_entry.Name,
-				}
-				switch action_entry.ActionType {
-				case action.ActionTypeIntegration:
-					ae["azure_integration"] = []interface{}{
-						map[string]interface{}{
-							"azure_integration": true,
-						},
-					}
-				case action.ActionTypeTagging:
-					ti := map[string]interface{}{
-						"action":  action_entry.Action,
-						"target":  action_entry.Target,
-						"timeout": action_entry.Timeout,
-					}
-					switch action_entry.Registration {
-					case action.RegistrationLocal:
-						ti["local_registration"] = []interface{}{
-							map[string]interface{}{
-								"tags": listAsSet(action_entry.Tags),
-							},
-						}
-					case action.RegistrationPanorama:
-						ti["panorama_registration"] = []interface{}{
-							map[string]interface{}{
-								"tags": listAsSet(action_entry.Tags),
-							},
-						}
-					case action.RegistrationRemote:
-						ti["remote_registration"] = []interface{}{
-							map[string]interface{}{
-								"tags":         listAsSet(action_entry.Tags),
-								"http_profile": action_entry.HttpProfile,
-							},
-						}
-					}
-					ae["tagging_integration"] = []interface{}{ti}
-				}
-				ael = append(ael, ae)
-			}
-			mm["action"] = ael
+			mm["action"] = saveLogForwardingActions(action_list)
 		}
 
 		mle = append(mle, mm)
@@ -408,6 +342,87 @@ func saveLogForwardingProfile(d *schema.ResourceData, o logfwd.Entry, ml []match
 	}
 }
 
+func loadLogForwardingActions(ael []interface{}) []action.Entry {
+	action_list := make([]action.Entry, 0, len(ael))
+	for j := range ael {
+		ae := ael[j].(map[string]interface{})
+		action_entry := action.Entry{
+			Name: ae["name"].(string),
+		}
+		if x := asInterfaceMap(ae, "azure_integration"); len(x) != 0 {
+			action_entry.ActionType = action.ActionTypeIntegration
+			action_entry.Action = action.ActionAzure
+		} else if x := asInterfaceMap(ae, "tagging_integration"); len(x) != 0 {
+			action_entry.ActionType = action.ActionTypeTagging
+			action_entry.Action = x["action"].(string)
+			action_entry.Target = x["target"].(string)
+			action_entry.Timeout = x["timeout"].(int)
+			if y := asInterfaceMap(x, "local_registration"); len(y) != 0 {
+				action_entry.Registration = action.RegistrationLocal
+				action_entry.Tags = asStringList(y["tags"].([]interface{}))
+			} else if y := asInterfaceMap(x, "remote_registration"); len(y) != 0 {
+				action_entry.Registration = action.RegistrationRemote
+				action_entry.Tags = asStringList(y["tags"].([]interface{}))
+				action_entry.HttpProfile = y["http_profile"].(string)
+			} else if y := asInterfaceMap(x, "panorama_registration"); len(y) != 0 {
+				action_entry.Registration = action.RegistrationPanorama
+				action_entry.Tags = asStringList(y["tags"].([]interface{}))
+			}
+		}
+		action_list = append(action_list, action_entry)
+	}
+
+	return action_list
+}
+
+func saveLogForwardingActions(action_list []action.Entry) []interface{} {
+	ael := make([]interface{}, 0, len(action_list))
+	for _, action_entry := range action_list {
+		ae := map[string]interface{}{
+			"name": action_entry.Name,
+		}
+		switch action_entry.ActionType {
+		case action.ActionTypeIntegration:
+			ae["azure_integration"] = []interface{}{
+				map[string]interface{}{
+					"azure_integration": true,
+				},
+			}
+		case action.ActionTypeTagging:
+			ti := map[string]interface{}{
+				"action":  action_entry.Action,
+				"target":  action_entry.Target,
+				"timeout": action_entry.Timeout,
+			}
+			switch action_entry.Registration {
+			case action.RegistrationLocal:
+				ti["local_registration"] = []interface{}{
+					map[string]interface{}{
+						"tags": listAsSet(action_entry.Tags),
+					},
+				}
+			case action.RegistrationPanorama:
+				ti["panorama_registration"] = []interface{}{
+					map[string]interface{}{
+						"tags": listAsSet(action_entry.Tags),
+					},
+				}
+			case action.RegistrationRemote:
+				ti["remote_registration"] = []interface{}{
+					map[string]interface{}{
+						"tags":         listAsSet(action_entry.Tags),
+						"http_profile": action_entry.HttpProfile,
+					},
+				}
+			}
+			ae["tagging_integration"] = []interface{}{ti}
+		}
+		ael = append(ael, ae)
+	}
+
+	return ael
+}
+
 func parseLogForwardingProfileId(v string) (string, string) {
 	t := strings.Split(v, IdSeparator)
 	return t[0], t[1]
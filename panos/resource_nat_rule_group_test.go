@@ -2,6 +2,7 @@ package panos
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/fpluchorg/pango"
@@ -39,6 +40,105 @@ func TestAccPanosNatRuleGroup_basic(t *testing.T) {
 	})
 }
 
+func TestAccPanosNatRuleGroup_planValidation(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccNatRuleGroupBiDirectionalConfig(name),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("bi-directional static NAT cannot also do destination translation"),
+			},
+		},
+	})
+}
+
+func TestAccPanosNatRuleGroup_planValidationNptv6(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccNatRuleGroupNptv6Config(name),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("nptv6 rules do not support port translation"),
+			},
+		},
+	})
+}
+
+func TestNatRuleValidatorType(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		o       nat.Entry
+		problem string
+	}{
+		{"nat64 dipp", nat.Entry{Type: nat.TypeNat64, SatType: nat.DynamicIpAndPort}, ""},
+		{"nat64 static ip", nat.Entry{Type: nat.TypeNat64, SatType: nat.StaticIp}, "nat64 rules only support dynamic_ip_and_port source translation"},
+		{"nat64 dynamic ip", nat.Entry{Type: nat.TypeNat64, SatType: nat.DynamicIp}, "nat64 rules only support dynamic_ip_and_port source translation"},
+		{"nat64 dynamic dat", nat.Entry{Type: nat.TypeNat64, DatType: nat.DatTypeDynamic}, "nat64 rules do not support dynamic destination translation"},
+		{"nat64 static dat", nat.Entry{Type: nat.TypeNat64, DatType: nat.DatTypeStatic}, ""},
+		{"nptv6 static ip", nat.Entry{Type: nat.TypeNptv6, SatType: nat.StaticIp}, ""},
+		{"nptv6 dipp", nat.Entry{Type: nat.TypeNptv6, SatType: nat.DynamicIpAndPort}, "nptv6 rules only support static_ip source translation"},
+		{"nptv6 dynamic dat", nat.Entry{Type: nat.TypeNptv6, DatType: nat.DatTypeDynamic}, "nptv6 rules do not support dynamic destination translation"},
+		{"nptv6 port", nat.Entry{Type: nat.TypeNptv6, DatType: nat.DatTypeStatic, DatPort: 8080}, "nptv6 rules do not support port translation"},
+		{"ipv4 dynamic ip", nat.Entry{Type: nat.TypeIpv4, SatType: nat.DynamicIp, DatPort: 8080}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			v := &natRuleValidator{}
+			tc.o.Name = "rule1"
+			v.validateType(tc.o)
+
+			if tc.problem == "" {
+				if len(v.problems) != 0 {
+					t.Fatalf("Unexpected problems: %v", v.problems)
+				}
+			} else if len(v.problems) != 1 || v.problems[0] != fmt.Sprintf("rule %q: %s", "rule1", tc.problem) {
+				t.Fatalf("Expected %q, got %v", tc.problem, v.problems)
+			}
+		})
+	}
+}
+
+func TestNatRuleValidatorPanoramaSkipsDeviceChecks(t *testing.T) {
+	v := &natRuleValidator{
+		meta:  &pango.Panorama{},
+		loc:   "dg1",
+		check: true,
+	}
+
+	o := nat.Entry{
+		Name:                   "rule1",
+		Type:                   nat.TypeIpv4,
+		DestinationZone:        "untrust",
+		ToInterface:            "ethernet1/1",
+		SatType:                nat.DynamicIp,
+		SatTranslatedAddresses: []string{"10.1.1.1"},
+	}
+
+	if err := v.validate(o); err != nil {
+		t.Fatalf("Error in validate: %s", err)
+	}
+	if len(v.problems) != 0 {
+		t.Fatalf("Unexpected problems: %v", v.problems)
+	}
+}
+
 func testAccCheckPanosNatRuleGroupExists(top, bot string, o1, o2, o3 *nat.Entry) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		var vsys string
@@ -325,3 +425,58 @@ resource "panos_nat_rule_group" "bot" {
 }
 `, n1, n2, n3)
 }
+
+func testAccNatRuleGroupBiDirectionalConfig(name string) string {
+	return fmt.Sprintf(`
+resource "panos_nat_rule_group" "test" {
+    rule {
+        name = %q
+        original_packet {
+            source_zones = ["any"]
+            destination_zone = "any"
+            source_addresses = ["10.1.1.1"]
+            destination_addresses = ["any"]
+        }
+        translated_packet {
+            source {
+                static_ip {
+                    translated_address = "192.168.1.1"
+                    bi_directional = true
+                }
+            }
+            destination {
+                static_translation {
+                    address = "10.2.2.2"
+                }
+            }
+        }
+    }
+}
+`, name)
+}
+
+func testAccNatRuleGroupNptv6Config(name string) string {
+	return fmt.Sprintf(`
+resource "panos_nat_rule_group" "test" {
+    rule {
+        name = %q
+        type = "nptv6"
+        original_packet {
+            source_zones = ["any"]
+            destination_zone = "any"
+            source_addresses = ["any"]
+            destination_addresses = ["2001:db8::/64"]
+        }
+        translated_packet {
+            source {}
+            destination {
+                static_translation {
+                    address = "2001:db8:1::/64"
+                    port = 8080
+                }
+            }
+        }
+    }
+}
+`, name)
+}
@@ -118,9 +118,17 @@ func updatePanoramaTemplateStack(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 	lo.Copy(o)
+
+	overrides, err := getPanoramaDeviceVariableOverrides(meta, o.Name)
+	if err != nil {
+		return err
+	}
 	if err = pano.Panorama.TemplateStack.Edit(lo); err != nil {
 		return err
 	}
+	if err = restorePanoramaDeviceVariableOverrides(meta, o.Name, overrides, o.Devices); err != nil {
+		return err
+	}
 
 	return readPanoramaTemplateStack(d, meta)
 }
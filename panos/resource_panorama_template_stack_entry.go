@@ -48,7 +48,14 @@ func createPanoramaTemplateStackEntry(d *schema.ResourceData, meta interface{})
 	ts := d.Get("template_stack").(string)
 	dev := d.Get("device").(string)
 
-	if err := pano.Panorama.TemplateStack.EditDevice(ts, dev); err != nil {
+	overrides, err := getPanoramaDeviceVariableOverrides(meta, ts)
+	if err != nil {
+		return err
+	}
+	if err = pano.Panorama.TemplateStack.EditDevice(ts, dev); err != nil {
+		return err
+	}
+	if err = restorePanoramaDeviceVariableOverrides(meta, ts, overrides, []string{dev}); err != nil {
 		return err
 	}
 
@@ -0,0 +1,882 @@
+package panos
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/addr"
+	"github.com/fpluchorg/pango/objs/addrgrp"
+	"github.com/fpluchorg/pango/objs/srvc"
+	"github.com/fpluchorg/pango/objs/srvcgrp"
+	"github.com/fpluchorg/pango/poli/security"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Conflict types.
+const (
+	policyConflictShadowed       = "shadowed"
+	policyConflictRedundant      = "redundant"
+	policyConflictGeneralization = "generalization"
+)
+
+// Data source.
+func dataSourceSecurityPolicyAnalysis() *schema.Resource {
+	return &schema.Resource{
+		Read: readDataSourceSecurityPolicyAnalysis,
+
+		Schema: map[string]*schema.Schema{
+			"vsys":         vsysSchema("vsys1"),
+			"device_group": deviceGroupSchema(),
+			"total_rules": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of enabled rules analyzed",
+			},
+			"conflicts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of conflicts found",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The conflict type: shadowed, redundant, or generalization",
+						},
+						"rule": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The later rule",
+						},
+						"device_group": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "(Panorama) The later rule's device group",
+						},
+						"rulebase": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "(Panorama) The later rule's rulebase",
+						},
+						"conflicting_rule": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The earlier rule",
+						},
+						"conflicting_device_group": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "(Panorama) The earlier rule's device group",
+						},
+						"conflicting_rulebase": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "(Panorama) The earlier rule's rulebase",
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func readDataSourceSecurityPolicyAnalysis(d *schema.ResourceData, meta interface{}) error {
+	var err error
+	var id string
+	var rules []*policyRule
+
+	a := &policyAnalyzer{
+		meta:    meta,
+		objects: make(map[string]*policyObjects),
+	}
+
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		id = d.Get("vsys").(string)
+		d.Set("device_group", nil)
+		rules, err = a.firewallRules(con, id)
+	case *pango.Panorama:
+		id = d.Get("device_group").(string)
+		d.Set("vsys", nil)
+		rules, err = a.panoramaRules(con, id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	conflicts := analyzeSecurityPolicy(rules)
+
+	d.SetId(id)
+	d.Set("total_rules", len(rules))
+	list := make([]interface{}, 0, len(conflicts))
+	for _, x := range conflicts {
+		list = append(list, map[string]interface{}{
+			"type":                     x.Type,
+			"rule":                     x.Rule.Name,
+			"device_group":             x.Rule.DeviceGroup,
+			"rulebase":                 x.Rule.Rulebase,
+			"conflicting_rule":         x.Other.Name,
+			"conflicting_device_group": x.Other.DeviceGroup,
+			"conflicting_rulebase":     x.Other.Rulebase,
+			"description":              x.Description,
+		})
+	}
+	if err = d.Set("conflicts", list); err != nil {
+		return fmt.Errorf("Error setting 'conflicts' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// Analysis.
+type policyConflict struct {
+	Type        string
+	Rule        *policyRule
+	Other       *policyRule
+	Description string
+}
+
+// analyzeSecurityPolicy compares each rule against the rules before it.
+//
+// A rule that is completely matched by an earlier rule can never be hit: it
+// is "redundant" if both rules have the same action, otherwise it is
+// "shadowed".  A rule that completely matches an earlier rule with a
+// different action is a "generalization" of that rule.
+func analyzeSecurityPolicy(rules []*policyRule) []policyConflict {
+	var ans []policyConflict
+
+	for j := 1; j < len(rules); j++ {
+		later := rules[j]
+		for i := 0; i < j; i++ {
+			earlier := rules[i]
+			if earlier.covers(later) {
+				if earlier.isAllow() == later.isAllow() {
+					ans = append(ans, policyConflict{
+						Type:        policyConflictRedundant,
+						Rule:        later,
+						Other:       earlier,
+						Description: fmt.Sprintf("Rule %q is never matched, as rule %q matches all of its traffic with the same action.", later.Name, earlier.Name),
+					})
+				} else {
+					ans = append(ans, policyConflict{
+						Type:        policyConflictShadowed,
+						Rule:        later,
+						Other:       earlier,
+						Description: fmt.Sprintf("Rule %q is never matched, as rule %q matches all of its traffic with action %q instead of %q.", later.Name, earlier.Name, earlier.Action, later.Action),
+					})
+				}
+				break
+			} else if earlier.isAllow() != later.isAllow() && later.covers(earlier) {
+				ans = append(ans, policyConflict{
+					Type:        policyConflictGeneralization,
+					Rule:        later,
+					Other:       earlier,
+					Description: fmt.Sprintf("Rule %q matches all traffic of the earlier rule %q, but with action %q instead of %q.", later.Name, earlier.Name, later.Action, earlier.Action),
+				})
+			}
+		}
+	}
+
+	return ans
+}
+
+// policyRule is a security rule with all objects resolved.
+type policyRule struct {
+	security.Entry
+	DeviceGroup string
+	Rulebase    string
+
+	srcZones  policyNameSet
+	dstZones  policyNameSet
+	src       policyAddrSet
+	dst       policyAddrSet
+	users     policyNameSet
+	hips      policyNameSet
+	apps      policyNameSet
+	services  policyServiceSet
+	cats      policyNameSet
+	srcDevice policyNameSet
+	dstDevice policyNameSet
+}
+
+func (o *policyRule) isAllow() bool {
+	return o.Action == "" || o.Action == "allow"
+}
+
+// covers returns if all traffic matched by rule b is also matched by o.
+func (o *policyRule) covers(b *policyRule) bool {
+	if o.Schedule != "" && o.Schedule != b.Schedule {
+		return false
+	}
+
+	if len(o.Targets) != 0 || o.NegateTarget {
+		if o.NegateTarget != b.NegateTarget || !reflect.DeepEqual(o.Targets, b.Targets) {
+			return false
+		}
+	}
+
+	switch o.Type {
+	case "intrazone":
+		if b.Type != "intrazone" || !o.srcZones.covers(b.srcZones) {
+			return false
+		}
+	case "interzone":
+		if b.Type != "interzone" || !o.srcZones.covers(b.srcZones) || !o.dstZones.covers(b.dstZones) {
+			return false
+		}
+	default:
+		if b.Type == "intrazone" {
+			if !o.srcZones.covers(b.srcZones) || !o.dstZones.covers(b.srcZones) {
+				return false
+			}
+		} else if !o.srcZones.covers(b.srcZones) || !o.dstZones.covers(b.dstZones) {
+			return false
+		}
+	}
+
+	return o.src.covers(b.src) &&
+		o.dst.covers(b.dst) &&
+		o.users.covers(b.users) &&
+		o.hips.covers(b.hips) &&
+		o.apps.covers(b.apps) &&
+		o.services.covers(b.services) &&
+		o.cats.covers(b.cats) &&
+		o.srcDevice.covers(b.srcDevice) &&
+		o.dstDevice.covers(b.dstDevice)
+}
+
+// policyNameSet is a set of names where "any" matches everything.
+type policyNameSet struct {
+	any   bool
+	names map[string]bool
+}
+
+func newPolicyNameSet(list []string) policyNameSet {
+	ans := policyNameSet{names: make(map[string]bool, len(list))}
+	for _, x := range list {
+		if x == "any" {
+			ans.any = true
+		}
+		ans.names[x] = true
+	}
+	if len(list) == 0 {
+		ans.any = true
+	}
+
+	return ans
+}
+
+func (o policyNameSet) covers(b policyNameSet) bool {
+	if o.any {
+		return true
+	} else if b.any {
+		return false
+	}
+
+	for x := range b.names {
+		if !o.names[x] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// policyAddrSet is a set of IP ranges.  Values that cannot be resolved to
+// IPs (FQDNs, regions, EDLs, dynamic groups) are kept as opaque tokens.
+type policyAddrSet struct {
+	any    bool
+	negate bool
+	ranges [][2]net.IP
+	tokens map[string]bool
+}
+
+func (o policyAddrSet) covers(b policyAddrSet) bool {
+	switch {
+	case o.negate != b.negate:
+		return !o.negate && o.any
+	case o.negate:
+		// The complement of A covers the complement of B if B covers A.
+		return b.positiveCovers(o)
+	}
+
+	return o.positiveCovers(b)
+}
+
+func (o policyAddrSet) positiveCovers(b policyAddrSet) bool {
+	if o.any {
+		return true
+	} else if b.any {
+		return false
+	}
+
+	for x := range b.tokens {
+		if !o.tokens[x] {
+			return false
+		}
+	}
+
+	ranges := make([][2]net.IP, len(o.ranges))
+	copy(ranges, o.ranges)
+	sort.Slice(ranges, func(i, j int) bool {
+		return bytes.Compare(ranges[i][0], ranges[j][0]) < 0
+	})
+
+	for _, r := range b.ranges {
+		if !ipRangesCover(ranges, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ipRangesCover returns if the sorted ranges completely cover r.
+func ipRangesCover(ranges [][2]net.IP, r [2]net.IP) bool {
+	cur := r[0]
+	for _, x := range ranges {
+		if bytes.Compare(x[1], cur) < 0 {
+			continue
+		} else if bytes.Compare(x[0], cur) > 0 {
+			return false
+		} else if bytes.Compare(x[1], r[1]) >= 0 {
+			return true
+		}
+		cur = nextIp(x[1])
+		if cur == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextIp returns the IP after the given one, or nil if it would overflow.
+func nextIp(ip net.IP) net.IP {
+	ans := make(net.IP, len(ip))
+	copy(ans, ip)
+	for i := len(ans) - 1; i >= 0; i-- {
+		ans[i]++
+		if ans[i] != 0 {
+			return ans
+		}
+	}
+
+	return nil
+}
+
+// policyServiceSet is a set of port ranges per protocol.  Values that cannot
+// be resolved to destination ports (such as "application-default" or services
+// with a source port) are kept as opaque tokens.
+type policyServiceSet struct {
+	any    bool
+	ports  map[string][][2]int
+	tokens map[string]bool
+}
+
+func (o policyServiceSet) covers(b policyServiceSet) bool {
+	if o.any {
+		return true
+	} else if b.any {
+		return false
+	}
+
+	for x := range b.tokens {
+		if !o.tokens[x] {
+			return false
+		}
+	}
+
+	for proto, list := range b.ports {
+		ranges := make([][2]int, len(o.ports[proto]))
+		copy(ranges, o.ports[proto])
+		sort.Slice(ranges, func(i, j int) bool {
+			return ranges[i][0] < ranges[j][0]
+		})
+		for _, r := range list {
+			cur := r[0]
+			covered := false
+			for _, x := range ranges {
+				if x[1] < cur {
+					continue
+				} else if x[0] > cur {
+					break
+				} else if x[1] >= r[1] {
+					covered = true
+					break
+				}
+				cur = x[1] + 1
+			}
+			if !covered {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// parsePortList parses a port spec such as "80,443,8000-8080".
+func parsePortList(v string) ([][2]int, bool) {
+	var ans [][2]int
+
+	for _, x := range strings.Split(v, ",") {
+		tokens := strings.SplitN(strings.TrimSpace(x), "-", 2)
+		lo, err := strconv.Atoi(tokens[0])
+		if err != nil {
+			return nil, false
+		}
+		hi := lo
+		if len(tokens) == 2 {
+			if hi, err = strconv.Atoi(tokens[1]); err != nil {
+				return nil, false
+			}
+		}
+		ans = append(ans, [2]int{lo, hi})
+	}
+
+	return ans, true
+}
+
+// Object resolution.
+type policyObjects struct {
+	addrs     map[string]addr.Entry
+	addrGrps  map[string]addrgrp.Entry
+	services  map[string]srvc.Entry
+	svcGrps   map[string]srvcgrp.Entry
+	appGroups map[string][]string
+}
+
+type policyAnalyzer struct {
+	meta       interface{}
+	parents    map[string]string
+	objects    map[string]*policyObjects
+	predefined map[string]srvc.Entry
+}
+
+func (a *policyAnalyzer) firewallRules(con *pango.Firewall, vsys string) ([]*policyRule, error) {
+	list, err := con.Policies.Security.GetAll(vsys)
+	if err != nil && !isObjectNotFound(err) {
+		return nil, err
+	}
+
+	return a.resolveRules(list, vsys, "", "")
+}
+
+// panoramaRules returns the rules in the order that a firewall in the given
+// device group evaluates them: pre rules from shared down to the device
+// group, then post rules from the device group back up to shared.
+func (a *policyAnalyzer) panoramaRules(con *pango.Panorama, dg string) ([]*policyRule, error) {
+	var err error
+
+	if dg != "shared" {
+		if a.parents, err = con.Panorama.DeviceGroup.GetParents(); err != nil {
+			return nil, err
+		}
+	}
+
+	chain := a.scopes(dg)
+	order := make([][2]string, 0, 2*len(chain))
+	for i := len(chain) - 1; i >= 0; i-- {
+		order = append(order, [2]string{chain[i], util.PreRulebase})
+	}
+	for _, loc := range chain {
+		order = append(order, [2]string{loc, util.PostRulebase})
+	}
+
+	var ans []*policyRule
+	for _, x := range order {
+		list, err := con.Policies.Security.GetAll(x[0], x[1])
+		if err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		rules, err := a.resolveRules(list, x[0], x[0], x[1])
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, rules...)
+	}
+
+	return ans, nil
+}
+
+// scopes returns the object lookup order for the given location.
+func (a *policyAnalyzer) scopes(loc string) []string {
+	ans := []string{loc}
+	if loc == "shared" {
+		return ans
+	}
+
+	if _, ok := a.meta.(*pango.Panorama); ok {
+		seen := map[string]bool{loc: true}
+		for p := a.parents[loc]; p != "" && !seen[p]; p = a.parents[p] {
+			seen[p] = true
+			ans = append(ans, p)
+		}
+	}
+
+	return append(ans, "shared")
+}
+
+func (a *policyAnalyzer) resolveRules(list []security.Entry, loc, dg, base string) ([]*policyRule, error) {
+	ans := make([]*policyRule, 0, len(list))
+
+	for _, e := range list {
+		if e.Disabled {
+			continue
+		}
+
+		o := &policyRule{
+			Entry:       e,
+			DeviceGroup: dg,
+			Rulebase:    base,
+			srcZones:    newPolicyNameSet(e.SourceZones),
+			dstZones:    newPolicyNameSet(e.DestinationZones),
+			users:       newPolicyNameSet(e.SourceUsers),
+			hips:        newPolicyNameSet(e.HipProfiles),
+			cats:        newPolicyNameSet(e.Categories),
+			srcDevice:   newPolicyNameSet(e.SourceDevices),
+			dstDevice:   newPolicyNameSet(e.DestinationDevices),
+		}
+
+		var err error
+		if o.src, err = a.addrSet(loc, e.SourceAddresses, e.NegateSource); err != nil {
+			return nil, err
+		}
+		if o.dst, err = a.addrSet(loc, e.DestinationAddresses, e.NegateDestination); err != nil {
+			return nil, err
+		}
+		if o.apps, err = a.appSet(loc, e.Applications); err != nil {
+			return nil, err
+		}
+		if o.services, err = a.serviceSet(loc, e.Services); err != nil {
+			return nil, err
+		}
+
+		ans = append(ans, o)
+	}
+
+	return ans, nil
+}
+
+func (a *policyAnalyzer) load(loc string) (*policyObjects, error) {
+	if o, ok := a.objects[loc]; ok {
+		return o, nil
+	}
+
+	var err error
+	var addrList []addr.Entry
+	var addrGrpList []addrgrp.Entry
+	var svcList []srvc.Entry
+	var svcGrpList []srvcgrp.Entry
+	var appGrpNames []string
+	var appGrpGet func(string) ([]string, error)
+
+	switch con := a.meta.(type) {
+	case *pango.Firewall:
+		if addrList, err = con.Objects.Address.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if addrGrpList, err = con.Objects.AddressGroup.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if svcList, err = con.Objects.Services.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if svcGrpList, err = con.Objects.ServiceGroup.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if appGrpNames, err = con.Objects.AppGroup.GetList(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		appGrpGet = func(name string) ([]string, error) {
+			o, err := con.Objects.AppGroup.Get(loc, name)
+			return o.Applications, err
+		}
+	case *pango.Panorama:
+		if addrList, err = con.Objects.Address.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if addrGrpList, err = con.Objects.AddressGroup.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if svcList, err = con.Objects.Services.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if svcGrpList, err = con.Objects.ServiceGroup.GetAll(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		if appGrpNames, err = con.Objects.AppGroup.GetList(loc); err != nil && !isObjectNotFound(err) {
+			return nil, err
+		}
+		appGrpGet = func(name string) ([]string, error) {
+			o, err := con.Objects.AppGroup.Get(loc, name)
+			return o.Applications, err
+		}
+	}
+
+	o := &policyObjects{
+		addrs:     make(map[string]addr.Entry, len(addrList)),
+		addrGrps:  make(map[string]addrgrp.Entry, len(addrGrpList)),
+		services:  make(map[string]srvc.Entry, len(svcList)),
+		svcGrps:   make(map[string]srvcgrp.Entry, len(svcGrpList)),
+		appGroups: make(map[string][]string, len(appGrpNames)),
+	}
+	for _, x := range addrList {
+		o.addrs[x.Name] = x
+	}
+	for _, x := range addrGrpList {
+		o.addrGrps[x.Name] = x
+	}
+	for _, x := range svcList {
+		o.services[x.Name] = x
+	}
+	for _, x := range svcGrpList {
+		o.svcGrps[x.Name] = x
+	}
+	for _, name := range appGrpNames {
+		if o.appGroups[name], err = appGrpGet(name); err != nil {
+			return nil, err
+		}
+	}
+
+	a.objects[loc] = o
+	return o, nil
+}
+
+// lookup returns the objects of the first scope that has the given name.
+func (a *policyAnalyzer) lookup(loc string, has func(*policyObjects) bool) (*policyObjects, error) {
+	for _, scope := range a.scopes(loc) {
+		o, err := a.load(scope)
+		if err != nil {
+			return nil, err
+		}
+		if has(o) {
+			return o, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (a *policyAnalyzer) addrSet(loc string, list []string, negate bool) (policyAddrSet, error) {
+	ans := policyAddrSet{negate: negate, tokens: make(map[string]bool)}
+
+	for _, name := range list {
+		if name == "any" {
+			ans.any = true
+			continue
+		}
+		if err := a.resolveAddr(loc, name, &ans, map[string]bool{}); err != nil {
+			return ans, err
+		}
+	}
+	if len(list) == 0 {
+		ans.any = true
+	}
+
+	return ans, nil
+}
+
+func (a *policyAnalyzer) resolveAddr(loc, name string, ans *policyAddrSet, seen map[string]bool) error {
+	if seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	o, err := a.lookup(loc, func(x *policyObjects) bool {
+		_, ok1 := x.addrs[name]
+		_, ok2 := x.addrGrps[name]
+		return ok1 || ok2
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case o == nil:
+		addAddrValue(ans, name, name)
+	case o.addrs[name].Name != "":
+		x := o.addrs[name]
+		if x.Type == addr.IpNetmask || x.Type == addr.IpRange {
+			addAddrValue(ans, x.Value, name)
+		} else {
+			ans.tokens[x.Type+":"+x.Value] = true
+		}
+	default:
+		grp := o.addrGrps[name]
+		if grp.DynamicMatch != "" {
+			ans.tokens["group:"+name] = true
+			return nil
+		}
+		for _, member := range grp.StaticAddresses {
+			if err = a.resolveAddr(loc, member, ans, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func addAddrValue(ans *policyAddrSet, value, name string) {
+	if lo, hi, ok := addressRange(value); ok {
+		ans.ranges = append(ans.ranges, [2]net.IP{lo.To16(), hi.To16()})
+	} else {
+		ans.tokens[name] = true
+	}
+}
+
+func (a *policyAnalyzer) appSet(loc string, list []string) (policyNameSet, error) {
+	var err error
+	ans := policyNameSet{names: make(map[string]bool)}
+	seen := make(map[string]bool)
+
+	var resolve func(string) error
+	resolve = func(name string) error {
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+
+		o, err := a.lookup(loc, func(x *policyObjects) bool {
+			_, ok := x.appGroups[name]
+			return ok
+		})
+		if err != nil {
+			return err
+		} else if o == nil {
+			ans.names[name] = true
+			return nil
+		}
+
+		for _, member := range o.appGroups[name] {
+			if err = resolve(member); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range list {
+		if name == "any" {
+			ans.any = true
+		} else if err = resolve(name); err != nil {
+			return ans, err
+		}
+	}
+	if len(list) == 0 {
+		ans.any = true
+	}
+
+	return ans, nil
+}
+
+func (a *policyAnalyzer) serviceSet(loc string, list []string) (policyServiceSet, error) {
+	ans := policyServiceSet{
+		ports:  make(map[string][][2]int),
+		tokens: make(map[string]bool),
+	}
+
+	for _, name := range list {
+		switch name {
+		case "any":
+			ans.any = true
+		case "application-default":
+			ans.tokens[name] = true
+		default:
+			if err := a.resolveService(loc, name, &ans, map[string]bool{}); err != nil {
+				return ans, err
+			}
+		}
+	}
+	if len(list) == 0 {
+		ans.tokens["application-default"] = true
+	}
+
+	return ans, nil
+}
+
+func (a *policyAnalyzer) resolveService(loc, name string, ans *policyServiceSet, seen map[string]bool) error {
+	if seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	o, err := a.lookup(loc, func(x *policyObjects) bool {
+		_, ok1 := x.services[name]
+		_, ok2 := x.svcGrps[name]
+		return ok1 || ok2
+	})
+	if err != nil {
+		return err
+	}
+
+	var svc srvc.Entry
+	switch {
+	case o == nil:
+		if err = a.loadPredefinedServices(); err != nil {
+			return err
+		}
+		svc = a.predefined[name]
+	case o.services[name].Name != "":
+		svc = o.services[name]
+	default:
+		for _, member := range o.svcGrps[name].Services {
+			if err = a.resolveService(loc, member, ans, seen); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if svc.Name == "" || svc.SourcePort != "" {
+		ans.tokens[name] = true
+	} else if ports, ok := parsePortList(svc.DestinationPort); !ok {
+		ans.tokens[name] = true
+	} else {
+		ans.ports[svc.Protocol] = append(ans.ports[svc.Protocol], ports...)
+	}
+
+	return nil
+}
+
+func (a *policyAnalyzer) loadPredefinedServices() error {
+	if a.predefined != nil {
+		return nil
+	}
+
+	var err error
+	var list []srvc.Entry
+
+	switch con := a.meta.(type) {
+	case *pango.Firewall:
+		list, err = con.Predefined.Services.GetAll()
+	case *pango.Panorama:
+		list, err = con.Predefined.Services.GetAll()
+	}
+
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	a.predefined = make(map[string]srvc.Entry, len(list))
+	for _, x := range list {
+		a.predefined[x.Name] = x
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosDsSecurityPolicyAnalysis(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsSecurityPolicyAnalysisConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.panos_security_policy_analysis.test", "total_rules"),
+					testAccCheckSecurityPolicyConflict(policyConflictShadowed, name+"b", name+"a"),
+					testAccCheckSecurityPolicyConflict(policyConflictRedundant, name+"c", name+"a"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSecurityPolicyConflict(cType, rule, other string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["data.panos_security_policy_analysis.test"]
+		if !ok {
+			return fmt.Errorf("Data source not found")
+		}
+
+		attrs := rs.Primary.Attributes
+		for i := 0; attrs[fmt.Sprintf("conflicts.%d.type", i)] != ""; i++ {
+			prefix := fmt.Sprintf("conflicts.%d.", i)
+			if attrs[prefix+"type"] == cType && attrs[prefix+"rule"] == rule && attrs[prefix+"conflicting_rule"] == other {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("No %s conflict found for %q and %q", cType, rule, other)
+	}
+}
+
+func testAccDsSecurityPolicyAnalysisConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_security_policy_analysis" "test" {
+    vsys = panos_security_rule_group.x.vsys
+}
+
+resource "panos_address_object" "x" {
+    name = "%s"
+    value = "10.1.2.0/24"
+}
+
+resource "panos_address_group" "x" {
+    name = "%s"
+    static_addresses = [panos_address_object.x.name]
+}
+
+resource "panos_security_rule_group" "x" {
+    rule {
+        name = "%sa"
+        source_zones = ["any"]
+        source_addresses = ["10.1.0.0/16"]
+        source_users = ["any"]
+        destination_zones = ["any"]
+        destination_addresses = ["any"]
+        applications = ["any"]
+        services = ["any"]
+        categories = ["any"]
+        action = "allow"
+    }
+    rule {
+        name = "%sb"
+        source_zones = ["any"]
+        source_addresses = [panos_address_group.x.name]
+        source_users = ["any"]
+        destination_zones = ["any"]
+        destination_addresses = ["any"]
+        applications = ["any"]
+        services = ["any"]
+        categories = ["any"]
+        action = "deny"
+    }
+    rule {
+        name = "%sc"
+        source_zones = ["any"]
+        source_addresses = ["10.1.3.1-10.1.3.9"]
+        source_users = ["any"]
+        destination_zones = ["any"]
+        destination_addresses = ["any"]
+        applications = ["any"]
+        services = ["service-http"]
+        categories = ["any"]
+        action = "allow"
+    }
+}
+`, name, name, name, name, name)
+}
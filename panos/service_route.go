@@ -0,0 +1,209 @@
+package panos
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceServiceRoute() *schema.Resource {
+	return &schema.Resource{
+		Create: createServiceRoute,
+		Read:   readServiceRoute,
+		Update: updateServiceRoute,
+		Delete: deleteServiceRoute,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"service": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The service, such as dns, ntp, or panorama",
+			},
+			"ipv4_source_interface": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The IPv4 source interface",
+			},
+			"ipv4_source_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The IPv4 source address of the source interface",
+			},
+			"ipv6_source_interface": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The IPv6 source interface",
+			},
+			"ipv6_source_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The IPv6 source address of the source interface",
+			},
+		},
+	}
+}
+
+func createServiceRoute(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	o := loadServiceRoute(d)
+
+	path, err := serviceRouteXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildServiceRouteId(tmpl, ts, o.Name))
+	return readServiceRoute(d, meta)
+}
+
+func readServiceRoute(d *schema.ResourceData, meta interface{}) error {
+	var ans serviceRouteAns
+
+	tmpl, ts, name := parseServiceRouteId(d.Id())
+
+	path, err := serviceRouteXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	saveServiceRoute(d, *ans.Entry)
+
+	return nil
+}
+
+func updateServiceRoute(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, name := parseServiceRouteId(d.Id())
+	o := loadServiceRoute(d)
+
+	path, err := serviceRouteXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readServiceRoute(d, meta)
+}
+
+func deleteServiceRoute(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, name := parseServiceRouteId(d.Id())
+
+	path, err := serviceRouteXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadServiceRoute(d *schema.ResourceData) serviceRouteEntry {
+	o := serviceRouteEntry{
+		Name: d.Get("service").(string),
+	}
+
+	if iface, addr := d.Get("ipv4_source_interface").(string), d.Get("ipv4_source_address").(string); iface != "" || addr != "" {
+		o.Source = &serviceRouteSource{Interface: iface, Address: addr}
+	}
+	if iface, addr := d.Get("ipv6_source_interface").(string), d.Get("ipv6_source_address").(string); iface != "" || addr != "" {
+		o.SourceV6 = &serviceRouteSource{Interface: iface, Address: addr}
+	}
+
+	return o
+}
+
+func saveServiceRoute(d *schema.ResourceData, o serviceRouteEntry) {
+	var v4, v6 serviceRouteSource
+
+	if o.Source != nil {
+		v4 = *o.Source
+	}
+	if o.SourceV6 != nil {
+		v6 = *o.SourceV6
+	}
+
+	d.Set("service", o.Name)
+	d.Set("ipv4_source_interface", v4.Interface)
+	d.Set("ipv4_source_address", v4.Address)
+	d.Set("ipv6_source_interface", v6.Interface)
+	d.Set("ipv6_source_address", v6.Address)
+}
+
+// Id functions.
+func buildServiceRouteId(a, b, c string) string {
+	return strings.Join([]string{a, b, c}, IdSeparator)
+}
+
+func parseServiceRouteId(v string) (string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2]
+}
+
+func serviceRouteXpath(meta interface{}, tmpl, ts string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "deviceconfig", "system", "route", "service"), nil
+}
+
+// Config structs.
+type serviceRouteEntry struct {
+	XMLName  xml.Name            `xml:"entry"`
+	Name     string              `xml:"name,attr"`
+	Source   *serviceRouteSource `xml:"source"`
+	SourceV6 *serviceRouteSource `xml:"source-v6"`
+}
+
+type serviceRouteSource struct {
+	Interface string `xml:"interface,omitempty"`
+	Address   string `xml:"address,omitempty"`
+}
+
+type serviceRouteAns struct {
+	Entry *serviceRouteEntry `xml:"result>entry"`
+}
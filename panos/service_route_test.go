@@ -0,0 +1,77 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosServiceRoute(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosServiceRouteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceRouteConfig("10.5.6.1/24"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_service_route.test", "ipv4_source_interface", "ethernet1/8"),
+					resource.TestCheckResourceAttr("panos_service_route.test", "ipv4_source_address", "10.5.6.1/24"),
+				),
+			},
+			{
+				Config: testAccServiceRouteConfig("10.5.7.1/24"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_service_route.test", "ipv4_source_address", "10.5.7.1/24"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosServiceRouteDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_service_route" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, name := parseServiceRouteId(rs.Primary.ID)
+			path, err := serviceRouteXpath(testAccProvider.Meta(), tmpl, ts)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccServiceRouteConfig(addr string) string {
+	return fmt.Sprintf(`
+resource "panos_ethernet_interface" "test" {
+    name = "ethernet1/8"
+    mode = "layer3"
+    static_ips = [%q]
+}
+
+resource "panos_service_route" "test" {
+    service = "ntp"
+    ipv4_source_interface = panos_ethernet_interface.test.name
+    ipv4_source_address = %q
+}
+`, addr, addr)
+}
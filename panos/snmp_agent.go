@@ -0,0 +1,489 @@
+package panos
+
+import (
+	"encoding/xml"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Resource.
+func resourceSnmpAgent() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdateSnmpAgent,
+		Read:   readSnmpAgent,
+		Update: createUpdateSnmpAgent,
+		Delete: deleteSnmpAgent,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: snmpAgentSchema(),
+	}
+}
+
+func createUpdateSnmpAgent(d *schema.ResourceData, meta interface{}) error {
+	var ans snmpAgentAns
+
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	o := loadSnmpAgent(d)
+
+	path, err := snmpAgentXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	// Save the encrypted passwords so that drift can be detected.
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		return err
+	}
+	d.SetId(buildSnmpAgentId(tmpl, ts))
+	saveSnmpAgentPasswords(d, o, ans.Config)
+
+	return readSnmpAgent(d, meta)
+}
+
+func readSnmpAgent(d *schema.ResourceData, meta interface{}) error {
+	var ans snmpAgentAns
+
+	tmpl, ts := parseSnmpAgentId(d.Id())
+
+	path, err := snmpAgentXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Config == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	saveSnmpAgent(d, *ans.Config)
+
+	return nil
+}
+
+func deleteSnmpAgent(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts := parseSnmpAgentId(d.Id())
+
+	path, err := snmpAgentXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(path, nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func snmpAgentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template":       templateSchema(true),
+		"template_stack": templateStackSchema(),
+		"location": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The physical location of the device",
+		},
+		"contact": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The administrator contact",
+		},
+		"send_event_specific_traps": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Send a unique OID per event type instead of a generic OID",
+		},
+		"version": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "v2c",
+			Description:  "The SNMP version",
+			ValidateFunc: validation.StringInSlice([]string{"v2c", "v3"}, false),
+		},
+		"community": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "(v2c) The community string",
+		},
+		"v3_view": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "(v3) Views",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"oid": {
+						Type:     schema.TypeList,
+						Required: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"oid": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"option": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Default:      "include",
+									ValidateFunc: validation.StringInSlice([]string{"include", "exclude"}, false),
+								},
+								"mask": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"v3_user": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "(v3) Users",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"view": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"auth_password": {
+						Type:      schema.TypeString,
+						Required:  true,
+						Sensitive: true,
+					},
+					"priv_password": {
+						Type:      schema.TypeString,
+						Required:  true,
+						Sensitive: true,
+					},
+					"auth_protocol": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"priv_protocol": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"auth_password_enc": {
+			Type:      schema.TypeMap,
+			Computed:  true,
+			Sensitive: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"auth_password_raw": {
+			Type:      schema.TypeMap,
+			Computed:  true,
+			Sensitive: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"priv_password_enc": {
+			Type:      schema.TypeMap,
+			Computed:  true,
+			Sensitive: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"priv_password_raw": {
+			Type:      schema.TypeMap,
+			Computed:  true,
+			Sensitive: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+}
+
+func loadSnmpAgent(d *schema.ResourceData) snmpAgentConfig {
+	o := snmpAgentConfig{
+		System: &snmpAgentSystem{
+			Location:               d.Get("location").(string),
+			Contact:                d.Get("contact").(string),
+			SendEventSpecificTraps: util.YesNo(d.Get("send_event_specific_traps").(bool)),
+		},
+		Access: &snmpAgentAccess{},
+	}
+
+	if d.Get("version").(string) == "v2c" {
+		o.Access.V2c = &snmpAgentV2c{
+			Community: d.Get("community").(string),
+		}
+		return o
+	}
+
+	v3 := &snmpAgentV3{}
+	if list := d.Get("v3_view").([]interface{}); len(list) != 0 {
+		v3.Views = &snmpAgentViews{}
+		for i := range list {
+			x := list[i].(map[string]interface{})
+			view := snmpAgentView{Name: x["name"].(string)}
+			for _, oi := range x["oid"].([]interface{}) {
+				y := oi.(map[string]interface{})
+				view.Oids = append(view.Oids, snmpAgentOid{
+					Name:   y["name"].(string),
+					Oid:    y["oid"].(string),
+					Option: y["option"].(string),
+					Mask:   y["mask"].(string),
+				})
+			}
+			v3.Views.Entries = append(v3.Views.Entries, view)
+		}
+	}
+	if list := d.Get("v3_user").([]interface{}); len(list) != 0 {
+		v3.Users = &snmpAgentUsers{}
+		for i := range list {
+			x := list[i].(map[string]interface{})
+			v3.Users.Entries = append(v3.Users.Entries, snmpAgentUser{
+				Name:         x["name"].(string),
+				View:         x["view"].(string),
+				AuthPassword: x["auth_password"].(string),
+				PrivPassword: x["priv_password"].(string),
+				AuthProtocol: x["auth_protocol"].(string),
+				PrivProtocol: x["priv_protocol"].(string),
+			})
+		}
+	}
+	o.Access.V3 = v3
+
+	return o
+}
+
+func saveSnmpAgentPasswords(d *schema.ResourceData, unencrypted snmpAgentConfig, encrypted *snmpAgentConfig) {
+	authEnc := make(map[string]interface{})
+	authRaw := make(map[string]interface{})
+	privEnc := make(map[string]interface{})
+	privRaw := make(map[string]interface{})
+
+	for _, x := range snmpAgentUserList(&unencrypted) {
+		authRaw[x.Name] = x.AuthPassword
+		privRaw[x.Name] = x.PrivPassword
+	}
+	for _, x := range snmpAgentUserList(encrypted) {
+		authEnc[x.Name] = x.AuthPassword
+		privEnc[x.Name] = x.PrivPassword
+	}
+
+	if err := d.Set("auth_password_enc", authEnc); err != nil {
+		log.Printf("[WARN] Error setting 'auth_password_enc' for %q: %s", d.Id(), err)
+	}
+	if err := d.Set("auth_password_raw", authRaw); err != nil {
+		log.Printf("[WARN] Error setting 'auth_password_raw' for %q: %s", d.Id(), err)
+	}
+	if err := d.Set("priv_password_enc", privEnc); err != nil {
+		log.Printf("[WARN] Error setting 'priv_password_enc' for %q: %s", d.Id(), err)
+	}
+	if err := d.Set("priv_password_raw", privRaw); err != nil {
+		log.Printf("[WARN] Error setting 'priv_password_raw' for %q: %s", d.Id(), err)
+	}
+}
+
+func saveSnmpAgent(d *schema.ResourceData, o snmpAgentConfig) {
+	var err error
+	var sys snmpAgentSystem
+	var community string
+	var views, users []interface{}
+
+	if o.System != nil {
+		sys = *o.System
+	}
+
+	version := "v2c"
+	if o.Access != nil {
+		if o.Access.V2c != nil {
+			community = o.Access.V2c.Community
+		} else if o.Access.V3 != nil {
+			version = "v3"
+		}
+	}
+
+	if version == "v3" && o.Access.V3.Views != nil {
+		for _, x := range o.Access.V3.Views.Entries {
+			oids := make([]interface{}, 0, len(x.Oids))
+			for _, y := range x.Oids {
+				oids = append(oids, map[string]interface{}{
+					"name":   y.Name,
+					"oid":    y.Oid,
+					"option": y.Option,
+					"mask":   y.Mask,
+				})
+			}
+			views = append(views, map[string]interface{}{
+				"name": x.Name,
+				"oid":  oids,
+			})
+		}
+	}
+
+	apMap := d.Get("auth_password_enc").(map[string]interface{})
+	apMapRaw := d.Get("auth_password_raw").(map[string]interface{})
+	ppMap := d.Get("priv_password_enc").(map[string]interface{})
+	ppMapRaw := d.Get("priv_password_raw").(map[string]interface{})
+	for _, x := range snmpAgentUserList(&o) {
+		authPassword := "(incorrect password)"
+		privPassword := "(incorrect password)"
+		if apMap[x.Name] != nil && apMap[x.Name].(string) == x.AuthPassword {
+			authPassword = apMapRaw[x.Name].(string)
+		}
+		if ppMap[x.Name] != nil && ppMap[x.Name].(string) == x.PrivPassword {
+			privPassword = ppMapRaw[x.Name].(string)
+		}
+		users = append(users, map[string]interface{}{
+			"name":          x.Name,
+			"view":          x.View,
+			"auth_password": authPassword,
+			"priv_password": privPassword,
+			"auth_protocol": x.AuthProtocol,
+			"priv_protocol": x.PrivProtocol,
+		})
+	}
+
+	d.Set("location", sys.Location)
+	d.Set("contact", sys.Contact)
+	d.Set("send_event_specific_traps", util.AsBool(sys.SendEventSpecificTraps))
+	d.Set("version", version)
+	d.Set("community", community)
+	if err = d.Set("v3_view", views); err != nil {
+		log.Printf("[WARN] Error setting 'v3_view' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("v3_user", users); err != nil {
+		log.Printf("[WARN] Error setting 'v3_user' for %q: %s", d.Id(), err)
+	}
+}
+
+func snmpAgentUserList(o *snmpAgentConfig) []snmpAgentUser {
+	if o == nil || o.Access == nil || o.Access.V3 == nil || o.Access.V3.Users == nil {
+		return nil
+	}
+
+	return o.Access.V3.Users.Entries
+}
+
+// Id functions.
+func buildSnmpAgentId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parseSnmpAgentId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
+
+func snmpAgentXpath(meta interface{}, tmpl, ts string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "deviceconfig", "system", "snmp-setting"), nil
+}
+
+// Config structs.
+type snmpAgentConfig struct {
+	XMLName xml.Name         `xml:"snmp-setting"`
+	System  *snmpAgentSystem `xml:"snmp-system"`
+	Access  *snmpAgentAccess `xml:"access-setting>version"`
+}
+
+type snmpAgentSystem struct {
+	Location               string `xml:"location,omitempty"`
+	Contact                string `xml:"contact,omitempty"`
+	SendEventSpecificTraps string `xml:"send-event-specific-traps"`
+}
+
+type snmpAgentAccess struct {
+	V2c *snmpAgentV2c `xml:"v2c"`
+	V3  *snmpAgentV3  `xml:"v3"`
+}
+
+type snmpAgentV2c struct {
+	Community string `xml:"snmp-community-string,omitempty"`
+}
+
+type snmpAgentV3 struct {
+	Views *snmpAgentViews `xml:"views"`
+	Users *snmpAgentUsers `xml:"users"`
+}
+
+type snmpAgentViews struct {
+	Entries []snmpAgentView `xml:"entry"`
+}
+
+type snmpAgentView struct {
+	Name string         `xml:"name,attr"`
+	Oids []snmpAgentOid `xml:"view>entry"`
+}
+
+type snmpAgentOid struct {
+	Name   string `xml:"name,attr"`
+	Oid    string `xml:"oid"`
+	Option string `xml:"option"`
+	Mask   string `xml:"mask,omitempty"`
+}
+
+type snmpAgentUsers struct {
+	Entries []snmpAgentUser `xml:"entry"`
+}
+
+type snmpAgentUser struct {
+	Name         string `xml:"name,attr"`
+	View         string `xml:"view"`
+	AuthPassword string `xml:"authpwd"`
+	PrivPassword string `xml:"privpwd"`
+	AuthProtocol string `xml:"authproto,omitempty"`
+	PrivProtocol string `xml:"privproto,omitempty"`
+}
+
+type snmpAgentAns struct {
+	Config *snmpAgentConfig `xml:"result>snmp-setting"`
+}
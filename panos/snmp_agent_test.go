@@ -0,0 +1,71 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosSnmpAgent(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSnmpAgentV2cConfig("lab", "secret1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "location", "lab"),
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "version", "v2c"),
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "community", "secret1"),
+				),
+			},
+			{
+				Config: testAccSnmpAgentV3Config("datacenter"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "location", "datacenter"),
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "version", "v3"),
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "v3_view.0.oid.0.oid", "1.3.6.1"),
+					resource.TestCheckResourceAttr("panos_snmp_agent.test", "v3_user.0.auth_password", "authpassword"),
+				),
+			},
+		},
+	})
+}
+
+func testAccSnmpAgentV2cConfig(location, community string) string {
+	return fmt.Sprintf(`
+resource "panos_snmp_agent" "test" {
+    location = %q
+    contact = "admin@example.com"
+    community = %q
+}
+`, location, community)
+}
+
+func testAccSnmpAgentV3Config(location string) string {
+	return fmt.Sprintf(`
+resource "panos_snmp_agent" "test" {
+    location = %q
+    contact = "admin@example.com"
+    version = "v3"
+    v3_view {
+        name = "all"
+        oid {
+            name = "mib2"
+            oid = "1.3.6.1"
+        }
+    }
+    v3_user {
+        name = "monitor"
+        view = "all"
+        auth_password = "authpassword"
+        priv_password = "privpassword"
+    }
+}
+`, location)
+}
@@ -0,0 +1,285 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/version"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Resource.
+func resourceSoftwareVersion() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdateSoftwareVersion,
+		Read:   readSoftwareVersion,
+		Update: createUpdateSoftwareVersion,
+		Delete: deleteSoftwareVersion,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The PAN-OS version to run",
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^\d+\.\d+\.\d+(-\S+)?$`),
+					"must be a PAN-OS version such as 10.1.6 or 10.1.6-h3",
+				),
+			},
+			"install": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Install the software after it is downloaded",
+			},
+			"reboot": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Reboot after the install so the new version is running",
+			},
+			"current_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PAN-OS version currently running",
+			},
+		},
+	}
+}
+
+func createUpdateSoftwareVersion(d *schema.ResourceData, meta interface{}) error {
+	var timeout time.Duration
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	} else {
+		timeout = d.Timeout(schema.TimeoutUpdate)
+	}
+	deadline := time.Now().Add(timeout)
+
+	c := rawClient(meta)
+	target := d.Get("version").(string)
+	install := d.Get("install").(bool)
+	reboot := d.Get("reboot").(bool)
+
+	tv, err := version.New(target)
+	if err != nil {
+		return err
+	}
+
+	current, err := getSystemVersion(c)
+	if err != nil {
+		return err
+	}
+
+	if current.String() != target {
+		list, err := checkSoftwareVersions(c)
+		if err != nil {
+			return err
+		}
+
+		// Each feature release between the current one and the target's has
+		// to be installed and running before moving on to the next.
+		path := softwareUpgradePath(list, current, tv)
+		if len(path) != 0 && (!install || !reboot) {
+			return fmt.Errorf("Going from %s to %s requires that %s be installed first, so install and reboot must be enabled", current, target, strings.Join(path, ", "))
+		}
+		for _, v := range path {
+			if current, err = installSoftwareVersion(meta, list, v, true, deadline); err != nil {
+				return err
+			}
+		}
+
+		// A new feature release requires that its base image is downloaded
+		// before the target image.
+		if tv.Major != current.Major || tv.Minor != current.Minor {
+			base := fmt.Sprintf("%d.%d.0", tv.Major, tv.Minor)
+			if base != target {
+				if err = downloadSoftwareVersion(c, list, base, deadline); err != nil {
+					return err
+				}
+			}
+		}
+
+		if install {
+			if _, err = installSoftwareVersion(meta, list, target, reboot, deadline); err != nil {
+				return err
+			}
+		} else if err = downloadSoftwareVersion(c, list, target, deadline); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(systemOpsId(c))
+	return readSoftwareVersion(d, meta)
+}
+
+func readSoftwareVersion(d *schema.ResourceData, meta interface{}) error {
+	c := rawClient(meta)
+
+	current, err := getSystemVersion(c)
+	if err != nil {
+		return err
+	}
+	if c.Target == "" {
+		c.Version = current
+	}
+
+	d.Set("current_version", current.String())
+
+	// Report drift only if the target version should be running by now.
+	target := d.Get("version").(string)
+	if current.String() != target && d.Get("install").(bool) && d.Get("reboot").(bool) {
+		d.Set("version", current.String())
+	}
+
+	return nil
+}
+
+func deleteSoftwareVersion(d *schema.ResourceData, meta interface{}) error {
+	// Software can't be uninstalled, so just remove it from the state.
+	d.SetId("")
+	return nil
+}
+
+// checkSoftwareVersions refreshes and returns the software versions that are
+// available to the device.
+func checkSoftwareVersions(c *pango.Client) ([]softwareVersionEntry, error) {
+	var ans softwareCheckAns
+
+	c.LogOp("(op) request system software check")
+	if _, err := c.Op(softwareCheckReq{}, "", nil, &ans); err != nil {
+		return nil, err
+	}
+
+	return ans.Entries, nil
+}
+
+// softwareUpgradePath returns the base images of the feature releases between
+// the current version and the target, in the order that they must be
+// installed.
+//
+// The feature releases are taken from the versions available to the device,
+// as they are not always sequential (such as 11.2 to 12.1).
+func softwareUpgradePath(list []softwareVersionEntry, current, target version.Number) []string {
+	type release struct{ major, minor int }
+
+	from := release{current.Major, current.Minor}
+	to := release{target.Major, target.Minor}
+	less := func(a, b release) bool {
+		return a.major < b.major || (a.major == b.major && a.minor < b.minor)
+	}
+
+	seen := make(map[release]bool)
+	var releases []release
+	for _, x := range list {
+		v, err := version.New(x.Version)
+		if err != nil {
+			continue
+		}
+		r := release{v.Major, v.Minor}
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		if (less(from, r) && less(r, to)) || (less(to, r) && less(r, from)) {
+			releases = append(releases, r)
+		}
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		if less(from, to) {
+			return less(releases[i], releases[j])
+		}
+		return less(releases[j], releases[i])
+	})
+
+	ans := make([]string, 0, len(releases))
+	for _, r := range releases {
+		ans = append(ans, fmt.Sprintf("%d.%d.0", r.major, r.minor))
+	}
+
+	return ans
+}
+
+// installSoftwareVersion downloads and installs the given version, then
+// optionally restarts the device.  The version running afterwards is
+// returned.
+func installSoftwareVersion(meta interface{}, list []softwareVersionEntry, v string, reboot bool, deadline time.Time) (version.Number, error) {
+	c := rawClient(meta)
+
+	if err := downloadSoftwareVersion(c, list, v, deadline); err != nil {
+		return version.Number{}, err
+	}
+
+	req := softwareInstallReq{Version: v}
+	if err := runOpJob(c, req, fmt.Sprintf("request system software install version %s", v), deadline); err != nil {
+		return version.Number{}, err
+	}
+
+	if !reboot {
+		return getSystemVersion(c)
+	}
+
+	return restartSystem(meta, deadline)
+}
+
+// downloadSoftwareVersion downloads the given version, if it isn't already.
+func downloadSoftwareVersion(c *pango.Client, list []softwareVersionEntry, v string, deadline time.Time) error {
+	var found bool
+	for _, x := range list {
+		if x.Version != v {
+			continue
+		}
+		found = true
+		if x.Downloaded == "yes" {
+			return nil
+		}
+		break
+	}
+
+	if !found {
+		return fmt.Errorf("Software version %q is not available to this device", v)
+	}
+
+	req := softwareDownloadReq{Version: v}
+	return runOpJob(c, req, fmt.Sprintf("request system software download version %s", v), deadline)
+}
+
+// Op structs.
+type softwareCheckReq struct {
+	XMLName xml.Name `xml:"request"`
+	Cmd     string   `xml:"system>software>check"`
+}
+
+type softwareCheckAns struct {
+	Entries []softwareVersionEntry `xml:"result>sw-updates>versions>entry"`
+}
+
+type softwareVersionEntry struct {
+	Version    string `xml:"version"`
+	Downloaded string `xml:"downloaded"`
+	Current    string `xml:"current"`
+	Latest     string `xml:"latest"`
+}
+
+type softwareDownloadReq struct {
+	XMLName xml.Name `xml:"request"`
+	Version string   `xml:"system>software>download>version"`
+}
+
+type softwareInstallReq struct {
+	XMLName xml.Name `xml:"request"`
+	Version string   `xml:"system>software>install>version"`
+}
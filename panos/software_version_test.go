@@ -0,0 +1,95 @@
+package panos
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/fpluchorg/pango/version"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccPanosSoftwareVersion(t *testing.T) {
+	// This acctest changes the software running on the device, so it only
+	// runs if the version to go to is given.
+	sv := os.Getenv("PANOS_SOFTWARE_VERSION")
+
+	if sv == "" {
+		t.Skip("Env PANOS_SOFTWARE_VERSION must be specified to run this acc test")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSoftwareVersionConfig(sv),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_software_version.test", "version", sv),
+					resource.TestCheckResourceAttr("panos_software_version.test", "current_version", sv),
+				),
+			},
+		},
+	})
+}
+
+func testAccSoftwareVersionConfig(sv string) string {
+	return fmt.Sprintf(`
+resource "panos_software_version" "test" {
+    version = %q
+
+    timeouts {
+        create = "180m"
+    }
+}
+`, sv)
+}
+
+func TestSoftwareUpgradePath(t *testing.T) {
+	list := []softwareVersionEntry{
+		{Version: "12.1.2"},
+		{Version: "11.2.4-h1"},
+		{Version: "11.2.0"},
+		{Version: "11.1.3"},
+		{Version: "11.1.0"},
+		{Version: "10.2.9"},
+		{Version: "10.2.0"},
+		{Version: "10.1.11"},
+		{Version: "10.1.0"},
+		{Version: "10.0.0"},
+		{Version: "9.1.16"},
+		{Version: "9.1.0"},
+	}
+
+	testCases := []struct {
+		current string
+		target  string
+		path    []string
+	}{
+		{"10.1.6", "10.1.11", []string{}},
+		{"10.1.6", "10.2.9", []string{}},
+		{"9.1.16", "10.2.9", []string{"10.0.0", "10.1.0"}},
+		{"10.1.6", "12.1.2", []string{"10.2.0", "11.1.0", "11.2.0"}},
+		{"10.2.9", "9.1.16", []string{"10.1.0", "10.0.0"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s to %s", tc.current, tc.target), func(t *testing.T) {
+			cv, err := version.New(tc.current)
+			if err != nil {
+				t.Fatalf("Bad current version: %s", err)
+			}
+			tv, err := version.New(tc.target)
+			if err != nil {
+				t.Fatalf("Bad target version: %s", err)
+			}
+
+			path := softwareUpgradePath(list, cv, tv)
+			if !reflect.DeepEqual(path, tc.path) {
+				t.Errorf("Expected %#v, got %#v", tc.path, path)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/util"
+	"github.com/fpluchorg/pango/version"
+)
+
+// How often the device is polled while waiting on jobs and restarts.
+const systemOpsPollInterval = 10 * time.Second
+
+// runOpJob runs an op command that starts a job, then waits for the job to
+// finish or for the deadline to pass.
+func runOpJob(c *pango.Client, cmd interface{}, desc string, deadline time.Time) error {
+	var ans opJobAns
+
+	c.LogOp("(op) %s", desc)
+	if _, err := c.Op(cmd, "", nil, &ans); err != nil {
+		return err
+	}
+	if ans.Job == 0 {
+		return fmt.Errorf("No job ID returned for %s", desc)
+	}
+
+	return waitForOpJob(c, ans.Job, desc, deadline)
+}
+
+// waitForOpJob is like pango's WaitForJob, but gives up at the deadline.
+func waitForOpJob(c *pango.Client, id uint, desc string, deadline time.Time) error {
+	req := showJobReq{Id: id}
+
+	c.LogOp("(op) waiting for job %d: %s", id, desc)
+	for {
+		var ans util.BasicJob
+		if _, err := c.Op(req, "", nil, &ans); err != nil {
+			return err
+		}
+
+		if ans.Progress == 100 || ans.Status == "FIN" {
+			if ans.Result == "FAIL" {
+				if len(ans.Details.Lines) > 0 {
+					return fmt.Errorf("%s failed: %s", desc, ans.Details.String())
+				}
+				return fmt.Errorf("%s failed: job %d did not complete successfully", desc, id)
+			}
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timed out waiting for job %d: %s", id, desc)
+		}
+		time.Sleep(systemOpsPollInterval)
+	}
+}
+
+// restartSystem restarts the device then waits for it to be ready again,
+// returning the version it is running.
+func restartSystem(meta interface{}, deadline time.Time) (version.Number, error) {
+	c := rawClient(meta)
+
+	c.LogOp("(op) request restart system")
+	if _, err := c.Op(restartSystemReq{}, "", nil, nil); err != nil {
+		return version.Number{}, err
+	}
+
+	return waitForSystemReady(meta, deadline)
+}
+
+// waitForSystemReady waits for a device that was told to restart to go down,
+// come back up, and, for firewalls, finish its auto-commit.  The version the
+// device is running is returned.
+//
+// If the provider is a Panorama with a "target", then it is the firewall that
+// restarts, so it's the firewall's auto-commit that is waited on, and the
+// version of the connection (which is Panorama's) is left alone.  Otherwise
+// the version of the connection is refreshed, as it may have changed.
+func waitForSystemReady(meta interface{}, deadline time.Time) (version.Number, error) {
+	c := rawClient(meta)
+
+	w := restartWaiter{
+		interval: systemOpsPollInterval,
+		version:  func() (version.Number, error) { return getSystemVersion(c) },
+	}
+	if waitsForAutoCommit(meta) {
+		w.autoCommit = func() (bool, error) { return autoCommitFinished(c) }
+	}
+
+	v, err := w.wait(deadline)
+	if err != nil {
+		return v, err
+	}
+	if c.Target == "" {
+		c.Version = v
+	}
+
+	return v, nil
+}
+
+// waitsForAutoCommit returns if the device that restarts is a firewall, which
+// runs an auto-commit once it is back up.
+func waitsForAutoCommit(meta interface{}) bool {
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		return true
+	case *pango.Panorama:
+		return con.Target != ""
+	}
+
+	return false
+}
+
+// restartWaiter polls a restarting device until it is ready again.
+type restartWaiter struct {
+	interval time.Duration
+
+	// version returns the running version, and fails while the device is
+	// down.
+	version func() (version.Number, error)
+
+	// autoCommit returns if the auto-commit has finished.  If this is nil,
+	// then there is no auto-commit to wait on.
+	autoCommit func() (bool, error)
+}
+
+func (o restartWaiter) wait(deadline time.Time) (version.Number, error) {
+	var err error
+	var v version.Number
+
+	// Wait for the management plane to go down.
+	for {
+		if _, err = o.version(); err != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return v, fmt.Errorf("Timed out waiting for the device to restart")
+		}
+		time.Sleep(o.interval)
+	}
+
+	// Wait for the management plane to come back up.
+	for {
+		time.Sleep(o.interval)
+		if v, err = o.version(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return v, fmt.Errorf("Timed out waiting for the device to come back up: %s", err)
+		}
+	}
+
+	if o.autoCommit == nil {
+		return v, nil
+	}
+
+	// Wait for the auto-commit to finish.
+	for {
+		done, err := o.autoCommit()
+		if err != nil {
+			return v, err
+		} else if done {
+			return v, nil
+		}
+
+		if time.Now().After(deadline) {
+			return v, fmt.Errorf("Timed out waiting for the auto-commit to finish")
+		}
+		time.Sleep(o.interval)
+	}
+}
+
+// autoCommitFinished returns if the firewall's auto-commit has finished, and
+// an error if the auto-commit failed.
+//
+// As this is polled while the firewall is still starting up, failing to get
+// the jobs is not an error.
+func autoCommitFinished(c *pango.Client) (bool, error) {
+	var ans showJobsAns
+
+	c.LogOp("(op) show jobs all")
+	if _, err := c.Op(showJobsReq{}, "", nil, &ans); err != nil {
+		return false, nil
+	}
+
+	for _, job := range ans.Jobs {
+		if job.Type != "AutoCom" || job.Status != "FIN" {
+			continue
+		}
+		if job.Result == "FAIL" {
+			return false, fmt.Errorf("Auto-commit job %d failed", job.Id)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// systemOpsId returns the ID for resources that act on the device as a
+// whole, which is the device's hostname and, if any, the Panorama target.
+func systemOpsId(c *pango.Client) string {
+	if c.Target == "" {
+		return c.Hostname
+	}
+
+	return strings.Join([]string{c.Hostname, c.Target}, IdSeparator)
+}
+
+// getSystemVersion returns the running PAN-OS version.
+func getSystemVersion(c *pango.Client) (version.Number, error) {
+	var ans systemInfoAns
+
+	c.LogOp("(op) show system info")
+	if _, err := c.Op(systemInfoReq{}, "", nil, &ans); err != nil {
+		return version.Number{}, err
+	}
+
+	return version.New(ans.Version)
+}
+
+// Op structs.
+type opJobAns struct {
+	Job uint `xml:"result>job"`
+}
+
+type showJobReq struct {
+	XMLName xml.Name `xml:"show"`
+	Id      uint     `xml:"jobs>id"`
+}
+
+type showJobsReq struct {
+	XMLName xml.Name `xml:"show"`
+	Cmd     string   `xml:"jobs>all"`
+}
+
+type showJobsAns struct {
+	Jobs []showJobsEntry `xml:"result>job"`
+}
+
+type showJobsEntry struct {
+	Id     uint   `xml:"id"`
+	Type   string `xml:"type"`
+	Status string `xml:"status"`
+	Result string `xml:"result"`
+}
+
+type restartSystemReq struct {
+	XMLName xml.Name `xml:"request"`
+	Cmd     string   `xml:"restart>system"`
+}
+
+type systemInfoReq struct {
+	XMLName xml.Name `xml:"show"`
+	Cmd     string   `xml:"system>info"`
+}
+
+type systemInfoAns struct {
+	Version string `xml:"result>system>sw-version"`
+}
@@ -0,0 +1,194 @@
+package panos
+
+import (
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Resource.
+func resourceUserGroupTag() *schema.Resource {
+	return &schema.Resource{
+		Create: createUserGroupTag,
+		Read:   readUserGroupTag,
+		Delete: deleteUserGroupTag,
+
+		Schema: map[string]*schema.Schema{
+			"vsys": vsysSchema("vsys1"),
+			"group": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "User group whose members are tagged",
+				ForceNew:    true,
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "Tags",
+				MinItems:    1,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Seconds until the tags expire",
+				ValidateFunc: validation.IntBetween(0, 2592000),
+			},
+			"persistent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Keep the tags across reboots",
+			},
+			"users": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The group members that are tagged",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"remaining_lifetime": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds until the tags expire",
+			},
+		},
+	}
+}
+
+func createUserGroupTag(d *schema.ResourceData, meta interface{}) error {
+	fw, err := firewall(meta, "")
+	if err != nil {
+		return err
+	}
+	vsys := d.Get("vsys").(string)
+	group := d.Get("group").(string)
+	tagList := d.Get("tags").(*schema.Set).List()
+
+	reg := userGroupTagRegistration{
+		Group:      group,
+		Tags:       make([]string, 0, len(tagList)),
+		Timeout:    d.Get("timeout").(int),
+		Persistent: d.Get("persistent").(bool),
+	}
+	for i := range tagList {
+		reg.Tags = append(reg.Tags, tagList[i].(string))
+	}
+
+	if _, err = registerUserGroupTags(fw, vsys, reg); err != nil {
+		return err
+	}
+
+	d.SetId(buildUserGroupTagId(vsys, group, tagList))
+	return readUserGroupTag(d, meta)
+}
+
+func readUserGroupTag(d *schema.ResourceData, meta interface{}) error {
+	fw, err := firewall(meta, "")
+	if err != nil {
+		return err
+	}
+	vsys, group, tags := parseUserGroupTagId(d.Id())
+
+	members, err := fw.UserId.GetGroupMembers(group, vsys)
+	if err != nil {
+		d.SetId("")
+		return nil
+	}
+
+	cur, err := getRegisteredUserTags(&fw.Client, "", vsys)
+	if err != nil {
+		d.SetId("")
+		return nil
+	}
+
+	// A tag is only reported if every current member of the group has it,
+	// so that new members of the group show up as drift.
+	var persistent string
+	var lifetime int
+	overlap := make([]string, 0, len(tags))
+	if len(members) > 0 {
+		overlap = append(overlap, tags...)
+	}
+	for _, user := range members {
+		info := cur[user]
+		list := make([]string, 0, len(overlap))
+		for _, tag := range overlap {
+			if info.Has(tag) {
+				list = append(list, tag)
+			}
+		}
+		overlap = list
+		if persistent == "" {
+			persistent = info.Persistent
+		}
+		if v := info.RemainingLifetime(tags); v > 0 && (lifetime == 0 || v < lifetime) {
+			lifetime = v
+		}
+	}
+
+	d.Set("vsys", vsys)
+	d.Set("group", group)
+	if len(overlap) != 0 {
+		if err := d.Set("tags", listAsSet(overlap)); err != nil {
+			log.Printf("[WARN] Error setting 'tags' for %q: %s", d.Id(), err)
+		}
+	} else {
+		d.Set("tags", nil)
+	}
+	if persistent != "" {
+		d.Set("persistent", persistent == "1")
+	}
+	if err := d.Set("users", members); err != nil {
+		log.Printf("[WARN] Error setting 'users' for %q: %s", d.Id(), err)
+	}
+	d.Set("remaining_lifetime", lifetime)
+
+	return nil
+}
+
+func deleteUserGroupTag(d *schema.ResourceData, meta interface{}) error {
+	fw, err := firewall(meta, "")
+	if err != nil {
+		return err
+	}
+	vsys, group, tags := parseUserGroupTagId(d.Id())
+
+	// Users that have left the group since the last refresh are still
+	// untagged.
+	users := asStringList(d.Get("users").([]interface{}))
+	members, err := fw.UserId.GetGroupMembers(group, vsys)
+	if err != nil {
+		return err
+	}
+	users = append(users, members...)
+
+	if err = unregisterUserGroupTags(fw, vsys, users, tags); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Id functions.
+func buildUserGroupTagId(a, b string, c []interface{}) string {
+	list := make([]string, len(c))
+	for i := range c {
+		list[i] = c[i].(string)
+	}
+	return strings.Join([]string{a, b, base64Encode(list)}, IdSeparator)
+}
+
+func parseUserGroupTagId(v string) (string, string, []string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], base64Decode(t[2])
+}
@@ -0,0 +1,94 @@
+package panos
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosUserGroupTag(t *testing.T) {
+	// This acctest requires a user group with members, as group membership
+	// comes from group mapping and cannot be created by Terraform.
+	group := os.Getenv("PANOS_USER_GROUP")
+
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	} else if group == "" {
+		t.Skip("Env PANOS_USER_GROUP must be specified to run this acc test")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosUserGroupTagDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserGroupTagConfig(group, "tfgtag1", 0, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_user_group_tag.test", "tags.#", "1"),
+					resource.TestCheckResourceAttr("panos_user_group_tag.test", "persistent", "true"),
+					resource.TestCheckResourceAttrSet("panos_user_group_tag.test", "users.0"),
+					resource.TestCheckResourceAttr("panos_user_group_tag.test", "remaining_lifetime", "0"),
+				),
+			},
+			{
+				Config: testAccUserGroupTagConfig(group, "tfgtag2", 600, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_user_group_tag.test", "tags.#", "1"),
+					resource.TestCheckResourceAttr("panos_user_group_tag.test", "persistent", "false"),
+					resource.TestCheckResourceAttrSet("panos_user_group_tag.test", "remaining_lifetime"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosUserGroupTagDestroy(s *terraform.State) error {
+	fw, ok := testAccProvider.Meta().(*pango.Firewall)
+	if !ok {
+		return nil
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_user_group_tag" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			vsys, group, tags := parseUserGroupTagId(rs.Primary.ID)
+			members, err := fw.UserId.GetGroupMembers(group, vsys)
+			if err != nil {
+				return err
+			}
+			cur, err := getRegisteredUserTags(&fw.Client, "", vsys)
+			if err != nil {
+				return err
+			}
+			for _, user := range members {
+				for _, tag := range tags {
+					if cur[user].Has(tag) {
+						return fmt.Errorf("User %q still has tag %q", user, tag)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccUserGroupTagConfig(group, tag string, timeout int, persistent bool) string {
+	return fmt.Sprintf(`
+resource "panos_user_group_tag" "test" {
+    group = %q
+    tags = [%q]
+    timeout = %d
+    persistent = %t
+}
+`, group, tag, timeout, persistent)
+}
@@ -4,9 +4,8 @@ import (
 	"log"
 	"strings"
 
-	"github.com/fpluchorg/pango/userid"
-
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // Data source.
@@ -110,6 +109,25 @@ func resourceUserTag() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Seconds until the tags expire",
+				ValidateFunc: validation.IntBetween(0, 2592000),
+			},
+			"persistent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Keep the tags across reboots",
+			},
+			"remaining_lifetime": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Seconds until the tags expire",
+			},
 		},
 	}
 }
@@ -123,43 +141,20 @@ func createUserTag(d *schema.ResourceData, meta interface{}) error {
 	user := d.Get("user").(string)
 	tagList := d.Get("tags").(*schema.Set).List()
 
-	cur, err := fw.UserId.GetUserTags(user, vsys)
-	if err != nil {
-		return err
+	// All tags are registered, even if already present, so that the timeout
+	// and persistence are applied to them.
+	reg := userTagRegistration{
+		User:       user,
+		Tags:       make([]string, 0, len(tagList)),
+		Timeout:    d.Get("timeout").(int),
+		Persistent: d.Get("persistent").(bool),
 	}
-	curTags := cur[user]
-
-	missing := make([]userid.UserTag, 0, len(tagList))
 	for i := range tagList {
-		tag := tagList[i].(string)
-		var found bool
-		for _, x := range curTags {
-			if x == tag {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			missing = append(missing, userid.UserTag{
-				Tag: tag,
-			})
-		}
+		reg.Tags = append(reg.Tags, tagList[i].(string))
 	}
 
-	if len(missing) > 0 {
-		msg := &userid.Message{
-			TagUsers: []userid.TagUser{
-				userid.TagUser{
-					User: user,
-					Tags: missing,
-				},
-			},
-		}
-
-		if err = fw.UserId.Run(msg, vsys); err != nil {
-			return err
-		}
+	if err = registerUserTags(&fw.Client, vsys, []userTagRegistration{reg}); err != nil {
+		return err
 	}
 
 	d.SetId(buildUserTagId(vsys, user, tagList))
@@ -173,20 +168,17 @@ func readUserTag(d *schema.ResourceData, meta interface{}) error {
 	}
 	vsys, user, tags := parseUserTagId(d.Id())
 
-	cur, err := fw.UserId.GetUserTags(user, vsys)
+	cur, err := getRegisteredUserTags(&fw.Client, user, vsys)
 	if err != nil || len(cur) == 0 {
 		d.SetId("")
 		return nil
 	}
-	curTags := cur[user]
+	info := cur[user]
 
-	overlap := make([]string, 0, len(curTags))
-	for _, curTag := range curTags {
-		for _, wantTag := range tags {
-			if curTag == wantTag {
-				overlap = append(overlap, wantTag)
-				break
-			}
+	overlap := make([]string, 0, len(info.Tags))
+	for _, wantTag := range tags {
+		if info.Has(wantTag) {
+			overlap = append(overlap, wantTag)
 		}
 	}
 
@@ -199,6 +191,10 @@ func readUserTag(d *schema.ResourceData, meta interface{}) error {
 	} else {
 		d.Set("tags", nil)
 	}
+	if info.Persistent != "" {
+		d.Set("persistent", info.Persistent == "1")
+	}
+	d.Set("remaining_lifetime", info.RemainingLifetime(overlap))
 
 	return nil
 }
@@ -210,41 +206,28 @@ func deleteUserTag(d *schema.ResourceData, meta interface{}) error {
 	}
 	vsys, user, tags := parseUserTagId(d.Id())
 
-	cur, err := fw.UserId.GetUserTags(user, vsys)
+	cur, err := getRegisteredUserTags(&fw.Client, user, vsys)
 	if err != nil || len(cur) == 0 {
 		d.SetId("")
 		return nil
 	}
-	curTags := cur[user]
+	info := cur[user]
 
-	overlap := make([]string, 0, len(curTags))
-	for _, curTag := range curTags {
-		for _, wantTag := range tags {
-			if curTag == wantTag {
-				overlap = append(overlap, wantTag)
-				break
-			}
+	reg := userTagRegistration{User: user}
+	for _, wantTag := range tags {
+		if info.Has(wantTag) {
+			reg.Tags = append(reg.Tags, wantTag)
 		}
 	}
 
-	if len(overlap) == 0 {
+	if len(reg.Tags) == 0 {
 		d.SetId("")
 		return nil
 	}
 
-	msg := &userid.Message{
-		UntagUsers: []userid.UntagUser{
-			userid.UntagUser{
-				User: user,
-				Tags: overlap,
-			},
-		},
-	}
-
 	// The UserId subsystem doesn't return ObjectNotFound, so we don't need
 	// to check for that at this point.
-	err = fw.UserId.Run(msg, vsys)
-	if err != nil {
+	if err = unregisterUserTags(&fw.Client, vsys, []userTagRegistration{reg}); err != nil {
 		return err
 	}
 
@@ -78,6 +78,15 @@ func TestAccPanosUserTag(t *testing.T) {
 					testAccCheckPanosUserTagAttributes(&o, name, "tag3", "tag2"),
 				),
 			},
+			{
+				Config: testAccUserTagLifetimeConfig(name, "tag3", "tag2", 600, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosUserTagExists("panos_user_tag.test", &o),
+					testAccCheckPanosUserTagAttributes(&o, name, "tag3", "tag2"),
+					resource.TestCheckResourceAttr("panos_user_tag.test", "persistent", "false"),
+					resource.TestCheckResourceAttrSet("panos_user_tag.test", "remaining_lifetime"),
+				),
+			},
 		},
 	})
 }
@@ -184,3 +193,19 @@ resource "panos_user_tag" "test" {
 }
 `, name, tag1, tag2)
 }
+
+func testAccUserTagLifetimeConfig(name, tag1, tag2 string, timeout int, persistent bool) string {
+	return fmt.Sprintf(`
+resource "panos_userid_login" "x" {
+    ip = "10.20.59.77"
+    user = %q
+}
+
+resource "panos_user_tag" "test" {
+    user = panos_userid_login.x.user
+    tags = [%q, %q]
+    timeout = %d
+    persistent = %t
+}
+`, name, tag1, tag2, timeout, persistent)
+}
@@ -0,0 +1,294 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceUseridAgent() *schema.Resource {
+	return &schema.Resource{
+		Create: createUseridAgent,
+		Read:   readUseridAgent,
+		Update: updateUseridAgent,
+		Delete: deleteUseridAgent,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("vsys1"),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The User-ID agent name",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The agent hostname or IP address",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     5007,
+				Description: "The agent port",
+			},
+			"ntlm_auth": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Use this agent for NTLM authentication",
+			},
+			"ldap_proxy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Use this agent as an LDAP proxy",
+			},
+			"collector_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The collector name, when the agent is another firewall",
+			},
+			"collector_secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The collector pre-shared key, when the agent is another firewall",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Disable this agent",
+			},
+			"enable_hip_collection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Collect HIP data from this agent",
+			},
+			"collector_secret_raw": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Collector secret, raw",
+			},
+			"collector_secret_enc": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Collector secret, encrypted",
+			},
+		},
+	}
+}
+
+func createUseridAgent(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadUseridAgent(d)
+
+	path, err := useridAgentXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("User-ID agent %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildUseridAgentId(tmpl, ts, vsys, o.Name))
+	if err = saveUseridAgentSecret(d, meta, path, o); err != nil {
+		return err
+	}
+
+	return readUseridAgent(d, meta)
+}
+
+func readUseridAgent(d *schema.ResourceData, meta interface{}) error {
+	var ans useridAgentAns
+
+	tmpl, ts, vsys, name := parseUseridAgentId(d.Id())
+
+	path, err := useridAgentXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveUseridAgent(d, *ans.Entry)
+
+	return nil
+}
+
+func updateUseridAgent(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseUseridAgentId(d.Id())
+	o := loadUseridAgent(d)
+
+	path, err := useridAgentXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	if err = saveUseridAgentSecret(d, meta, path, o); err != nil {
+		return err
+	}
+
+	return readUseridAgent(d, meta)
+}
+
+func deleteUseridAgent(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseUseridAgentId(d.Id())
+
+	path, err := useridAgentXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadUseridAgent(d *schema.ResourceData) useridAgentEntry {
+	return useridAgentEntry{
+		Name: d.Get("name").(string),
+		HostPort: useridAgentHostPort{
+			Host:          d.Get("host").(string),
+			Port:          d.Get("port").(int),
+			NtlmAuth:      util.YesNo(d.Get("ntlm_auth").(bool)),
+			LdapProxy:     util.YesNo(d.Get("ldap_proxy").(bool)),
+			CollectorName: d.Get("collector_name").(string),
+			Secret:        d.Get("collector_secret").(string),
+		},
+		Disabled:            util.YesNo(d.Get("disabled").(bool)),
+		EnableHipCollection: util.YesNo(d.Get("enable_hip_collection").(bool)),
+	}
+}
+
+func saveUseridAgent(d *schema.ResourceData, o useridAgentEntry) {
+	d.Set("name", o.Name)
+	d.Set("host", o.HostPort.Host)
+	d.Set("port", o.HostPort.Port)
+	d.Set("ntlm_auth", util.AsBool(o.HostPort.NtlmAuth))
+	d.Set("ldap_proxy", util.AsBool(o.HostPort.LdapProxy))
+	d.Set("collector_name", o.HostPort.CollectorName)
+	d.Set("disabled", util.AsBool(o.Disabled))
+	d.Set("enable_hip_collection", util.AsBool(o.EnableHipCollection))
+
+	var secret string
+	if d.Get("collector_secret_enc").(string) == o.HostPort.Secret {
+		secret = d.Get("collector_secret_raw").(string)
+	} else {
+		secret = "(mismatch)"
+	}
+	d.Set("collector_secret", secret)
+}
+
+// saveUseridAgentSecret saves the raw and encrypted collector secret so that
+// drift can be detected.
+func saveUseridAgentSecret(d *schema.ResourceData, meta interface{}, path []string, o useridAgentEntry) error {
+	var ans useridAgentAns
+
+	c := rawClient(meta)
+	if _, err := c.Get(append(path, util.AsEntryXpath([]string{o.Name})), nil, &ans); err != nil {
+		return err
+	}
+
+	var enc string
+	if ans.Entry != nil {
+		enc = ans.Entry.HostPort.Secret
+	}
+
+	d.Set("collector_secret_raw", o.HostPort.Secret)
+	d.Set("collector_secret_enc", enc)
+
+	return nil
+}
+
+// Id functions.
+func buildUseridAgentId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseUseridAgentId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func useridAgentXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "user-id-agent"), nil
+}
+
+// Config structs.
+type useridAgentEntry struct {
+	XMLName             xml.Name            `xml:"entry"`
+	Name                string              `xml:"name,attr"`
+	HostPort            useridAgentHostPort `xml:"host-port"`
+	Disabled            string              `xml:"disabled"`
+	EnableHipCollection string              `xml:"enable-hip-collection"`
+}
+
+type useridAgentHostPort struct {
+	Host          string `xml:"host"`
+	Port          int    `xml:"port,omitempty"`
+	NtlmAuth      string `xml:"ntlm-auth"`
+	LdapProxy     string `xml:"ldap-proxy"`
+	CollectorName string `xml:"collectorname,omitempty"`
+	Secret        string `xml:"secret,omitempty"`
+}
+
+type useridAgentAns struct {
+	Entry *useridAgentEntry `xml:"result>entry"`
+}
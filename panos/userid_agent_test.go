@@ -0,0 +1,78 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosUseridAgent(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosUseridAgentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUseridAgentConfig(name, "10.1.1.1", 5007, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_userid_agent.test", "host", "10.1.1.1"),
+					resource.TestCheckResourceAttr("panos_userid_agent.test", "port", "5007"),
+					resource.TestCheckResourceAttr("panos_userid_agent.test", "disabled", "false"),
+				),
+			},
+			{
+				Config: testAccUseridAgentConfig(name, "10.1.1.2", 5008, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_userid_agent.test", "host", "10.1.1.2"),
+					resource.TestCheckResourceAttr("panos_userid_agent.test", "port", "5008"),
+					resource.TestCheckResourceAttr("panos_userid_agent.test", "disabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosUseridAgentDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_userid_agent" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, vsys, name := parseUseridAgentId(rs.Primary.ID)
+			path, err := useridAgentXpath(testAccProvider.Meta(), tmpl, ts, vsys)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccUseridAgentConfig(name, host string, port int, disabled bool) string {
+	return fmt.Sprintf(`
+resource "panos_userid_agent" "test" {
+    name = %q
+    host = %q
+    port = %d
+    disabled = %t
+}
+`, name, host, port, disabled)
+}
@@ -0,0 +1,376 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceUseridGroupMapping() *schema.Resource {
+	return &schema.Resource{
+		Create: createUseridGroupMapping,
+		Read:   readUseridGroupMapping,
+		Update: updateUseridGroupMapping,
+		Delete: deleteUseridGroupMapping,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("vsys1"),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The group mapping name",
+			},
+			"server_profile": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The LDAP server profile",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Enable this group mapping",
+			},
+			"update_interval": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Seconds between group updates",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The user domain",
+			},
+			"group_object": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP object class for groups",
+			},
+			"group_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for the group name",
+			},
+			"group_member": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for group members",
+			},
+			"group_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "LDAP search filter for groups",
+			},
+			"user_object": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP object class for users",
+			},
+			"user_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for the user name",
+			},
+			"user_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "LDAP search filter for users",
+			},
+			"group_include_list": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Only map these groups",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"custom_group": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Custom groups defined by LDAP filters",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"ldap_filter": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"primary_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for the primary username",
+			},
+			"alternate_username_1": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for the first alternate username",
+			},
+			"alternate_username_2": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for the second alternate username",
+			},
+			"alternate_username_3": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The LDAP attribute for the third alternate username",
+			},
+		},
+	}
+}
+
+func createUseridGroupMapping(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadUseridGroupMapping(d)
+
+	path, err := useridGroupMappingXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Group mapping %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildUseridGroupMappingId(tmpl, ts, vsys, o.Name))
+	return readUseridGroupMapping(d, meta)
+}
+
+func readUseridGroupMapping(d *schema.ResourceData, meta interface{}) error {
+	var ans useridGroupMappingAns
+
+	tmpl, ts, vsys, name := parseUseridGroupMappingId(d.Id())
+
+	path, err := useridGroupMappingXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveUseridGroupMapping(d, *ans.Entry)
+
+	return nil
+}
+
+func updateUseridGroupMapping(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseUseridGroupMappingId(d.Id())
+	o := loadUseridGroupMapping(d)
+
+	path, err := useridGroupMappingXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readUseridGroupMapping(d, meta)
+}
+
+func deleteUseridGroupMapping(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseUseridGroupMappingId(d.Id())
+
+	path, err := useridGroupMappingXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadUseridGroupMapping(d *schema.ResourceData) useridGroupMappingEntry {
+	o := useridGroupMappingEntry{
+		Name:               d.Get("name").(string),
+		ServerProfile:      d.Get("server_profile").(string),
+		Enabled:            util.YesNo(d.Get("enabled").(bool)),
+		UpdateInterval:     d.Get("update_interval").(int),
+		Domain:             d.Get("domain").(string),
+		GroupObject:        d.Get("group_object").(string),
+		GroupName:          d.Get("group_name").(string),
+		GroupMember:        d.Get("group_member").(string),
+		GroupFilter:        d.Get("group_filter").(string),
+		UserObject:         d.Get("user_object").(string),
+		UserName:           d.Get("user_name").(string),
+		UserFilter:         d.Get("user_filter").(string),
+		PrimaryUsername:    d.Get("primary_username").(string),
+		AlternateUsername1: d.Get("alternate_username_1").(string),
+		AlternateUsername2: d.Get("alternate_username_2").(string),
+		AlternateUsername3: d.Get("alternate_username_3").(string),
+	}
+
+	if list := asStringList(d.Get("group_include_list").([]interface{})); len(list) > 0 {
+		o.GroupIncludeList = &util.MemberType{}
+		for _, x := range list {
+			o.GroupIncludeList.Members = append(o.GroupIncludeList.Members, util.Member{Value: x})
+		}
+	}
+
+	if list := d.Get("custom_group").([]interface{}); len(list) > 0 {
+		o.CustomGroups = &useridGroupMappingCustomGroups{}
+		for i := range list {
+			x := list[i].(map[string]interface{})
+			o.CustomGroups.Entries = append(o.CustomGroups.Entries, useridGroupMappingCustomGroup{
+				Name:       x["name"].(string),
+				LdapFilter: x["ldap_filter"].(string),
+			})
+		}
+	}
+
+	return o
+}
+
+func saveUseridGroupMapping(d *schema.ResourceData, o useridGroupMappingEntry) {
+	var includes []string
+	var groups []interface{}
+
+	if o.GroupIncludeList != nil {
+		for _, x := range o.GroupIncludeList.Members {
+			includes = append(includes, x.Value)
+		}
+	}
+
+	if o.CustomGroups != nil {
+		for _, x := range o.CustomGroups.Entries {
+			groups = append(groups, map[string]interface{}{
+				"name":        x.Name,
+				"ldap_filter": x.LdapFilter,
+			})
+		}
+	}
+
+	d.Set("name", o.Name)
+	d.Set("server_profile", o.ServerProfile)
+	d.Set("enabled", o.Enabled != "no")
+	d.Set("update_interval", o.UpdateInterval)
+	d.Set("domain", o.Domain)
+	d.Set("group_object", o.GroupObject)
+	d.Set("group_name", o.GroupName)
+	d.Set("group_member", o.GroupMember)
+	d.Set("group_filter", o.GroupFilter)
+	d.Set("user_object", o.UserObject)
+	d.Set("user_name", o.UserName)
+	d.Set("user_filter", o.UserFilter)
+	d.Set("primary_username", o.PrimaryUsername)
+	d.Set("alternate_username_1", o.AlternateUsername1)
+	d.Set("alternate_username_2", o.AlternateUsername2)
+	d.Set("alternate_username_3", o.AlternateUsername3)
+	if err := d.Set("group_include_list", includes); err != nil {
+		log.Printf("[WARN] Error setting 'group_include_list' for %q: %s", d.Id(), err)
+	}
+	if err := d.Set("custom_group", groups); err != nil {
+		log.Printf("[WARN] Error setting 'custom_group' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildUseridGroupMappingId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseUseridGroupMappingId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func useridGroupMappingXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "group-mapping"), nil
+}
+
+// Config structs.
+type useridGroupMappingEntry struct {
+	XMLName            xml.Name                        `xml:"entry"`
+	Name               string                          `xml:"name,attr"`
+	ServerProfile      string                          `xml:"server-profile"`
+	Enabled            string                          `xml:"enabled"`
+	UpdateInterval     int                             `xml:"update-interval,omitempty"`
+	Domain             string                          `xml:"domain,omitempty"`
+	GroupObject        string                          `xml:"group-object,omitempty"`
+	GroupName          string                          `xml:"group-name,omitempty"`
+	GroupMember        string                          `xml:"group-member,omitempty"`
+	GroupFilter        string                          `xml:"group-filter,omitempty"`
+	UserObject         string                          `xml:"user-object,omitempty"`
+	UserName           string                          `xml:"user-name,omitempty"`
+	UserFilter         string                          `xml:"user-filter,omitempty"`
+	GroupIncludeList   *util.MemberType                `xml:"group-include-list"`
+	CustomGroups       *useridGroupMappingCustomGroups `xml:"custom-group"`
+	PrimaryUsername    string                          `xml:"primary-username,omitempty"`
+	AlternateUsername1 string                          `xml:"alternate-user-name-1,omitempty"`
+	AlternateUsername2 string                          `xml:"alternate-user-name-2,omitempty"`
+	AlternateUsername3 string                          `xml:"alternate-user-name-3,omitempty"`
+}
+
+type useridGroupMappingCustomGroups struct {
+	Entries []useridGroupMappingCustomGroup `xml:"entry"`
+}
+
+type useridGroupMappingCustomGroup struct {
+	Name       string `xml:"name,attr"`
+	LdapFilter string `xml:"ldap-filter"`
+}
+
+type useridGroupMappingAns struct {
+	Entry *useridGroupMappingEntry `xml:"result>entry"`
+}
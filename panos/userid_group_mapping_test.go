@@ -0,0 +1,96 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosUseridGroupMapping(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosUseridGroupMappingDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUseridGroupMappingConfig(name, 3600, "cn=admins,dc=example,dc=com", "mail"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "update_interval", "3600"),
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "group_include_list.0", "cn=admins,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "custom_group.0.ldap_filter", "(title=engineer)"),
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "alternate_username_1", "mail"),
+				),
+			},
+			{
+				Config: testAccUseridGroupMappingConfig(name, 7200, "cn=users,dc=example,dc=com", "userPrincipalName"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "update_interval", "7200"),
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "group_include_list.0", "cn=users,dc=example,dc=com"),
+					resource.TestCheckResourceAttr("panos_userid_group_mapping.test", "alternate_username_1", "userPrincipalName"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosUseridGroupMappingDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_userid_group_mapping" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, vsys, name := parseUseridGroupMappingId(rs.Primary.ID)
+			path, err := useridGroupMappingXpath(testAccProvider.Meta(), tmpl, ts, vsys)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccUseridGroupMappingConfig(name string, interval int, group, alt string) string {
+	return fmt.Sprintf(`
+resource "panos_ldap_profile" "test" {
+    name = %q
+    base_dn = "dc=example,dc=com"
+    bind_dn = "cn=admin,dc=example,dc=com"
+    password = "secret"
+    server {
+        name = "first"
+        server = "ldap.example.com"
+    }
+}
+
+resource "panos_userid_group_mapping" "test" {
+    name = %q
+    server_profile = panos_ldap_profile.test.name
+    update_interval = %d
+    group_include_list = [%q]
+    custom_group {
+        name = "engineers"
+        ldap_filter = "(title=engineer)"
+    }
+    primary_username = "sAMAccountName"
+    alternate_username_1 = %q
+}
+`, name, name, interval, group, alt)
+}
@@ -0,0 +1,206 @@
+package panos
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/userid"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Resource.
+func resourceUseridLogins() *schema.Resource {
+	return &schema.Resource{
+		Create: createUpdateUseridLogins,
+		Read:   readUseridLogins,
+		Update: createUpdateUseridLogins,
+		Delete: deleteUseridLogins,
+
+		Schema: map[string]*schema.Schema{
+			"vsys": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "vsys1",
+				Description: "The vsys to map IPs to users in",
+				ForceNew:    true,
+			},
+			"login": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "IP/user mappings",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "IP address the user is logging in from",
+						},
+						"user": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "User that should be logged in",
+						},
+						"timeout": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Description: "Minutes until the mapping expires",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createUpdateUseridLogins(d *schema.ResourceData, meta interface{}) error {
+	fw, err := firewall(meta, "")
+	if err != nil {
+		return err
+	}
+
+	vsys := d.Get("vsys").(string)
+	logins, err := loadUseridLogins(d.Get("login").(*schema.Set))
+	if err != nil {
+		return err
+	}
+
+	// Log out the users that are no longer mapped to an IP.
+	if d.HasChange("login") && !d.IsNewResource() {
+		o, _ := d.GetChange("login")
+		prev, _ := loadUseridLogins(o.(*schema.Set))
+		msg := &userid.Message{}
+		for ip, x := range prev {
+			if y, ok := logins[ip]; !ok || y.User != x.User {
+				msg.Logouts = append(msg.Logouts, userid.Logout{User: x.User, Ip: ip})
+			}
+		}
+		if len(msg.Logouts) > 0 {
+			if err = fw.UserId.Run(msg, vsys); err != nil {
+				return err
+			}
+		}
+	}
+
+	msg := &userid.Message{
+		Logins: make([]userid.Login, 0, len(logins)),
+	}
+	for _, x := range logins {
+		msg.Logins = append(msg.Logins, x)
+	}
+	if err = fw.UserId.Run(msg, vsys); err != nil {
+		return err
+	}
+
+	if d.IsNewResource() {
+		d.SetId(buildUseridLoginsId(vsys, resource.UniqueId()))
+	}
+	return readUseridLogins(d, meta)
+}
+
+func readUseridLogins(d *schema.ResourceData, meta interface{}) error {
+	fw, err := firewall(meta, "")
+	if err != nil {
+		return err
+	}
+
+	vsys, _ := parseUseridLoginsId(d.Id())
+
+	list, err := fw.UserId.GetLogins("", "", vsys)
+	if err != nil {
+		return err
+	}
+
+	cur := make(map[string]string, len(list))
+	for _, x := range list {
+		cur[x.Ip] = x.User
+	}
+
+	// Only the mappings that are still present are kept, so a mapping that
+	// has expired shows up as drift.  The configured timeout is kept as is,
+	// as PAN-OS reports the time remaining.
+	logins := d.Get("login").(*schema.Set)
+	ans := &schema.Set{F: logins.F}
+	for _, v := range logins.List() {
+		x := v.(map[string]interface{})
+		user, ok := cur[x["ip"].(string)]
+		if !ok {
+			continue
+		}
+		ans.Add(map[string]interface{}{
+			"ip":      x["ip"],
+			"user":    user,
+			"timeout": x["timeout"],
+		})
+	}
+
+	d.Set("vsys", vsys)
+	if err = d.Set("login", ans); err != nil {
+		log.Printf("[WARN] Error setting 'login' for %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func deleteUseridLogins(d *schema.ResourceData, meta interface{}) error {
+	fw, err := firewall(meta, "")
+	if err != nil {
+		return err
+	}
+
+	vsys, _ := parseUseridLoginsId(d.Id())
+	logins, _ := loadUseridLogins(d.Get("login").(*schema.Set))
+
+	msg := &userid.Message{
+		Logouts: make([]userid.Logout, 0, len(logins)),
+	}
+	for ip, x := range logins {
+		msg.Logouts = append(msg.Logouts, userid.Logout{User: x.User, Ip: ip})
+	}
+
+	// The UserId subsystem doesn't return ObjectNotFound, so we don't need
+	// to check for that at this point.
+	if len(msg.Logouts) > 0 {
+		if err = fw.UserId.Run(msg, vsys); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Id functions.
+//
+// More than one of these resources can be in the same vsys, so the ID has a
+// unique part generated on create.
+func buildUseridLoginsId(a, b string) string {
+	return strings.Join([]string{a, b}, IdSeparator)
+}
+
+func parseUseridLoginsId(v string) (string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1]
+}
+
+// loadUseridLogins returns the logins keyed by IP address.
+func loadUseridLogins(s *schema.Set) (map[string]userid.Login, error) {
+	ans := make(map[string]userid.Login, s.Len())
+
+	for _, v := range s.List() {
+		x := v.(map[string]interface{})
+		ip := x["ip"].(string)
+		if _, ok := ans[ip]; ok {
+			return nil, fmt.Errorf("IP %q is mapped more than once", ip)
+		}
+		ans[ip] = userid.Login{
+			User:    x["user"].(string),
+			Ip:      ip,
+			Timeout: x["timeout"].(int),
+		}
+	}
+
+	return ans, nil
+}
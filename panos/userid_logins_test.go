@@ -0,0 +1,126 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosUseridLogins_basic(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	net := fmt.Sprintf("192.168.%d", (acctest.RandInt()%250)+1)
+	u1 := fmt.Sprintf("tf%s", acctest.RandString(6))
+	u2 := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosUseridLoginsDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUseridLoginsConfig(net, u1, u2, 30),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosUseridLoginsExist(net+".1", u1),
+					testAccCheckPanosUseridLoginsExist(net+".2", u2),
+					testAccCheckPanosUseridLoginsExist(net+".3", u1),
+					resource.TestCheckResourceAttr("panos_userid_logins.test", "login.#", "2"),
+					resource.TestCheckResourceAttr("panos_userid_logins.other", "login.#", "1"),
+				),
+			},
+			{
+				Config: testAccUseridLoginsConfig(net, u2, u1, 60),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosUseridLoginsExist(net+".1", u2),
+					testAccCheckPanosUseridLoginsExist(net+".2", u1),
+					testAccCheckPanosUseridLoginsExist(net+".3", u1),
+					resource.TestCheckResourceAttr("panos_userid_logins.test", "login.#", "2"),
+					resource.TestCheckResourceAttr("panos_userid_logins.other", "login.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPanosUseridLoginsExist(ip, user string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		fw := testAccProvider.Meta().(*pango.Firewall)
+		v, err := fw.UserId.GetLogins(ip, "", "vsys1")
+		if err != nil {
+			return err
+		}
+
+		if len(v) != 1 {
+			return fmt.Errorf("Got %d results for %q, not 1", len(v), ip)
+		}
+
+		if v[0].User != user {
+			return fmt.Errorf("User for %q is %q expected %q", ip, v[0].User, user)
+		}
+
+		return nil
+	}
+}
+
+func testAccPanosUseridLoginsDestroy(s *terraform.State) error {
+	fw := testAccProvider.Meta().(*pango.Firewall)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_userid_logins" {
+			continue
+		}
+
+		for k, ip := range rs.Primary.Attributes {
+			if len(k) < 3 || k[len(k)-3:] != ".ip" {
+				continue
+			}
+			vsys, _ := parseUseridLoginsId(rs.Primary.ID)
+			cur, err := fw.UserId.GetLogins(ip, "", vsys)
+			if err != nil {
+				return err
+			}
+			if len(cur) != 0 {
+				return fmt.Errorf("Found logins: %#v", cur)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccUseridLoginsConfig(net, u1, u2 string, timeout int) string {
+	return fmt.Sprintf(`
+resource "panos_userid_logins" "test" {
+    login {
+        ip = "%s.1"
+        user = %q
+        timeout = %d
+    }
+    login {
+        ip = "%s.2"
+        user = %q
+    }
+}
+
+resource "panos_userid_logins" "other" {
+    login {
+        ip = "%s.3"
+        user = %q
+    }
+}
+`, net, u1, timeout, net, u2, net, u1)
+}
+
+func TestParseUseridLoginsId(t *testing.T) {
+	vsys, uid := parseUseridLoginsId(buildUseridLoginsId("vsys2", "20201019000000000000000001"))
+	if vsys != "vsys2" || uid != "20201019000000000000000001" {
+		t.Errorf("Parsed %q / %q", vsys, uid)
+	}
+}
@@ -0,0 +1,355 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// Server monitor types.
+const (
+	UseridServerMonitorActiveDirectory = "active-directory"
+	UseridServerMonitorExchange        = "exchange"
+	UseridServerMonitorEDirectory      = "e-directory"
+	UseridServerMonitorSyslog          = "syslog"
+)
+
+// Resource.
+func resourceUseridServerMonitor() *schema.Resource {
+	return &schema.Resource{
+		Create: createUseridServerMonitor,
+		Read:   readUseridServerMonitor,
+		Update: updateUseridServerMonitor,
+		Delete: deleteUseridServerMonitor,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"template":       templateSchema(true),
+			"template_stack": templateStackSchema(),
+			"vsys":           vsysSchema("vsys1"),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The server monitor name",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Enable this server monitor",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The server type",
+				ValidateFunc: validation.StringInSlice([]string{
+					UseridServerMonitorActiveDirectory,
+					UseridServerMonitorExchange,
+					UseridServerMonitorEDirectory,
+					UseridServerMonitorSyslog,
+				}, false),
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(active-directory / exchange / syslog) The server hostname or IP address",
+			},
+			"server_profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "(e-directory) The LDAP server profile",
+			},
+			"connection_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "(syslog) The syslog connection type",
+				ValidateFunc: validation.StringInSlice([]string{"", "udp", "ssl"}, false),
+			},
+			"syslog_parse_profile": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "(syslog) Syslog parse profiles",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"event_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "login",
+							ValidateFunc: validation.StringInSlice([]string{"login", "logout"}, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func createUseridServerMonitor(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o, err := loadUseridServerMonitor(d)
+	if err != nil {
+		return err
+	}
+
+	path, err := useridServerMonitorXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Server monitor %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildUseridServerMonitorId(tmpl, ts, vsys, o.Name))
+	return readUseridServerMonitor(d, meta)
+}
+
+func readUseridServerMonitor(d *schema.ResourceData, meta interface{}) error {
+	var ans useridServerMonitorAns
+
+	tmpl, ts, vsys, name := parseUseridServerMonitorId(d.Id())
+
+	path, err := useridServerMonitorXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("vsys", vsys)
+	saveUseridServerMonitor(d, *ans.Entry)
+
+	return nil
+}
+
+func updateUseridServerMonitor(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseUseridServerMonitorId(d.Id())
+	o, err := loadUseridServerMonitor(d)
+	if err != nil {
+		return err
+	}
+
+	path, err := useridServerMonitorXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{name})), o, nil, nil); err != nil {
+		return err
+	}
+
+	return readUseridServerMonitor(d, meta)
+}
+
+func deleteUseridServerMonitor(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, vsys, name := parseUseridServerMonitorId(d.Id())
+
+	path, err := useridServerMonitorXpath(meta, tmpl, ts, vsys)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func loadUseridServerMonitor(d *schema.ResourceData) (useridServerMonitorEntry, error) {
+	o := useridServerMonitorEntry{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Enable:      util.YesNo(d.Get("enabled").(bool)),
+	}
+
+	host := d.Get("host").(string)
+	profile := d.Get("server_profile").(string)
+
+	switch typ := d.Get("type").(string); typ {
+	case UseridServerMonitorActiveDirectory, UseridServerMonitorExchange:
+		if host == "" {
+			return o, fmt.Errorf("host must be specified for type %q", typ)
+		}
+		spec := &useridServerMonitorHost{Host: host}
+		if typ == UseridServerMonitorActiveDirectory {
+			o.ActiveDirectory = spec
+		} else {
+			o.Exchange = spec
+		}
+	case UseridServerMonitorEDirectory:
+		if profile == "" {
+			return o, fmt.Errorf("server_profile must be specified for type %q", typ)
+		}
+		o.EDirectory = &useridServerMonitorEDirectory{ServerProfile: profile}
+	case UseridServerMonitorSyslog:
+		if host == "" {
+			return o, fmt.Errorf("host must be specified for type %q", typ)
+		}
+		o.Syslog = &useridServerMonitorSyslog{
+			Address:        host,
+			ConnectionType: d.Get("connection_type").(string),
+		}
+		if list := d.Get("syslog_parse_profile").([]interface{}); len(list) > 0 {
+			o.Syslog.ParseProfiles = &useridServerMonitorParseProfiles{}
+			for i := range list {
+				x := list[i].(map[string]interface{})
+				o.Syslog.ParseProfiles.Entries = append(o.Syslog.ParseProfiles.Entries, useridServerMonitorParseProfile{
+					Name:      x["name"].(string),
+					EventType: x["event_type"].(string),
+				})
+			}
+		}
+	}
+
+	return o, nil
+}
+
+func saveUseridServerMonitor(d *schema.ResourceData, o useridServerMonitorEntry) {
+	var typ, host, profile, connType string
+	var profiles []interface{}
+
+	switch {
+	case o.ActiveDirectory != nil:
+		typ = UseridServerMonitorActiveDirectory
+		host = o.ActiveDirectory.Host
+	case o.Exchange != nil:
+		typ = UseridServerMonitorExchange
+		host = o.Exchange.Host
+	case o.EDirectory != nil:
+		typ = UseridServerMonitorEDirectory
+		profile = o.EDirectory.ServerProfile
+	case o.Syslog != nil:
+		typ = UseridServerMonitorSyslog
+		host = o.Syslog.Address
+		connType = o.Syslog.ConnectionType
+		if o.Syslog.ParseProfiles != nil {
+			for _, x := range o.Syslog.ParseProfiles.Entries {
+				profiles = append(profiles, map[string]interface{}{
+					"name":       x.Name,
+					"event_type": x.EventType,
+				})
+			}
+		}
+	}
+
+	d.Set("name", o.Name)
+	d.Set("description", o.Description)
+	d.Set("enabled", o.Enable != "no")
+	d.Set("type", typ)
+	d.Set("host", host)
+	d.Set("server_profile", profile)
+	d.Set("connection_type", connType)
+	if err := d.Set("syslog_parse_profile", profiles); err != nil {
+		log.Printf("[WARN] Error setting 'syslog_parse_profile' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildUseridServerMonitorId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseUseridServerMonitorId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func useridServerMonitorXpath(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := vsysXpathPrefix(meta, tmpl, ts, vsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "user-id-collector", "server-monitor"), nil
+}
+
+// Config structs.
+type useridServerMonitorEntry struct {
+	XMLName         xml.Name                       `xml:"entry"`
+	Name            string                         `xml:"name,attr"`
+	Description     string                         `xml:"description,omitempty"`
+	Enable          string                         `xml:"enable"`
+	ActiveDirectory *useridServerMonitorHost       `xml:"active-directory"`
+	Exchange        *useridServerMonitorHost       `xml:"exchange"`
+	EDirectory      *useridServerMonitorEDirectory `xml:"e-directory"`
+	Syslog          *useridServerMonitorSyslog     `xml:"syslog"`
+}
+
+type useridServerMonitorHost struct {
+	Host string `xml:"host"`
+}
+
+type useridServerMonitorEDirectory struct {
+	ServerProfile string `xml:"server-profile"`
+}
+
+type useridServerMonitorSyslog struct {
+	Address        string                            `xml:"address"`
+	ConnectionType string                            `xml:"connection-type,omitempty"`
+	ParseProfiles  *useridServerMonitorParseProfiles `xml:"syslog-parse-profile"`
+}
+
+type useridServerMonitorParseProfiles struct {
+	Entries []useridServerMonitorParseProfile `xml:"entry"`
+}
+
+type useridServerMonitorParseProfile struct {
+	Name      string `xml:"name,attr"`
+	EventType string `xml:"event-type,omitempty"`
+}
+
+type useridServerMonitorAns struct {
+	Entry *useridServerMonitorEntry `xml:"result>entry"`
+}
@@ -0,0 +1,76 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosUseridServerMonitor(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosUseridServerMonitorDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUseridServerMonitorConfig(name, UseridServerMonitorActiveDirectory, "10.2.2.2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_userid_server_monitor.test", "type", UseridServerMonitorActiveDirectory),
+					resource.TestCheckResourceAttr("panos_userid_server_monitor.test", "host", "10.2.2.2"),
+				),
+			},
+			{
+				Config: testAccUseridServerMonitorConfig(name, UseridServerMonitorExchange, "10.2.2.3"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_userid_server_monitor.test", "type", UseridServerMonitorExchange),
+					resource.TestCheckResourceAttr("panos_userid_server_monitor.test", "host", "10.2.2.3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosUseridServerMonitorDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_userid_server_monitor" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, vsys, name := parseUseridServerMonitorId(rs.Primary.ID)
+			path, err := useridServerMonitorXpath(testAccProvider.Meta(), tmpl, ts, vsys)
+			if err != nil {
+				return err
+			}
+			if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccUseridServerMonitorConfig(name, typ, host string) string {
+	return fmt.Sprintf(`
+resource "panos_userid_server_monitor" "test" {
+    name = %q
+    description = "made by terraform"
+    type = %q
+    host = %q
+}
+`, name, typ, host)
+}
@@ -0,0 +1,456 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+)
+
+// Tag registrations are sent as raw User-ID messages, as pango does not
+// support the timeout and persistent attributes for tags.
+
+// ipTagRegistration is a set of tags to register to an IP address.
+type ipTagRegistration struct {
+	Ip         string
+	Tags       []string
+	Timeout    int
+	Persistent bool
+}
+
+// userTagRegistration is a set of tags to register to a user.
+type userTagRegistration struct {
+	User       string
+	Tags       []string
+	Timeout    int
+	Persistent bool
+}
+
+// userGroupTagRegistration is a set of tags to register to the members of
+// a user group.
+//
+// PAN-OS does not register tags to the group itself, so the tags are
+// registered to each user that is currently a member of the group.
+type userGroupTagRegistration struct {
+	Group      string
+	Tags       []string
+	Timeout    int
+	Persistent bool
+}
+
+// userTagRegistrations returns the user tag registrations for the given
+// group members.
+func (o userGroupTagRegistration) userTagRegistrations(members []string) []userTagRegistration {
+	ans := make([]userTagRegistration, 0, len(members))
+	for _, user := range members {
+		ans = append(ans, userTagRegistration{
+			User:       user,
+			Tags:       o.Tags,
+			Timeout:    o.Timeout,
+			Persistent: o.Persistent,
+		})
+	}
+
+	return ans
+}
+
+// registeredTags is the current state of an IP or user's tags.
+type registeredTags struct {
+	Persistent string
+	Tags       []string
+
+	// Timeouts is the remaining lifetime in seconds of the tags that
+	// have a timeout.
+	Timeouts map[string]int
+}
+
+// Has returns if the given tag is registered.
+func (o registeredTags) Has(tag string) bool {
+	for _, x := range o.Tags {
+		if x == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemainingLifetime returns the shortest remaining lifetime of the given
+// tags, or 0 if none of them have a timeout.
+func (o registeredTags) RemainingLifetime(tags []string) int {
+	var ans int
+
+	for _, tag := range tags {
+		if v, ok := o.Timeouts[tag]; ok && v > 0 && (ans == 0 || v < ans) {
+			ans = v
+		}
+	}
+
+	return ans
+}
+
+// registerIpTags registers the given IP tags.
+func registerIpTags(c *pango.Client, vsys string, list []ipTagRegistration) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	spec := &uidIpTagSpec{Entries: make([]uidIpTagEntry, 0, len(list))}
+	for _, x := range list {
+		entry := uidIpTagEntry{
+			Ip:   x.Ip,
+			Tags: uidTagMembers(x.Tags, x.Timeout),
+		}
+		if x.Persistent {
+			entry.Persistent = "1"
+		} else {
+			entry.Persistent = "0"
+		}
+		spec.Entries = append(spec.Entries, entry)
+	}
+
+	return runUidMessage(c, vsys, uidPayload{IpTags: spec}, fmt.Sprintf("tagip:%d", len(list)))
+}
+
+// registerUserTags registers the given user tags.
+func registerUserTags(c *pango.Client, vsys string, list []userTagRegistration) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	spec := &uidUserTagSpec{Entries: make([]uidUserTagEntry, 0, len(list))}
+	for _, x := range list {
+		entry := uidUserTagEntry{
+			User: x.User,
+			Tags: uidTagMembers(x.Tags, x.Timeout),
+		}
+		if x.Persistent {
+			entry.Persistent = "1"
+		} else {
+			entry.Persistent = "0"
+		}
+		spec.Entries = append(spec.Entries, entry)
+	}
+
+	return runUidMessage(c, vsys, uidPayload{UserTags: spec}, fmt.Sprintf("taguser:%d", len(list)))
+}
+
+// unregisterUserTags unregisters the given user tags.
+//
+// Only the user and tags of each registration are used.
+func unregisterUserTags(c *pango.Client, vsys string, list []userTagRegistration) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	spec := &uidUserTagSpec{Entries: make([]uidUserTagEntry, 0, len(list))}
+	for _, x := range list {
+		spec.Entries = append(spec.Entries, uidUserTagEntry{
+			User: x.User,
+			Tags: uidTagMembers(x.Tags, 0),
+		})
+	}
+
+	return runUidMessage(c, vsys, uidPayload{UntagUsers: spec}, fmt.Sprintf("untaguser:%d", len(list)))
+}
+
+// registerUserGroupTags registers the given tags to the current members of
+// the user group, returning the members that were tagged.
+func registerUserGroupTags(fw *pango.Firewall, vsys string, reg userGroupTagRegistration) ([]string, error) {
+	members, err := fw.UserId.GetGroupMembers(reg.Group, vsys)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("User group %q has no members to tag", reg.Group)
+	}
+
+	if err = registerUserTags(&fw.Client, vsys, reg.userTagRegistrations(members)); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// unregisterUserGroupTags unregisters the given tags from the users, which
+// should be both the current and previously tagged members of the group.
+//
+// Only the tags that are still registered to each user are unregistered.
+func unregisterUserGroupTags(fw *pango.Firewall, vsys string, users, tags []string) error {
+	cur, err := getRegisteredUserTags(&fw.Client, "", vsys)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	list := make([]userTagRegistration, 0, len(users))
+	for _, user := range users {
+		if seen[user] {
+			continue
+		}
+		seen[user] = true
+
+		info, ok := cur[user]
+		if !ok {
+			continue
+		}
+		reg := userTagRegistration{User: user}
+		for _, tag := range tags {
+			if info.Has(tag) {
+				reg.Tags = append(reg.Tags, tag)
+			}
+		}
+		if len(reg.Tags) > 0 {
+			list = append(list, reg)
+		}
+	}
+
+	return unregisterUserTags(&fw.Client, vsys, list)
+}
+
+func runUidMessage(c *pango.Client, vsys string, payload uidPayload, desc string) error {
+	var ans uidRegisterAns
+
+	if vsys == "" {
+		vsys = "vsys1"
+	}
+
+	msg := uidRegisterMsg{
+		Version: "1.0",
+		Type:    "update",
+		Payload: payload,
+	}
+
+	c.LogUid("(userid) running in %s - %s", vsys, desc)
+	if _, err := c.Uid(msg, vsys, nil, &ans); err != nil {
+		return err
+	}
+
+	entries := make([]uidRegisterAnsEntry, 0, len(ans.IpTags)+len(ans.UserTags)+len(ans.UntagUsers))
+	entries = append(entries, ans.IpTags...)
+	entries = append(entries, ans.UserTags...)
+	entries = append(entries, ans.UntagUsers...)
+
+	msgs := make([]string, 0, len(entries))
+	for _, x := range entries {
+		if x.Message != "" {
+			name := x.Ip
+			if name == "" {
+				name = x.User
+			}
+			msgs = append(msgs, fmt.Sprintf("%s:%q", name, x.Message))
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("User-ID registration failed: %s", strings.Join(msgs, " | "))
+	}
+
+	return nil
+}
+
+// getRegisteredIpTags returns the registered IP tags, keyed by IP address.
+//
+// The ip param is a server-side filter.
+func getRegisteredIpTags(c *pango.Client, ip, vsys string) (map[string]registeredTags, error) {
+	if vsys == "" {
+		vsys = "vsys1"
+	}
+	c.LogOp("(op) getting registered ip addresses - ip:%q vsys:%q", ip, vsys)
+
+	req := showRegisteredIpReq{
+		Ip:    ip,
+		Limit: 500,
+	}
+	ans := make(map[string]registeredTags)
+	for {
+		var resp showRegisteredIpAns
+
+		req.Start = len(ans) + 1
+		if _, err := c.Op(req, vsys, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, x := range resp.Entries {
+			ans[x.Ip] = x.registeredTags()
+		}
+
+		if len(resp.Entries) < req.Limit {
+			break
+		}
+	}
+
+	return ans, nil
+}
+
+// getRegisteredUserTags returns the registered user tags, keyed by user.
+//
+// The user param is a server-side filter.
+func getRegisteredUserTags(c *pango.Client, user, vsys string) (map[string]registeredTags, error) {
+	if vsys == "" {
+		vsys = "vsys1"
+	}
+	c.LogOp("(op) getting user tags: user:%q vsys %q", user, vsys)
+
+	req := showRegisteredUserReq{}
+	if user != "" {
+		req.User = user
+	} else {
+		req.All = &showRegisteredUserAll{Limit: 500}
+	}
+
+	ans := make(map[string]registeredTags)
+	for {
+		var resp showRegisteredUserAns
+
+		if req.All != nil {
+			req.All.Start = len(ans) + 1
+		}
+		if _, err := c.Op(req, vsys, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, x := range resp.Entries {
+			ans[x.User] = x.registeredTags()
+		}
+
+		if req.All == nil || len(resp.Entries) < req.All.Limit {
+			break
+		}
+	}
+
+	return ans, nil
+}
+
+func uidTagMembers(tags []string, timeout int) []uidTagMember {
+	ans := make([]uidTagMember, 0, len(tags))
+	for _, tag := range tags {
+		ans = append(ans, uidTagMember{Tag: tag, Timeout: timeout})
+	}
+
+	return ans
+}
+
+// User-ID structs.
+type uidRegisterMsg struct {
+	XMLName xml.Name   `xml:"uid-message"`
+	Version string     `xml:"version"`
+	Type    string     `xml:"type"`
+	Payload uidPayload `xml:"payload"`
+}
+
+type uidPayload struct {
+	IpTags     *uidIpTagSpec   `xml:"register"`
+	UserTags   *uidUserTagSpec `xml:"register-user"`
+	UntagUsers *uidUserTagSpec `xml:"unregister-user"`
+}
+
+type uidIpTagSpec struct {
+	Entries []uidIpTagEntry `xml:"entry"`
+}
+
+type uidIpTagEntry struct {
+	Ip         string         `xml:"ip,attr"`
+	Persistent string         `xml:"persistent,attr,omitempty"`
+	Tags       []uidTagMember `xml:"tag>member"`
+}
+
+type uidUserTagSpec struct {
+	Entries []uidUserTagEntry `xml:"entry"`
+}
+
+type uidUserTagEntry struct {
+	User       string         `xml:"user,attr"`
+	Persistent string         `xml:"persistent,attr,omitempty"`
+	Tags       []uidTagMember `xml:"tag>member"`
+}
+
+type uidTagMember struct {
+	Tag     string `xml:",chardata"`
+	Timeout int    `xml:"timeout,attr,omitempty"`
+}
+
+type uidRegisterAns struct {
+	IpTags     []uidRegisterAnsEntry `xml:"msg>line>uid-response>payload>register>entry"`
+	UserTags   []uidRegisterAnsEntry `xml:"msg>line>uid-response>payload>register-user>entry"`
+	UntagUsers []uidRegisterAnsEntry `xml:"msg>line>uid-response>payload>unregister-user>entry"`
+}
+
+type uidRegisterAnsEntry struct {
+	Ip      string `xml:"ip,attr"`
+	User    string `xml:"user,attr"`
+	Message string `xml:"message,attr"`
+}
+
+// Op structs.
+type showRegisteredIpReq struct {
+	XMLName xml.Name `xml:"show"`
+	Ip      string   `xml:"object>registered-ip>ip,omitempty"`
+	Limit   int      `xml:"object>registered-ip>limit"`
+	Start   int      `xml:"object>registered-ip>start-point"`
+}
+
+type showRegisteredIpAns struct {
+	Entries []registeredIpEntry `xml:"result>entry"`
+}
+
+type registeredIpEntry struct {
+	Ip         string                `xml:"ip,attr"`
+	Persistent string                `xml:"persistent,attr"`
+	Tags       []registeredTagMember `xml:"tag>member"`
+}
+
+func (o registeredIpEntry) registeredTags() registeredTags {
+	ans := asRegisteredTags(o.Tags)
+	ans.Persistent = o.Persistent
+	return ans
+}
+
+type showRegisteredUserReq struct {
+	XMLName xml.Name               `xml:"show"`
+	All     *showRegisteredUserAll `xml:"object>registered-user>all"`
+	User    string                 `xml:"object>registered-user>user,omitempty"`
+}
+
+type showRegisteredUserAll struct {
+	Limit int `xml:"limit"`
+	Start int `xml:"start-point"`
+}
+
+type showRegisteredUserAns struct {
+	Entries []registeredUserEntry `xml:"result>entry"`
+}
+
+type registeredUserEntry struct {
+	User       string                `xml:"user,attr"`
+	Persistent string                `xml:"persistent,attr"`
+	Tags       []registeredTagMember `xml:"tag>member"`
+}
+
+func (o registeredUserEntry) registeredTags() registeredTags {
+	ans := asRegisteredTags(o.Tags)
+	ans.Persistent = o.Persistent
+	return ans
+}
+
+type registeredTagMember struct {
+	Tag     string `xml:",chardata"`
+	Timeout int    `xml:"timeout,attr"`
+}
+
+func asRegisteredTags(list []registeredTagMember) registeredTags {
+	ans := registeredTags{
+		Tags:     make([]string, 0, len(list)),
+		Timeouts: make(map[string]int),
+	}
+
+	for _, x := range list {
+		tag := strings.TrimSpace(x.Tag)
+		ans.Tags = append(ans.Tags, tag)
+		if x.Timeout > 0 {
+			ans.Timeouts[tag] = x.Timeout
+		}
+	}
+
+	return ans
+}
@@ -0,0 +1,93 @@
+package panos
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestUidUserTagPayload(t *testing.T) {
+	testCases := []struct {
+		payload uidPayload
+		ans     string
+	}{
+		{
+			uidPayload{UserTags: &uidUserTagSpec{Entries: []uidUserTagEntry{{
+				User:       "user1",
+				Persistent: "1",
+				Tags:       uidTagMembers([]string{"tag1", "tag2"}, 60),
+			}}}},
+			`<payload><register-user><entry user="user1" persistent="1"><tag><member timeout="60">tag1</member><member timeout="60">tag2</member></tag></entry></register-user></payload>`,
+		},
+		{
+			uidPayload{UserTags: &uidUserTagSpec{Entries: []uidUserTagEntry{{
+				User:       "user1",
+				Persistent: "0",
+				Tags:       uidTagMembers([]string{"tag1"}, 0),
+			}}}},
+			`<payload><register-user><entry user="user1" persistent="0"><tag><member>tag1</member></tag></entry></register-user></payload>`,
+		},
+		{
+			uidPayload{UntagUsers: &uidUserTagSpec{Entries: []uidUserTagEntry{{
+				User: "user1",
+				Tags: uidTagMembers([]string{"tag1"}, 0),
+			}}}},
+			`<payload><unregister-user><entry user="user1"><tag><member>tag1</member></tag></entry></unregister-user></payload>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		b, err := xml.Marshal(struct {
+			XMLName xml.Name `xml:"payload"`
+			uidPayload
+		}{uidPayload: tc.payload})
+		if err != nil {
+			t.Fatalf("Error in marshal: %s", err)
+		}
+		if string(b) != tc.ans {
+			t.Errorf("expected %s, got %s", tc.ans, b)
+		}
+	}
+}
+
+func TestUserGroupTagRegistrations(t *testing.T) {
+	reg := userGroupTagRegistration{
+		Group:      "cn=group1",
+		Tags:       []string{"tag1", "tag2"},
+		Timeout:    30,
+		Persistent: true,
+	}
+
+	ans := reg.userTagRegistrations([]string{"user1", "user2"})
+	expected := []userTagRegistration{
+		{User: "user1", Tags: []string{"tag1", "tag2"}, Timeout: 30, Persistent: true},
+		{User: "user2", Tags: []string{"tag1", "tag2"}, Timeout: 30, Persistent: true},
+	}
+
+	if !reflect.DeepEqual(ans, expected) {
+		t.Errorf("expected %#v, got %#v", expected, ans)
+	}
+}
+
+func TestRegisteredUserEntry(t *testing.T) {
+	var ans showRegisteredUserAns
+
+	data := `<response status="success"><result><entry user="user1" persistent="0"><tag><member timeout="25">tag1</member><member>tag2</member></tag></entry></result></response>`
+	if err := xml.Unmarshal([]byte(data), &ans); err != nil {
+		t.Fatalf("Error in unmarshal: %s", err)
+	}
+	if len(ans.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(ans.Entries))
+	}
+
+	info := ans.Entries[0].registeredTags()
+	if info.Persistent != "0" {
+		t.Errorf("expected persistent %q, got %q", "0", info.Persistent)
+	}
+	if !info.Has("tag1") || !info.Has("tag2") {
+		t.Errorf("missing tags: %#v", info.Tags)
+	}
+	if v := info.RemainingLifetime([]string{"tag1", "tag2"}); v != 25 {
+		t.Errorf("expected remaining lifetime 25, got %d", v)
+	}
+}
@@ -0,0 +1,520 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	vwireEthernetInterface  = "ethernet"
+	vwireAggregateInterface = "aggregate-ethernet"
+)
+
+// Resource (virtual wire).
+func resourceVirtualWire() *schema.Resource {
+	return &schema.Resource{
+		Create: createVirtualWire,
+		Read:   readVirtualWire,
+		Update: updateVirtualWire,
+		Delete: deleteVirtualWire,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: virtualWireSchema(),
+	}
+}
+
+func createVirtualWire(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadVirtualWire(d)
+
+	path, err := virtualWireXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Virtual wire %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+	if err = c.VsysImport(util.VirtualWireImport, tmpl, ts, vsys, []string{o.Name}); err != nil {
+		c.Delete(append(path, util.AsEntryXpath([]string{o.Name})), nil, nil)
+		return err
+	}
+
+	d.SetId(buildVirtualWireId(tmpl, ts, vsys, o.Name))
+	return readVirtualWire(d, meta)
+}
+
+func readVirtualWire(d *schema.ResourceData, meta interface{}) error {
+	var ans virtualWireAns
+
+	tmpl, ts, vsys, name := parseVirtualWireId(d.Id())
+
+	path, err := virtualWireXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+	path = append(path, util.AsEntryXpath([]string{name}))
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	rv, err := c.IsImported(util.VirtualWireImport, tmpl, ts, vsys, name)
+	if err != nil {
+		return err
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	if rv {
+		d.Set("vsys", vsys)
+	} else {
+		d.Set("vsys", fmt.Sprintf("(not %s)", vsys))
+	}
+	saveVirtualWire(d, *ans.Entry)
+
+	return nil
+}
+
+func updateVirtualWire(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadVirtualWire(d)
+
+	path, err := virtualWireXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{o.Name})), o, nil, nil); err != nil {
+		return err
+	}
+	if d.HasChange("vsys") {
+		if err = c.VsysUnimport(util.VirtualWireImport, tmpl, ts, []string{o.Name}); err != nil {
+			return err
+		}
+		if err = c.VsysImport(util.VirtualWireImport, tmpl, ts, vsys, []string{o.Name}); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(buildVirtualWireId(tmpl, ts, vsys, o.Name))
+	return readVirtualWire(d, meta)
+}
+
+func deleteVirtualWire(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, _, name := parseVirtualWireId(d.Id())
+
+	path, err := virtualWireXpath(meta, tmpl, ts)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if err = c.VsysUnimport(util.VirtualWireImport, tmpl, ts, []string{name}); err != nil {
+		return err
+	}
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Resource (virtual wire subinterface).
+func resourceVirtualWireSubinterface() *schema.Resource {
+	return &schema.Resource{
+		Create: createVirtualWireSubinterface,
+		Read:   readVirtualWireSubinterface,
+		Update: updateVirtualWireSubinterface,
+		Delete: deleteVirtualWireSubinterface,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: virtualWireSubinterfaceSchema(),
+	}
+}
+
+func createVirtualWireSubinterface(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	iType := d.Get("interface_type").(string)
+	eth := d.Get("parent_interface").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadVirtualWireSubinterface(d)
+
+	path, err := virtualWireSubinterfaceXpath(meta, tmpl, ts, iType, eth)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == o.Name {
+			return fmt.Errorf("Subinterface %q already exists", o.Name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+	if err = c.VsysImport(util.InterfaceImport, tmpl, ts, vsys, []string{o.Name}); err != nil {
+		c.Delete(append(path, util.AsEntryXpath([]string{o.Name})), nil, nil)
+		return err
+	}
+
+	d.SetId(buildVirtualWireSubinterfaceId(tmpl, ts, iType, eth, vsys, o.Name))
+	return readVirtualWireSubinterface(d, meta)
+}
+
+func readVirtualWireSubinterface(d *schema.ResourceData, meta interface{}) error {
+	var ans virtualWireSubinterfaceAns
+
+	tmpl, ts, iType, eth, vsys, name := parseVirtualWireSubinterfaceId(d.Id())
+
+	path, err := virtualWireSubinterfaceXpath(meta, tmpl, ts, iType, eth)
+	if err != nil {
+		return err
+	}
+	path = append(path, util.AsEntryXpath([]string{name}))
+
+	c := rawClient(meta)
+	if _, err = c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if ans.Entry == nil {
+		d.SetId("")
+		return nil
+	}
+
+	rv, err := c.IsImported(util.InterfaceImport, tmpl, ts, vsys, name)
+	if err != nil {
+		return err
+	}
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	d.Set("interface_type", iType)
+	d.Set("parent_interface", eth)
+	if rv {
+		d.Set("vsys", vsys)
+	} else {
+		d.Set("vsys", fmt.Sprintf("(not %s)", vsys))
+	}
+	saveVirtualWireSubinterface(d, *ans.Entry)
+
+	return nil
+}
+
+func updateVirtualWireSubinterface(d *schema.ResourceData, meta interface{}) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	iType := d.Get("interface_type").(string)
+	eth := d.Get("parent_interface").(string)
+	vsys := d.Get("vsys").(string)
+	o := loadVirtualWireSubinterface(d)
+
+	path, err := virtualWireSubinterfaceXpath(meta, tmpl, ts, iType, eth)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Edit(append(path, util.AsEntryXpath([]string{o.Name})), o, nil, nil); err != nil {
+		return err
+	}
+	if d.HasChange("vsys") {
+		if err = c.VsysUnimport(util.InterfaceImport, tmpl, ts, []string{o.Name}); err != nil {
+			return err
+		}
+		if err = c.VsysImport(util.InterfaceImport, tmpl, ts, vsys, []string{o.Name}); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(buildVirtualWireSubinterfaceId(tmpl, ts, iType, eth, vsys, o.Name))
+	return readVirtualWireSubinterface(d, meta)
+}
+
+func deleteVirtualWireSubinterface(d *schema.ResourceData, meta interface{}) error {
+	tmpl, ts, iType, eth, _, name := parseVirtualWireSubinterfaceId(d.Id())
+
+	path, err := virtualWireSubinterfaceXpath(meta, tmpl, ts, iType, eth)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if err = c.VsysUnimport(util.InterfaceImport, tmpl, ts, []string{name}); err != nil {
+		return err
+	}
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func virtualWireImportVsysSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "vsys1",
+		Description: "The vsys to import into",
+	}
+}
+
+func virtualWireSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template":       templateSchema(true),
+		"template_stack": templateStackSchema(),
+		"vsys":           virtualWireImportVsysSchema(),
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name",
+		},
+		"interface1": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The first interface",
+		},
+		"interface2": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The second interface",
+		},
+		"tag_allowed": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Allowed VLAN tags, such as \"0-4094\" or \"10,20-30\"",
+		},
+		"multicast_firewalling": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Apply security policy to multicast traffic",
+		},
+		"link_state_pass_through": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Bring down the other interface if one goes down",
+		},
+	}
+}
+
+func virtualWireSubinterfaceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"template":       templateSchema(true),
+		"template_stack": templateStackSchema(),
+		"vsys":           virtualWireImportVsysSchema(),
+		"interface_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      vwireEthernetInterface,
+			Description:  "The parent interface type",
+			ValidateFunc: validation.StringInSlice([]string{vwireEthernetInterface, vwireAggregateInterface}, false),
+		},
+		"parent_interface": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The parent interface",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name, such as ethernet1/1.5",
+		},
+		"tag": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The VLAN tag",
+			ValidateFunc: validation.IntBetween(0, 4094),
+		},
+		"netflow_profile": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The netflow profile",
+		},
+		"comment": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The comment",
+		},
+	}
+}
+
+func loadVirtualWire(d *schema.ResourceData) virtualWireEntry {
+	o := virtualWireEntry{
+		Name:       d.Get("name").(string),
+		Interface1: d.Get("interface1").(string),
+		Interface2: d.Get("interface2").(string),
+		TagAllowed: d.Get("tag_allowed").(string),
+	}
+
+	if d.Get("multicast_firewalling").(bool) {
+		o.Multicast = &virtualWireEnable{Firewalling: &virtualWireFlag{Enable: util.YesNo(true)}}
+	}
+	if d.Get("link_state_pass_through").(bool) {
+		o.LinkStatePassThrough = &virtualWireFlag{Enable: util.YesNo(true)}
+	}
+
+	return o
+}
+
+func saveVirtualWire(d *schema.ResourceData, o virtualWireEntry) {
+	d.Set("name", o.Name)
+	d.Set("interface1", o.Interface1)
+	d.Set("interface2", o.Interface2)
+	d.Set("tag_allowed", o.TagAllowed)
+	d.Set("multicast_firewalling", o.Multicast != nil && o.Multicast.Firewalling != nil && util.AsBool(o.Multicast.Firewalling.Enable))
+	d.Set("link_state_pass_through", o.LinkStatePassThrough != nil && util.AsBool(o.LinkStatePassThrough.Enable))
+}
+
+func loadVirtualWireSubinterface(d *schema.ResourceData) virtualWireSubinterfaceEntry {
+	return virtualWireSubinterfaceEntry{
+		Name:           d.Get("name").(string),
+		Tag:            d.Get("tag").(int),
+		NetflowProfile: d.Get("netflow_profile").(string),
+		Comment:        d.Get("comment").(string),
+	}
+}
+
+func saveVirtualWireSubinterface(d *schema.ResourceData, o virtualWireSubinterfaceEntry) {
+	d.Set("name", o.Name)
+	d.Set("tag", o.Tag)
+	d.Set("netflow_profile", o.NetflowProfile)
+	d.Set("comment", o.Comment)
+}
+
+// Id functions.
+func buildVirtualWireId(a, b, c, d string) string {
+	return strings.Join([]string{a, b, c, d}, IdSeparator)
+}
+
+func parseVirtualWireId(v string) (string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3]
+}
+
+func buildVirtualWireSubinterfaceId(a, b, c, d, e, f string) string {
+	return strings.Join([]string{a, b, c, d, e, f}, IdSeparator)
+}
+
+func parseVirtualWireSubinterfaceId(v string) (string, string, string, string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	return t[0], t[1], t[2], t[3], t[4], t[5]
+}
+
+func virtualWireXpath(meta interface{}, tmpl, ts string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "network", "virtual-wire"), nil
+}
+
+func virtualWireSubinterfaceXpath(meta interface{}, tmpl, ts, iType, eth string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans,
+		"network",
+		"interface",
+		iType,
+		util.AsEntryXpath([]string{eth}),
+		"virtual-wire",
+		"units",
+	), nil
+}
+
+// Config structs.
+type virtualWireEntry struct {
+	XMLName              xml.Name           `xml:"entry"`
+	Name                 string             `xml:"name,attr"`
+	Interface1           string             `xml:"interface1,omitempty"`
+	Interface2           string             `xml:"interface2,omitempty"`
+	TagAllowed           string             `xml:"tag-allowed,omitempty"`
+	Multicast            *virtualWireEnable `xml:"multicast"`
+	LinkStatePassThrough *virtualWireFlag   `xml:"link-state-pass-through"`
+}
+
+type virtualWireEnable struct {
+	Firewalling *virtualWireFlag `xml:"firewalling"`
+}
+
+type virtualWireFlag struct {
+	Enable string `xml:"enable"`
+}
+
+type virtualWireAns struct {
+	Entry *virtualWireEntry `xml:"result>entry"`
+}
+
+type virtualWireSubinterfaceEntry struct {
+	XMLName        xml.Name `xml:"entry"`
+	Name           string   `xml:"name,attr"`
+	Tag            int      `xml:"tag,omitempty"`
+	NetflowProfile string   `xml:"netflow-profile,omitempty"`
+	Comment        string   `xml:"comment,omitempty"`
+}
+
+type virtualWireSubinterfaceAns struct {
+	Entry *virtualWireSubinterfaceEntry `xml:"result>entry"`
+}
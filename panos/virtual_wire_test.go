@@ -0,0 +1,111 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosVirtualWire(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+	num := acctest.RandIntRange(1, 4094)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosVirtualWireDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVirtualWireConfig(name, num, "0-4094", true, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "interface1", "ethernet1/6"),
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "tag_allowed", "0-4094"),
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "multicast_firewalling", "true"),
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "link_state_pass_through", "true"),
+					resource.TestCheckResourceAttr("panos_virtual_wire_subinterface.test", "tag", fmt.Sprintf("%d", num)),
+					resource.TestCheckResourceAttr("panos_virtual_wire_subinterface.test", "comment", "first"),
+				),
+			},
+			{
+				Config: testAccVirtualWireConfig(name, num, "100-200", false, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "tag_allowed", "100-200"),
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "multicast_firewalling", "false"),
+					resource.TestCheckResourceAttr("panos_virtual_wire.test", "link_state_pass_through", "false"),
+					resource.TestCheckResourceAttr("panos_virtual_wire_subinterface.test", "comment", "second"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosVirtualWireDestroy(s *terraform.State) error {
+	c := rawClient(testAccProvider.Meta())
+
+	for _, rs := range s.RootModule().Resources {
+		var path []string
+		var name string
+		var err error
+
+		switch rs.Type {
+		case "panos_virtual_wire":
+			var tmpl, ts string
+			tmpl, ts, _, name = parseVirtualWireId(rs.Primary.ID)
+			path, err = virtualWireXpath(testAccProvider.Meta(), tmpl, ts)
+		case "panos_virtual_wire_subinterface":
+			var tmpl, ts, iType, eth string
+			tmpl, ts, iType, eth, _, name = parseVirtualWireSubinterfaceId(rs.Primary.ID)
+			path, err = virtualWireSubinterfaceXpath(testAccProvider.Meta(), tmpl, ts, iType, eth)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+		if _, err = c.Get(append(path, util.AsEntryXpath([]string{name})), nil, nil); err == nil {
+			return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccVirtualWireConfig(name string, num int, tags string, flag bool, comment string) string {
+	return fmt.Sprintf(`
+resource "panos_ethernet_interface" "a" {
+    name = "ethernet1/6"
+    mode = "virtual-wire"
+}
+
+resource "panos_ethernet_interface" "b" {
+    name = "ethernet1/7"
+    mode = "virtual-wire"
+}
+
+resource "panos_virtual_wire" "test" {
+    name = %q
+    interface1 = panos_ethernet_interface.a.name
+    interface2 = panos_ethernet_interface.b.name
+    tag_allowed = %q
+    multicast_firewalling = %t
+    link_state_pass_through = %t
+}
+
+resource "panos_virtual_wire_subinterface" "test" {
+    parent_interface = panos_ethernet_interface.a.name
+    name = "${panos_ethernet_interface.a.name}.%d"
+    tag = %d
+    comment = %q
+}
+`, name, tags, flag, flag, num, num, comment)
+}
@@ -0,0 +1,589 @@
+package panos
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+/*
+Both vsys and shared gateways contain the config of everything inside of them
+(zones, objects, rules, etc), so read and update only touch the "display-name"
+and "import" elements (the imported network config, DNS proxy, visible vsys,
+and resource limits), leaving the rest of the config alone.  Delete removes
+the whole entry, along with everything inside of it.
+*/
+
+const (
+	vsysContainer          = "vsys"
+	sharedGatewayContainer = "shared-gateway"
+)
+
+// Resource.
+func resourceVsys() *schema.Resource {
+	return &schema.Resource{
+		Create: createVsys,
+		Read:   readVsys,
+		Update: updateVsys,
+		Delete: deleteVsys,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: vsysEntrySchema(true),
+	}
+}
+
+func createVsys(d *schema.ResourceData, meta interface{}) error {
+	return createVsysEntry(d, meta, vsysContainer)
+}
+
+func readVsys(d *schema.ResourceData, meta interface{}) error {
+	return readVsysEntry(d, meta, vsysContainer)
+}
+
+func updateVsys(d *schema.ResourceData, meta interface{}) error {
+	return updateVsysEntry(d, meta, vsysContainer)
+}
+
+func deleteVsys(d *schema.ResourceData, meta interface{}) error {
+	return deleteVsysEntry(d, meta, vsysContainer)
+}
+
+func resourceSharedGateway() *schema.Resource {
+	return &schema.Resource{
+		Create: createSharedGateway,
+		Read:   readSharedGateway,
+		Update: updateSharedGateway,
+		Delete: deleteSharedGateway,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: vsysEntrySchema(false),
+	}
+}
+
+func createSharedGateway(d *schema.ResourceData, meta interface{}) error {
+	return createVsysEntry(d, meta, sharedGatewayContainer)
+}
+
+func readSharedGateway(d *schema.ResourceData, meta interface{}) error {
+	return readVsysEntry(d, meta, sharedGatewayContainer)
+}
+
+func updateSharedGateway(d *schema.ResourceData, meta interface{}) error {
+	return updateVsysEntry(d, meta, sharedGatewayContainer)
+}
+
+func deleteSharedGateway(d *schema.ResourceData, meta interface{}) error {
+	return deleteVsysEntry(d, meta, sharedGatewayContainer)
+}
+
+func createVsysEntry(d *schema.ResourceData, meta interface{}, kind string) error {
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	name := d.Get("name").(string)
+	o := loadVsysEntry(d, kind)
+
+	path, err := vsysContainerXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	for _, x := range list {
+		if x == name {
+			return fmt.Errorf("%s %q already exists", kind, name)
+		}
+	}
+
+	if _, err = c.Set(path, o, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(buildVsysEntryId(tmpl, ts, name))
+	return readVsysEntry(d, meta, kind)
+}
+
+func readVsysEntry(d *schema.ResourceData, meta interface{}, kind string) error {
+	tmpl, ts, name := parseVsysEntryId(d.Id())
+
+	path, err := vsysContainerXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	list, err := c.EntryListUsing(c.Get, path)
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+	found := false
+	for _, x := range list {
+		if x == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		d.SetId("")
+		return nil
+	}
+
+	o, err := getVsysEntry(c, append(path, util.AsEntryXpath([]string{name})))
+	if err != nil {
+		return err
+	}
+	o.Name = name
+
+	d.Set("template", tmpl)
+	d.Set("template_stack", ts)
+	saveVsysEntry(d, o, kind)
+
+	return nil
+}
+
+func updateVsysEntry(d *schema.ResourceData, meta interface{}, kind string) error {
+	tmpl, ts, name := parseVsysEntryId(d.Id())
+	o := loadVsysEntry(d, kind)
+
+	path, err := vsysContainerXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+	path = append(path, util.AsEntryXpath([]string{name}))
+
+	c := rawClient(meta)
+
+	if d.HasChange("display_name") {
+		if o.DisplayName == "" {
+			_, err = c.Delete(append(path, "display-name"), nil, nil)
+		} else {
+			_, err = c.Edit(append(path, "display-name"), vsysDisplayName{Value: o.DisplayName}, nil, nil)
+		}
+		if err != nil && !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	if o.Import == nil {
+		_, err = c.Delete(append(path, "import"), nil, nil)
+	} else {
+		_, err = c.Edit(append(path, "import"), o.Import, nil, nil)
+	}
+	if err != nil && !isObjectNotFound(err) {
+		return err
+	}
+
+	return readVsysEntry(d, meta, kind)
+}
+
+func deleteVsysEntry(d *schema.ResourceData, meta interface{}, kind string) error {
+	tmpl, ts, name := parseVsysEntryId(d.Id())
+
+	path, err := vsysContainerXpath(meta, tmpl, ts, kind)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	if _, err = c.Delete(append(path, util.AsEntryXpath([]string{name})), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func vsysEntrySchema(isVsys bool) map[string]*schema.Schema {
+	ans := map[string]*schema.Schema{
+		"template": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			Description:   "The template.",
+			ConflictsWith: []string{"template_stack"},
+		},
+		"template_stack": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			Description:   "The template stack.",
+			ConflictsWith: []string{"template"},
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name",
+		},
+		"display_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The display name",
+		},
+		"interfaces": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Imported interfaces",
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"dns_proxy": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The DNS proxy object",
+		},
+	}
+
+	if !isVsys {
+		return ans
+	}
+
+	for _, key := range []string{"virtual_routers", "logical_routers", "virtual_wires", "vlans", "visible_vsys"} {
+		ans[key] = &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+	ans["virtual_routers"].Description = "Imported virtual routers"
+	ans["logical_routers"].Description = "Imported logical routers (PAN-OS 10.0+)"
+	ans["virtual_wires"].Description = "Imported virtual wires"
+	ans["vlans"].Description = "Imported VLANs"
+	ans["visible_vsys"].Description = "Vsys that are visible to this vsys"
+
+	ans["resource_limits"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Resource limits; unspecified or 0 means unlimited",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"max_sessions": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_security_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_nat_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_decryption_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_qos_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_application_override_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_pbf_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_authentication_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_dos_rules": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_site_to_site_vpn_tunnels": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_concurrent_ssl_vpn_tunnels": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+			},
+		},
+	}
+
+	return ans
+}
+
+func loadVsysEntry(d *schema.ResourceData, kind string) vsysEntry {
+	o := vsysEntry{
+		Name:        d.Get("name").(string),
+		DisplayName: d.Get("display_name").(string),
+	}
+
+	imp := vsysImport{
+		DnsProxy: d.Get("dns_proxy").(string),
+	}
+	net := vsysImportNetwork{
+		Interfaces: util.StrToMem(setAsList(d.Get("interfaces").(*schema.Set))),
+	}
+
+	if kind == vsysContainer {
+		net.VirtualRouters = util.StrToMem(setAsList(d.Get("virtual_routers").(*schema.Set)))
+		net.LogicalRouters = util.StrToMem(setAsList(d.Get("logical_routers").(*schema.Set)))
+		net.VirtualWires = util.StrToMem(setAsList(d.Get("virtual_wires").(*schema.Set)))
+		net.Vlans = util.StrToMem(setAsList(d.Get("vlans").(*schema.Set)))
+		imp.VisibleVsys = util.StrToMem(setAsList(d.Get("visible_vsys").(*schema.Set)))
+
+		if v := d.Get("resource_limits").([]interface{}); len(v) != 0 && v[0] != nil {
+			x := v[0].(map[string]interface{})
+			imp.Resource = &vsysResource{
+				MaxSessions:                 x["max_sessions"].(int),
+				MaxSecurityRules:            x["max_security_rules"].(int),
+				MaxNatRules:                 x["max_nat_rules"].(int),
+				MaxDecryptionRules:          x["max_decryption_rules"].(int),
+				MaxQosRules:                 x["max_qos_rules"].(int),
+				MaxApplicationOverrideRules: x["max_application_override_rules"].(int),
+				MaxPbfRules:                 x["max_pbf_rules"].(int),
+				MaxAuthenticationRules:      x["max_authentication_rules"].(int),
+				MaxDosRules:                 x["max_dos_rules"].(int),
+				MaxSiteToSiteVpnTunnels:     x["max_site_to_site_vpn_tunnels"].(int),
+				MaxConcurrentSslVpnTunnels:  x["max_concurrent_ssl_vpn_tunnels"].(int),
+			}
+			if *imp.Resource == (vsysResource{}) {
+				imp.Resource = nil
+			}
+		}
+	}
+
+	if net != (vsysImportNetwork{}) {
+		imp.Network = &net
+	}
+	if imp != (vsysImport{}) {
+		o.Import = &imp
+	}
+
+	return o
+}
+
+func saveVsysEntry(d *schema.ResourceData, o vsysEntry, kind string) {
+	var err error
+	var imp vsysImport
+	var net vsysImportNetwork
+
+	if o.Import != nil {
+		imp = *o.Import
+		if imp.Network != nil {
+			net = *imp.Network
+		}
+	}
+
+	d.Set("name", o.Name)
+	d.Set("display_name", o.DisplayName)
+	d.Set("dns_proxy", imp.DnsProxy)
+	if err = d.Set("interfaces", listAsSet(util.MemToStr(net.Interfaces))); err != nil {
+		log.Printf("[WARN] Error setting 'interfaces' for %q: %s", d.Id(), err)
+	}
+
+	if kind != vsysContainer {
+		return
+	}
+
+	if err = d.Set("virtual_routers", listAsSet(util.MemToStr(net.VirtualRouters))); err != nil {
+		log.Printf("[WARN] Error setting 'virtual_routers' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("logical_routers", listAsSet(util.MemToStr(net.LogicalRouters))); err != nil {
+		log.Printf("[WARN] Error setting 'logical_routers' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("virtual_wires", listAsSet(util.MemToStr(net.VirtualWires))); err != nil {
+		log.Printf("[WARN] Error setting 'virtual_wires' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("vlans", listAsSet(util.MemToStr(net.Vlans))); err != nil {
+		log.Printf("[WARN] Error setting 'vlans' for %q: %s", d.Id(), err)
+	}
+	if err = d.Set("visible_vsys", listAsSet(util.MemToStr(imp.VisibleVsys))); err != nil {
+		log.Printf("[WARN] Error setting 'visible_vsys' for %q: %s", d.Id(), err)
+	}
+
+	var limits []interface{}
+	if r := imp.Resource; r != nil {
+		limits = []interface{}{
+			map[string]interface{}{
+				"max_sessions":                   r.MaxSessions,
+				"max_security_rules":             r.MaxSecurityRules,
+				"max_nat_rules":                  r.MaxNatRules,
+				"max_decryption_rules":           r.MaxDecryptionRules,
+				"max_qos_rules":                  r.MaxQosRules,
+				"max_application_override_rules": r.MaxApplicationOverrideRules,
+				"max_pbf_rules":                  r.MaxPbfRules,
+				"max_authentication_rules":       r.MaxAuthenticationRules,
+				"max_dos_rules":                  r.MaxDosRules,
+				"max_site_to_site_vpn_tunnels":   r.MaxSiteToSiteVpnTunnels,
+				"max_concurrent_ssl_vpn_tunnels": r.MaxConcurrentSslVpnTunnels,
+			},
+		}
+	}
+	if err = d.Set("resource_limits", limits); err != nil {
+		log.Printf("[WARN] Error setting 'resource_limits' for %q: %s", d.Id(), err)
+	}
+}
+
+// Id functions.
+func buildVsysEntryId(a, b, c string) string {
+	return strings.Join([]string{a, b, c}, IdSeparator)
+}
+
+func parseVsysEntryId(v string) (string, string, string) {
+	t := strings.Split(v, IdSeparator)
+	if len(t) != 3 {
+		return "", "", v
+	}
+	return t[0], t[1], t[2]
+}
+
+// vsysContainerXpath returns the xpath of the vsys or shared gateway container.
+func vsysContainerXpath(meta interface{}, tmpl, ts, kind string) ([]string, error) {
+	ans, err := deviceXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == sharedGatewayContainer {
+		ans = append(ans, "network")
+	}
+
+	return append(ans, kind), nil
+}
+
+// deviceXpathPrefix returns the xpath of the device config, which is inside
+// of the template or template stack for Panorama.
+func deviceXpathPrefix(meta interface{}, tmpl, ts string) ([]string, error) {
+	ans, err := templateXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, "config", "devices", util.AsEntryXpath([]string{"localhost.localdomain"})), nil
+}
+
+// vsysXpathPrefix returns the xpath of the vsys config, or the shared config
+// if vsys is "shared", which is inside of the template or template stack for
+// Panorama.
+func vsysXpathPrefix(meta interface{}, tmpl, ts, vsys string) ([]string, error) {
+	ans, err := templateXpathPrefix(meta, tmpl, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ans, util.VsysXpathPrefix(vsys)...), nil
+}
+
+func templateXpathPrefix(meta interface{}, tmpl, ts string) ([]string, error) {
+	if con, ok := meta.(*pango.Panorama); ok && con.Target == "" {
+		if tmpl == "" && ts == "" {
+			return nil, fmt.Errorf("template or template_stack must be specified")
+		}
+		return util.TemplateXpathPrefix(tmpl, ts), nil
+	} else if tmpl != "" || ts != "" {
+		return nil, fmt.Errorf("template and template_stack are only valid for Panorama")
+	}
+
+	return nil, nil
+}
+
+// Config structs.
+type vsysEntry struct {
+	XMLName     xml.Name    `xml:"entry"`
+	Name        string      `xml:"name,attr"`
+	DisplayName string      `xml:"display-name,omitempty"`
+	Import      *vsysImport `xml:"import"`
+}
+
+type vsysDisplayName struct {
+	XMLName xml.Name `xml:"display-name"`
+	Value   string   `xml:",chardata"`
+}
+
+type vsysImport struct {
+	XMLName     xml.Name           `xml:"import"`
+	Network     *vsysImportNetwork `xml:"network"`
+	DnsProxy    string             `xml:"dns-proxy,omitempty"`
+	VisibleVsys *util.MemberType   `xml:"visible-vsys"`
+	Resource    *vsysResource      `xml:"resource"`
+}
+
+type vsysImportNetwork struct {
+	Interfaces     *util.MemberType `xml:"interface"`
+	VirtualRouters *util.MemberType `xml:"virtual-router"`
+	LogicalRouters *util.MemberType `xml:"logical-router"`
+	VirtualWires   *util.MemberType `xml:"virtual-wire"`
+	Vlans          *util.MemberType `xml:"vlan"`
+}
+
+type vsysResource struct {
+	MaxSessions                 int `xml:"max-sessions,omitempty"`
+	MaxSecurityRules            int `xml:"max-security-rules,omitempty"`
+	MaxNatRules                 int `xml:"max-nat-rules,omitempty"`
+	MaxDecryptionRules          int `xml:"max-ssl-decryption-rules,omitempty"`
+	MaxQosRules                 int `xml:"max-qos-rules,omitempty"`
+	MaxApplicationOverrideRules int `xml:"max-application-override-rules,omitempty"`
+	MaxPbfRules                 int `xml:"max-pbf-rules,omitempty"`
+	MaxAuthenticationRules      int `xml:"max-cp-rules,omitempty"`
+	MaxDosRules                 int `xml:"max-dos-rules,omitempty"`
+	MaxSiteToSiteVpnTunnels     int `xml:"max-site-to-site-vpn-tunnels,omitempty"`
+	MaxConcurrentSslVpnTunnels  int `xml:"max-concurrent-ssl-vpn-tunnels,omitempty"`
+}
+
+type vsysEntryAns struct {
+	DisplayName string      `xml:"result>display-name"`
+	Import      *vsysImport `xml:"result>import"`
+}
+
+func getVsysEntry(c *pango.Client, path []string) (vsysEntry, error) {
+	var o vsysEntry
+
+	for _, child := range []string{"display-name", "import"} {
+		var ans vsysEntryAns
+		if _, err := c.Get(append(append([]string{}, path...), child), nil, &ans); err != nil {
+			if isObjectNotFound(err) {
+				continue
+			}
+			return o, err
+		}
+		if ans.DisplayName != "" {
+			o.DisplayName = ans.DisplayName
+		}
+		if ans.Import != nil {
+			o.Import = ans.Import
+		}
+	}
+
+	return o, nil
+}
@@ -0,0 +1,95 @@
+package panos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosVsys(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	vsysName := fmt.Sprintf("vsys%d", acctest.RandIntRange(200, 255))
+	sgName := fmt.Sprintf("sg%d", acctest.RandIntRange(1, 99))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosVsysDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVsysConfig(vsysName, sgName, "first", 1000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_vsys.test", "display_name", "first"),
+					resource.TestCheckResourceAttr("panos_vsys.test", "resource_limits.0.max_sessions", "1000"),
+					resource.TestCheckResourceAttr("panos_vsys.test", "visible_vsys.#", "1"),
+					resource.TestCheckResourceAttr("panos_shared_gateway.test", "display_name", "first"),
+				),
+			},
+			{
+				Config: testAccVsysConfig(vsysName, sgName, "second", 2000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("panos_vsys.test", "display_name", "second"),
+					resource.TestCheckResourceAttr("panos_vsys.test", "resource_limits.0.max_sessions", "2000"),
+					resource.TestCheckResourceAttr("panos_shared_gateway.test", "display_name", "second"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPanosVsysDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		var kind string
+
+		switch rs.Type {
+		case "panos_vsys":
+			kind = vsysContainer
+		case "panos_shared_gateway":
+			kind = sharedGatewayContainer
+		default:
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			tmpl, ts, name := parseVsysEntryId(rs.Primary.ID)
+			path, err := vsysContainerXpath(testAccProvider.Meta(), tmpl, ts, kind)
+			if err != nil {
+				return err
+			}
+			c := rawClient(testAccProvider.Meta())
+			list, _ := c.EntryListUsing(c.Get, path)
+			for _, x := range list {
+				if x == name {
+					return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccVsysConfig(vsysName, sgName, displayName string, sessions int) string {
+	return fmt.Sprintf(`
+resource "panos_vsys" "test" {
+    name = %q
+    display_name = %q
+    visible_vsys = ["vsys1"]
+    resource_limits {
+        max_sessions = %d
+        max_security_rules = 100
+    }
+}
+
+resource "panos_shared_gateway" "test" {
+    name = %q
+    display_name = %q
+}
+`, vsysName, displayName, sessions, sgName, displayName)
+}
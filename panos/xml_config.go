@@ -0,0 +1,428 @@
+package panos
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/util"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// Data source.
+func dataSourceXmlConfig() *schema.Resource {
+	s := xmlConfigSchema(false)
+	s["exists"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "If the xpath exists",
+	}
+
+	return &schema.Resource{
+		Read: dataSourceXmlConfigRead,
+
+		Schema: s,
+	}
+}
+
+func dataSourceXmlConfigRead(d *schema.ResourceData, meta interface{}) error {
+	path, err := xmlConfigXpath(d, meta)
+	if err != nil {
+		return err
+	}
+
+	c := rawClient(meta)
+	config, err := getXmlConfig(c, path)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(path)
+	d.Set("full_xpath", path)
+	d.Set("exists", config != "")
+	d.Set("config", config)
+
+	return nil
+}
+
+// Resource.
+func resourceXmlConfig() *schema.Resource {
+	return &schema.Resource{
+		Create: createXmlConfig,
+		Read:   readXmlConfig,
+		Update: updateXmlConfig,
+		Delete: deleteXmlConfig,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: xmlConfigSchema(true),
+	}
+}
+
+func createXmlConfig(d *schema.ResourceData, meta interface{}) error {
+	path, err := xmlConfigXpath(d, meta)
+	if err != nil {
+		return err
+	}
+	config := d.Get("config").(string)
+
+	c := rawClient(meta)
+	cur, err := getXmlConfig(c, path)
+	if err != nil {
+		return err
+	}
+
+	// Deleting this resource deletes the element, so don't take over config
+	// that is already there unless it is imported.
+	if cur != "" {
+		return fmt.Errorf("The config at %q already exists, import it instead", path)
+	}
+
+	parent, _ := splitXpath(path)
+	if _, err = c.Set(parent, config, nil, nil); err != nil {
+		return err
+	}
+
+	d.SetId(path)
+	if cur, err = getXmlConfig(c, path); err != nil {
+		return err
+	} else if cur == "" {
+		return fmt.Errorf("The config was applied, but %q does not exist; the config's top level element must match the last element of the xpath", path)
+	}
+
+	return readXmlConfig(d, meta)
+}
+
+func readXmlConfig(d *schema.ResourceData, meta interface{}) error {
+	path := d.Id()
+
+	c := rawClient(meta)
+	config, err := getXmlConfig(c, path)
+	if err != nil {
+		return err
+	}
+
+	if config == "" {
+		d.SetId("")
+		return nil
+	}
+
+	// Imported resources only have the ID, so treat it as an absolute xpath.
+	if d.Get("xpath").(string) == "" {
+		d.Set("xpath", path)
+	}
+	d.Set("full_xpath", path)
+	d.Set("config", config)
+
+	return nil
+}
+
+func updateXmlConfig(d *schema.ResourceData, meta interface{}) error {
+	c := rawClient(meta)
+	if _, err := c.Edit(d.Id(), d.Get("config").(string), nil, nil); err != nil {
+		return err
+	}
+
+	return readXmlConfig(d, meta)
+}
+
+func deleteXmlConfig(d *schema.ResourceData, meta interface{}) error {
+	c := rawClient(meta)
+	if _, err := c.Delete(d.Id(), nil, nil); err != nil {
+		if !isObjectNotFound(err) {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// Schema functions.
+func xmlConfigSchema(isResource bool) map[string]*schema.Schema {
+	ans := map[string]*schema.Schema{
+		"xpath": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The xpath; this is relative to the vsys, template, or device group if specified, otherwise absolute",
+		},
+		"vsys": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "The vsys the xpath is relative to (\"shared\" for the shared config)",
+		},
+		"template": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			Description:   "(Panorama) The template the xpath is relative to",
+			ConflictsWith: []string{"template_stack", "device_group"},
+		},
+		"template_stack": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			Description:   "(Panorama) The template stack the xpath is relative to",
+			ConflictsWith: []string{"template", "device_group"},
+		},
+		"device_group": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			Description:   "(Panorama) The device group the xpath is relative to (\"shared\" for the shared config)",
+			ConflictsWith: []string{"template", "template_stack", "vsys"},
+		},
+		"config": {
+			Type:             schema.TypeString,
+			Required:         true,
+			Description:      "The XML element at the xpath",
+			DiffSuppressFunc: xmlConfigDiffSuppress,
+		},
+		"full_xpath": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The absolute xpath",
+		},
+	}
+
+	if !isResource {
+		ans["config"].Required = false
+		ans["config"].Computed = true
+		ans["config"].DiffSuppressFunc = nil
+	}
+
+	return ans
+}
+
+func xmlConfigDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	a, err := normalizeXml(old)
+	if err != nil {
+		return false
+	}
+	b, err := normalizeXml(new)
+	if err != nil {
+		return false
+	}
+
+	return a == b
+}
+
+// rawClient returns the client for config that pango has no namespace for.
+// If the provider has a Panorama "target", then the client sends requests to
+// that firewall.
+func rawClient(meta interface{}) *pango.Client {
+	switch con := meta.(type) {
+	case *pango.Firewall:
+		return &con.Client
+	case *pango.Panorama:
+		return &con.Client
+	}
+
+	return nil
+}
+
+// xmlConfigXpath returns the absolute xpath, applying the scoping params.
+func xmlConfigXpath(d *schema.ResourceData, meta interface{}) (string, error) {
+	var prefix []string
+
+	path := d.Get("xpath").(string)
+	vsys := d.Get("vsys").(string)
+	tmpl := d.Get("template").(string)
+	ts := d.Get("template_stack").(string)
+	dg := d.Get("device_group").(string)
+
+	isPanorama := false
+	if con, ok := meta.(*pango.Panorama); ok && con.Target == "" {
+		isPanorama = true
+	}
+
+	switch {
+	case tmpl != "" || ts != "":
+		if !isPanorama {
+			return "", fmt.Errorf("template and template_stack are only valid for Panorama")
+		}
+		prefix = util.TemplateXpathPrefix(tmpl, ts)
+		if vsys != "" {
+			prefix = append(prefix, util.VsysXpathPrefix(vsys)...)
+		} else {
+			prefix = append(prefix, "config", "devices", util.AsEntryXpath([]string{"localhost.localdomain"}))
+		}
+	case dg != "":
+		if !isPanorama {
+			return "", fmt.Errorf("device_group is only valid for Panorama")
+		}
+		prefix = util.DeviceGroupXpathPrefix(dg)
+	case vsys != "":
+		if isPanorama {
+			return "", fmt.Errorf("vsys for Panorama requires either template or template_stack")
+		}
+		prefix = util.VsysXpathPrefix(vsys)
+	}
+
+	if len(prefix) == 0 {
+		if !strings.HasPrefix(path, "/") {
+			return "", fmt.Errorf("xpath %q must be absolute if no vsys, template, or device group is specified", path)
+		}
+		return path, nil
+	}
+
+	return util.AsXpath(prefix) + "/" + strings.TrimPrefix(path, "/"), nil
+}
+
+// splitXpath splits the xpath into its parent and last element, ignoring any
+// slashes in predicates, such as entry[@name='ethernet1/1'].
+func splitXpath(path string) (string, string) {
+	var depth int
+	var quote rune
+	idx := -1
+
+	for i, ch := range path {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '[':
+			depth++
+		case ch == ']':
+			depth--
+		case ch == '/' && depth == 0:
+			idx = i
+		}
+	}
+
+	if idx == -1 {
+		return "", path
+	}
+
+	return path[:idx], path[idx+1:]
+}
+
+// Operational state.
+type xmlConfigAns struct {
+	Result struct {
+		Text string `xml:",innerxml"`
+	} `xml:"result"`
+}
+
+// getXmlConfig returns the normalized candidate config at the xpath, or an
+// empty string if it does not exist.
+func getXmlConfig(c *pango.Client, path string) (string, error) {
+	var ans xmlConfigAns
+
+	if _, err := c.Get(path, nil, &ans); err != nil {
+		if isObjectNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return normalizeXml(util.CleanRawXml(ans.Result.Text))
+}
+
+// These attributes are added by PAN-OS and are not user config.
+var xmlConfigIgnoredAttrs = map[string]bool{
+	"admin":   true,
+	"dirtyId": true,
+	"time":    true,
+	"uuid":    true,
+}
+
+type xmlConfigNode struct {
+	Name     string
+	Attrs    []xml.Attr
+	Text     string
+	Children []*xmlConfigNode
+}
+
+// normalizeXml returns a canonical form of the given XML: whitespace
+// between elements is removed, attributes are sorted, PAN-OS metadata
+// attributes are removed, and empty elements are self-closing.
+func normalizeXml(v string) (string, error) {
+	var stack []*xmlConfigNode
+	var top []*xmlConfigNode
+
+	dec := xml.NewDecoder(strings.NewReader(v))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &xmlConfigNode{Name: t.Name.Local}
+			for _, attr := range t.Attr {
+				if !xmlConfigIgnoredAttrs[attr.Name.Local] {
+					node.Attrs = append(node.Attrs, attr)
+				}
+			}
+			sort.Slice(node.Attrs, func(i, j int) bool {
+				return node.Attrs[i].Name.Local < node.Attrs[j].Name.Local
+			})
+			if len(stack) == 0 {
+				top = append(top, node)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) != 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, node := range top {
+		node.write(&buf)
+	}
+
+	return buf.String(), nil
+}
+
+func (o *xmlConfigNode) write(buf *bytes.Buffer) {
+	buf.WriteString("<")
+	buf.WriteString(o.Name)
+	for _, attr := range o.Attrs {
+		buf.WriteString(" ")
+		buf.WriteString(attr.Name.Local)
+		buf.WriteString(`="`)
+		xml.EscapeText(buf, []byte(attr.Value))
+		buf.WriteString(`"`)
+	}
+
+	text := strings.TrimSpace(o.Text)
+	if len(o.Children) == 0 && text == "" {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">")
+
+	if len(o.Children) == 0 {
+		xml.EscapeText(buf, []byte(text))
+	}
+	for _, child := range o.Children {
+		child.write(buf)
+	}
+
+	buf.WriteString("</")
+	buf.WriteString(o.Name)
+	buf.WriteString(">")
+}
@@ -0,0 +1,162 @@
+package panos
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/fpluchorg/pango"
+	"github.com/fpluchorg/pango/objs/addr"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestAccPanosDsXmlConfig(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDsXmlConfigConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.panos_xml_config.test", "exists", "true"),
+					resource.TestCheckResourceAttr("data.panos_xml_config.test", "config", fmt.Sprintf(`<entry name="%s"><ip-netmask>10.1.1.1</ip-netmask></entry>`, name)),
+				),
+			},
+		},
+	})
+}
+
+func testAccDsXmlConfigConfig(name string) string {
+	return fmt.Sprintf(`
+data "panos_xml_config" "test" {
+    vsys = "vsys1"
+    xpath = "address/entry[@name='${panos_address_object.x.name}']"
+}
+
+resource "panos_address_object" "x" {
+    name = %q
+    value = "10.1.1.1"
+}
+`, name)
+}
+
+func TestAccPanosXmlConfig(t *testing.T) {
+	if !testAccIsFirewall {
+		t.Skip(SkipFirewallAccTest)
+	}
+
+	var o addr.Entry
+	name := fmt.Sprintf("tf%s", acctest.RandString(6))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccPanosXmlConfigDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccXmlConfigConfig(name, "10.1.1.1", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosXmlConfigExists(name, &o),
+					testAccCheckPanosXmlConfigAttributes(&o, "10.1.1.1", "first"),
+				),
+			},
+			{
+				Config: testAccXmlConfigConfig(name, "10.2.2.2", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPanosXmlConfigExists(name, &o),
+					testAccCheckPanosXmlConfigAttributes(&o, "10.2.2.2", "second"),
+				),
+			},
+			{
+				Config:      testAccXmlConfigDuplicateConfig(name, "10.2.2.2", "second"),
+				ExpectError: regexp.MustCompile("already exists, import it instead"),
+			},
+		},
+	})
+}
+
+func testAccCheckPanosXmlConfigExists(name string, o *addr.Entry) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		fw := testAccProvider.Meta().(*pango.Firewall)
+		v, err := fw.Objects.Address.Get("vsys1", name)
+		if err != nil {
+			return fmt.Errorf("Error in get: %s", err)
+		}
+
+		*o = v
+
+		return nil
+	}
+}
+
+func testAccCheckPanosXmlConfigAttributes(o *addr.Entry, value, desc string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if o.Value != value {
+			return fmt.Errorf("Value is %q, expected %q", o.Value, value)
+		}
+
+		if o.Description != desc {
+			return fmt.Errorf("Description is %q, expected %q", o.Description, desc)
+		}
+
+		return nil
+	}
+}
+
+func testAccPanosXmlConfigDestroy(s *terraform.State) error {
+	fw := testAccProvider.Meta().(*pango.Firewall)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "panos_xml_config" {
+			continue
+		}
+
+		if rs.Primary.ID != "" {
+			config, err := getXmlConfig(&fw.Client, rs.Primary.ID)
+			if err != nil {
+				return err
+			} else if config != "" {
+				return fmt.Errorf("Object %q still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func testAccXmlConfigConfig(name, value, desc string) string {
+	return fmt.Sprintf(`
+resource "panos_xml_config" "test" {
+    vsys = "vsys1"
+    xpath = "address/entry[@name='%s']"
+    config = <<EOT
+<entry name="%s">
+    <ip-netmask>%s</ip-netmask>
+    <description>%s</description>
+</entry>
+EOT
+}
+`, name, name, value, desc)
+}
+
+func testAccXmlConfigDuplicateConfig(name, value, desc string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "panos_xml_config" "dup" {
+    vsys = "vsys1"
+    xpath = panos_xml_config.test.xpath
+    config = panos_xml_config.test.config
+}
+`, testAccXmlConfigConfig(name, value, desc))
+}